@@ -0,0 +1,208 @@
+package bearchive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"testing/fstest"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+// Zip reads a zip archive and runs the matchers on it as a file system, so the befs
+// matchers apply to its entries. befs.FileMode checks the mode recorded in an entry's header.
+//
+//	bearchive.Zip(befs.FileNamed("bin/tool"), befs.FileMode("bin/tool", be.Eq(fs.FileMode(0o755))))
+func Zip(matchers ...expect.Matcher[fs.FS]) expect.Matcher[io.Reader] {
+	return archive("zip archive", openZip, matchers)
+}
+
+// Tar reads an uncompressed tar archive and runs the matchers on it as a file system.
+func Tar(matchers ...expect.Matcher[fs.FS]) expect.Matcher[io.Reader] {
+	return archive("tar archive", func(data []byte) (fs.FS, error) {
+		return openTar(bytes.NewReader(data))
+	}, matchers)
+}
+
+// TarGz reads a gzip-compressed tar archive and runs the matchers on it as a file system.
+func TarGz(matchers ...expect.Matcher[fs.FS]) expect.Matcher[io.Reader] {
+	return archive("tar.gz archive", func(data []byte) (fs.FS, error) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return openTar(zr)
+	}, matchers)
+}
+
+// Bytes runs the matcher on a reader of the bytes, for archives already held in memory.
+//
+//	expect.It(t, archiveBytes).To(bearchive.Bytes(bearchive.Zip(befs.FileNamed("README.md"))))
+func Bytes(matcher expect.Matcher[io.Reader]) expect.Matcher[[]byte] {
+	return func(data []byte) expect.MatchResult {
+		return matcher(bytes.NewReader(data))
+	}
+}
+
+// EntryCount checks the number of entries in the archive, including the directories it
+// records, meets the matcher's criteria.
+func EntryCount(matcher expect.Matcher[int]) expect.Matcher[fs.FS] {
+	return func(fileSystem fs.FS) expect.MatchResult {
+		var count int
+		switch a := fileSystem.(type) {
+		case *zip.Reader:
+			count = len(a.File)
+		case *tarFS:
+			count = len(a.headers)
+		default:
+			return notAnArchive("have a number of entries", "an archive", fileSystem)
+		}
+
+		result := matcher(count)
+		result = result.WithDescription("have a number of entries " + be.Complement(result))
+		result.SubjectName = "the archive"
+		return result
+	}
+}
+
+// Compressed checks the named entry of a zip archive uses the compression method, such as
+// zip.Store or zip.Deflate.
+func Compressed(name string, method uint16) expect.Matcher[fs.FS] {
+	return func(fileSystem fs.FS) expect.MatchResult {
+		description := fmt.Sprintf("have %q compressed with %s", name, methodName(method))
+		r, ok := fileSystem.(*zip.Reader)
+		if !ok {
+			return notAnArchive(description, "a zip archive", fileSystem)
+		}
+
+		result := expect.MatchResult{
+			Description: description,
+			Matches:     false,
+			SubjectName: "zip archive",
+		}
+		for _, f := range r.File {
+			if path.Clean(f.Name) != path.Clean(name) {
+				continue
+			}
+			result.Matches = f.Method == method
+			result.But = fmt.Sprintf("it was compressed with %s", methodName(f.Method))
+			return result
+		}
+		result.But = "it had no such entry"
+		return result
+	}
+}
+
+func methodName(method uint16) string {
+	switch method {
+	case zip.Store:
+		return "store"
+	case zip.Deflate:
+		return "deflate"
+	default:
+		return fmt.Sprintf("method %d", method)
+	}
+}
+
+func notAnArchive(description, kind string, fileSystem fs.FS) expect.MatchResult {
+	return expect.MatchResult{
+		Description: description,
+		Matches:     false,
+		But:         fmt.Sprintf("it was a %T, not %s", fileSystem, kind),
+		SubjectName: "the file system",
+	}
+}
+
+func archive(kind string, open func([]byte) (fs.FS, error), matchers []expect.Matcher[fs.FS]) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		failed := expect.MatchResult{
+			Description: "be a valid " + kind,
+			Matches:     false,
+			SubjectName: kind,
+		}
+
+		data, err := io.ReadAll(rdr)
+		if err != nil {
+			failed.But = fmt.Sprintf("it could not be read: %v", err)
+			return failed
+		}
+		fileSystem, err := open(data)
+		if err != nil {
+			failed.But = fmt.Sprintf("it could not be opened: %v", err)
+			return failed
+		}
+
+		var combined expect.MatchResult
+		for _, matcher := range matchers {
+			if result := matcher(fileSystem); !result.Matches {
+				combined = combined.Combine(result)
+			}
+		}
+		if combined.Zero() {
+			return expect.MatchResult{Description: failed.Description, Matches: true, SubjectName: kind}
+		}
+		if combined.SubjectName == "" {
+			combined.SubjectName = kind
+		}
+		return combined
+	}
+}
+
+func openZip(data []byte) (fs.FS, error) {
+	return zip.NewReader(bytes.NewReader(data), int64(len(data)))
+}
+
+// tarFS holds the entries of a tar archive in memory, keeping their headers so they can be
+// inspected through Stat's Sys.
+type tarFS struct {
+	fstest.MapFS
+	headers []*tar.Header
+}
+
+func openTar(rdr io.Reader) (fs.FS, error) {
+	t := &tarFS{MapFS: fstest.MapFS{}}
+	tr := tar.NewReader(rdr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return t, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean(hdr.Name)
+		if !fs.ValidPath(name) || name == "." {
+			return nil, fmt.Errorf("the entry %q has an invalid name", hdr.Name)
+		}
+		t.headers = append(t.headers, hdr)
+
+		file := &fstest.MapFile{Mode: hdr.FileInfo().Mode(), ModTime: hdr.ModTime, Sys: hdr}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			file.Mode |= fs.ModeDir
+		case tar.TypeSymlink:
+			file.Data = []byte(hdr.Linkname)
+		case tar.TypeLink:
+			target, ok := t.MapFS[path.Clean(hdr.Linkname)]
+			if !ok {
+				return nil, fmt.Errorf("the entry %q links to %q, which is not earlier in the archive", hdr.Name, hdr.Linkname)
+			}
+			file.Data = target.Data
+			file.Mode = target.Mode
+		default:
+			if file.Data, err = io.ReadAll(tr); err != nil {
+				return nil, err
+			}
+		}
+		t.MapFS[name] = file
+	}
+}
@@ -0,0 +1,177 @@
+package bearchive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/bearchive"
+	"github.com/jsteenb2/expect/be/befs"
+	"github.com/jsteenb2/expect/be/beio"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+type entry struct {
+	name   string
+	data   string
+	mode   fs.FileMode
+	method uint16
+	link   string
+}
+
+var release = []entry{
+	{name: "todos/", mode: fs.ModeDir | 0o755},
+	{name: "todos/bin/todos", data: "#!/bin/sh\necho todos\n", mode: 0o755, method: zip.Deflate},
+	{name: "todos/README.md", data: "# todos\n", mode: 0o644, method: zip.Store},
+	{name: "todos/LICENSE", data: "MIT\n", mode: 0o644, method: zip.Deflate},
+}
+
+func newZip(t expect.TB, entries []entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name, Method: e.method, Modified: time.Date(2024, 5, 27, 7, 32, 0, 0, time.UTC)}
+		hdr.SetMode(e.mode)
+		w, err := zw.CreateHeader(hdr)
+		expect.NoError(t, err)
+		_, err = io.WriteString(w, e.data)
+		expect.NoError(t, err)
+	}
+	expect.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func newTar(t expect.TB, entries []entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: int64(e.mode.Perm()), Size: int64(len(e.data)), Typeflag: tar.TypeReg}
+		switch {
+		case e.mode.IsDir():
+			hdr.Typeflag = tar.TypeDir
+		case e.mode&fs.ModeSymlink != 0:
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, e.link, 0
+		}
+		expect.NoError(t, tw.WriteHeader(hdr))
+		_, err := io.WriteString(tw, e.data)
+		expect.NoError(t, err)
+	}
+	expect.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+func newTarGz(t expect.TB, entries []entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(newTar(t, entries))
+	expect.NoError(t, err)
+	expect.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func ExampleZip() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, bytes.NewReader(newZip(t, release))).To(bearchive.Zip(
+		befs.FileNamed("todos/README.md", beio.String(be.Eq("# todos\n"))),
+		befs.FileMode("todos/bin/todos", be.Eq(fs.FileMode(0o755))),
+		bearchive.EntryCount(be.Eq(4)),
+		bearchive.Compressed("todos/README.md", zip.Store),
+	))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleTarGz() {
+	t := &expect.SpyTB{}
+
+	expect.It(t, newTarGz(t, release)).To(bearchive.Bytes(bearchive.TarGz(
+		befs.Dir("todos/bin"),
+		befs.FileNamed("todos/CHANGELOG.md"),
+	)))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected file system to have file called "todos/CHANGELOG.md", but it did not]
+}
+
+func TestZip(t *testing.T) {
+	archive := newZip(t, release)
+
+	expect.It(t, archive).To(bearchive.Bytes(bearchive.Zip(
+		befs.DirExactly("todos", "LICENSE", "README.md", "bin"),
+		befs.FileNamed("todos/bin/todos", beio.String(be.Substring("echo todos"))),
+		befs.FileMode("todos/README.md", be.Eq(fs.FileMode(0o644))),
+		bearchive.Compressed("todos/bin/todos", zip.Deflate),
+	)))
+
+	spytb.VerifyFailingMatcher(t, archive, bearchive.Bytes(bearchive.Zip(bearchive.Compressed("todos/LICENSE", zip.Store))),
+		`expected zip archive to have "todos/LICENSE" compressed with store, but it was compressed with deflate`,
+	)
+	spytb.VerifyFailingMatcher(t, archive, bearchive.Bytes(bearchive.Zip(bearchive.Compressed("todos/NOTICE", zip.Store))),
+		`expected zip archive to have "todos/NOTICE" compressed with store, but it had no such entry`,
+	)
+	spytb.VerifyFailingMatcher(t, archive, bearchive.Bytes(bearchive.Zip(bearchive.EntryCount(be.Eq(3)))),
+		`expected the archive to have a number of entries equal to 3, but it was 4`,
+	)
+	spytb.VerifyFailingMatcher(t, archive, bearchive.Bytes(bearchive.Zip(
+		befs.FileMode("todos/README.md", be.Eq(fs.FileMode(0o600))),
+		befs.FileNamed("todos/README.md"),
+		befs.FileNamed("todos/NOTICE"),
+	)),
+		`expected file system to have file called "todos/README.md" with mode equal to -rw------- and have file called "todos/NOTICE", but it was -rw-r--r-- and it did not`,
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader("not a zip"), bearchive.Zip(),
+		`expected zip archive to be a valid zip archive, but it could not be opened: zip: not a valid zip file`,
+	)
+}
+
+func TestTar(t *testing.T) {
+	entries := append(release[:len(release):len(release)], entry{name: "todos/bin/t", mode: fs.ModeSymlink | 0o777, link: "todos"})
+
+	for name, tt := range map[string]struct {
+		archive []byte
+		matcher func(...expect.Matcher[fs.FS]) expect.Matcher[io.Reader]
+	}{
+		"tar":    {archive: newTar(t, entries), matcher: bearchive.Tar},
+		"tar.gz": {archive: newTarGz(t, entries), matcher: bearchive.TarGz},
+	} {
+		t.Run(name, func(t *testing.T) {
+			expect.It(t, tt.archive).To(bearchive.Bytes(tt.matcher(
+				befs.DirExactly("todos/bin", "t", "todos"),
+				befs.FileNamed("todos/LICENSE", beio.String(be.Eq("MIT\n"))),
+				befs.FileMode("todos/bin/todos", be.Eq(fs.FileMode(0o755))),
+				befs.Symlink("todos/bin/t", "todos"),
+				bearchive.EntryCount(be.Eq(5)),
+			)))
+
+			spytb.VerifyFailingMatcher(t, tt.archive, bearchive.Bytes(tt.matcher(bearchive.Compressed("todos/LICENSE", zip.Store))),
+				`expected the file system to have "todos/LICENSE" compressed with store, but it was a *bearchive.tarFS, not a zip archive`,
+			)
+		})
+	}
+
+	spytb.VerifyFailingMatcher[io.Reader](t, bytes.NewReader(newTar(t, release)), bearchive.TarGz(),
+		`expected tar.gz archive to be a valid tar.gz archive, but it could not be opened: gzip: invalid header`,
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, bytes.NewReader(newTar(t, []entry{{name: "../escape", data: "x"}})), bearchive.Tar(),
+		`expected tar archive to be a valid tar archive, but it could not be opened: the entry "../escape" has an invalid name`,
+	)
+}
+
+func TestEntryCount(t *testing.T) {
+	spytb.VerifyFailingMatcher[fs.FS](t, fstest.MapFS{}, bearchive.EntryCount(be.Eq(1)),
+		`expected the file system to have a number of entries, but it was a fstest.MapFS, not an archive`,
+	)
+}
@@ -0,0 +1,146 @@
+package befs
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	diffContext = 2
+	// maxDiffCells bounds the table used to find the longest common subsequence of lines.
+	maxDiffCells = 4 << 20
+)
+
+type edit struct {
+	op   byte // ' ', '-' or '+'
+	line string
+	// a and b are the line indexes in each text before the edit.
+	a, b int
+}
+
+// lineDiff renders a unified diff of the lines of want and got, or notes their sizes when
+// they are too large to diff.
+func lineDiff(want, got string) string {
+	a, b := splitLines(want), splitLines(got)
+	edits, ok := diffLines(a, b)
+	if !ok {
+		return fmt.Sprintf("too large to diff, it had %d lines, expected %d", len(b), len(a))
+	}
+
+	var sb strings.Builder
+	for _, h := range hunks(edits) {
+		aCount, bCount := 0, 0
+		for _, e := range edits[h[0]:h[1]] {
+			if e.op != '+' {
+				aCount++
+			}
+			if e.op != '-' {
+				bCount++
+			}
+		}
+		first := edits[h[0]]
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", first.a+1, aCount, first.b+1, bCount)
+		for _, e := range edits[h[0]:h[1]] {
+			sb.WriteByte(e.op)
+			// carriage returns are shown so line ending differences are visible
+			sb.WriteString(strings.ReplaceAll(e.line, "\r", `\r`))
+			sb.WriteByte('\n')
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// splitLines splits s after each newline. A last line without one is marked the way
+// diff(1) does, so a missing newline at the end shows up as a change.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(s, "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n\\ No newline at end of file"
+	}
+	return lines
+}
+
+// diffLines finds the edits turning a into b from their longest common subsequence.
+func diffLines(a, b []string) ([]edit, bool) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	am, bm := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if (len(am)+1)*(len(bm)+1) > maxDiffCells {
+		return nil, false
+	}
+
+	// lcs[i][j] is the length of the common subsequence of am[i:] and bm[j:]
+	lcs := make([][]int, len(am)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bm)+1)
+	}
+	for i := len(am) - 1; i >= 0; i-- {
+		for j := len(bm) - 1; j >= 0; j-- {
+			if am[i] == bm[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var edits []edit
+	for i := range prefix {
+		edits = append(edits, edit{op: ' ', line: a[i], a: i, b: i})
+	}
+	i, j := 0, 0
+	for i < len(am) || j < len(bm) {
+		ai, bj := prefix+i, prefix+j
+		switch {
+		case i < len(am) && j < len(bm) && am[i] == bm[j]:
+			edits = append(edits, edit{op: ' ', line: am[i], a: ai, b: bj})
+			i, j = i+1, j+1
+		case i < len(am) && (j == len(bm) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{op: '-', line: am[i], a: ai, b: bj})
+			i++
+		default:
+			edits = append(edits, edit{op: '+', line: bm[j], a: ai, b: bj})
+			j++
+		}
+	}
+	for k := range suffix {
+		edits = append(edits, edit{op: ' ', line: a[len(a)-suffix+k], a: len(a) - suffix + k, b: len(b) - suffix + k})
+	}
+	return edits, true
+}
+
+// hunks groups the changes with their surrounding context, returning [start, end) ranges
+// of edits.
+func hunks(edits []edit) [][2]int {
+	var out [][2]int
+	for i := 0; i < len(edits); i++ {
+		if edits[i].op == ' ' {
+			continue
+		}
+		end := i
+		for j := i; j < len(edits) && j-end <= 2*diffContext; j++ {
+			if edits[j].op != ' ' {
+				end = j
+			}
+		}
+		start, stop := max(0, i-diffContext), min(len(edits), end+diffContext+1)
+		if n := len(out); n > 0 && start <= out[n-1][1] {
+			out[n-1][1] = stop
+		} else {
+			out = append(out, [2]int{start, stop})
+		}
+		i = end
+	}
+	return out
+}
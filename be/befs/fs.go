@@ -1,9 +1,12 @@
 package befs
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"testing/iotest"
 	
 	"github.com/jsteenb2/expect"
 )
@@ -11,38 +14,75 @@ import (
 const fsSubjectName = "file system"
 
 // FileNamed checks if a file exists in the file system, and can run additional matchers on its contents.
+// Each content matcher reads the whole file from the start, and every failing one is reported.
 func FileNamed(name string, contentMatcher ...expect.Matcher[io.Reader]) expect.Matcher[fs.FS] {
 	return func(fileSystem fs.FS) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have file called %q", name),
+			Matches:     false,
+			SubjectName: fsSubjectName,
+		}
+		
 		file, err := fileSystem.Open(name)
 		if err != nil {
-			return expect.MatchResult{
-				Description: fmt.Sprintf("have file called %q", name),
-				Matches:     false,
-				But:         "it did not",
-				SubjectName: fsSubjectName,
-			}
+			result.But = describeErr(err, "open", "opened")
+			return result
 		}
-		
 		defer file.Close()
 		
-		if len(contentMatcher) > 0 {
-			for _, matcher := range contentMatcher {
-				result := matcher(file)
-				result.SubjectName = fmt.Sprintf("file called %q", name)
-				if !result.Matches {
-					if result.But == "" {
-						result.But = "while the file existed, the contents did not match"
-					}
-					return result
+		if info, err := file.Stat(); err == nil && info.IsDir() {
+			result.But = "it was a directory"
+			return result
+		}
+		
+		if len(contentMatcher) == 0 {
+			result.Matches = true
+			return result
+		}
+		
+		// the contents are read once so each matcher gets a fresh reader, and a read error
+		// is replayed to each of them after the data read before it
+		data, readErr := io.ReadAll(file)
+		var failures []expect.MatchResult
+		for _, matcher := range contentMatcher {
+			var reader io.Reader = bytes.NewReader(data)
+			if readErr != nil {
+				reader = io.MultiReader(reader, iotest.ErrReader(readErr))
+			}
+			
+			contentResult := matcher(reader)
+			if !contentResult.Matches {
+				if contentResult.But == "" {
+					contentResult.But = "while the file existed, the contents did not match"
 				}
+				failures = append(failures, contentResult)
 			}
 		}
 		
-		return expect.MatchResult{
-			Description: fmt.Sprintf("have file called %q", name),
-			Matches:     true,
-			SubjectName: fsSubjectName,
+		if len(failures) == 0 {
+			result.Matches = true
+			return result
+		}
+		
+		var combined expect.MatchResult
+		for _, f := range failures {
+			combined = combined.Combine(f)
 		}
+		combined.SubjectName = fmt.Sprintf("file called %q", name)
+		return combined
+	}
+}
+
+// describeErr explains why a file could not be used, given the verb for what was done with
+// it, i.e. describeErr(err, "open", "opened").
+func describeErr(err error, verb, done string) string {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return "it did not"
+	case errors.Is(err, fs.ErrPermission):
+		return fmt.Sprintf("permission to %s it was denied: %v", verb, err)
+	default:
+		return fmt.Sprintf("it could not be %s: %v", done, err)
 	}
 }
 
@@ -24,7 +24,7 @@ func ExampleFileNamed_fail() {
 	expect.It[fs.FS](t, stubFS).To(befs.FileNamed("someFile.txt", beio.String(be.Substring("Pluto"))))
 
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected file called "someFile.txt" to contain "Pluto", but while the file existed, the contents did not match]
+	// Output: Test failed: [expected file called "someFile.txt" to contain "Pluto", but it was "hello world"]
 }
 
 func ExampleFileNamed() {
@@ -153,6 +153,50 @@ func TestFSMatching(t *testing.T) {
 			})
 		})
 	})
+	
+	t.Run("FileNamed with several content matchers", func(t *testing.T) {
+		t.Run("passing", func(t *testing.T) {
+			expect.It[fs.FS](t, stubFS).To(befs.FileNamed("someFile.txt",
+				beio.String(be.Substring("hello")),
+				beio.String(be.Substring("world")),
+				beio.HaveData(beio.ContainingString("hello world")),
+			))
+		})
+		
+		t.Run("failing", func(t *testing.T) {
+			spytb.VerifyFailingMatcher[fs.FS](
+				t,
+				stubFS,
+				befs.FileNamed("someFile.txt",
+					beio.String(be.Substring("goodbye")),
+					beio.String(be.Substring("world")),
+					beio.HaveData(beio.ContainingString("Pluto")),
+				),
+				`expected file called "someFile.txt" to contain "goodbye" and contain "Pluto", but it was "hello world"`,
+			)
+		})
+	})
+	
+	t.Run("FileNamed errors", func(t *testing.T) {
+		spytb.VerifyFailingMatcher[fs.FS](
+			t,
+			stubFS,
+			befs.FileNamed("someDir"),
+			`expected file system to have file called "someDir", but it was a directory`,
+		)
+		spytb.VerifyFailingMatcher[fs.FS](
+			t,
+			FailToOpenFS{Error: fs.ErrPermission},
+			befs.FileNamed("secret.txt"),
+			`expected file system to have file called "secret.txt", but permission to open it was denied: open secret.txt: permission denied`,
+		)
+		spytb.VerifyFailingMatcher[fs.FS](
+			t,
+			FailToOpenFS{Error: fmt.Errorf("disk on fire")},
+			befs.FileNamed("secret.txt"),
+			`expected file system to have file called "secret.txt", but it could not be opened: open secret.txt: disk on fire`,
+		)
+	})
 }
 
 type FailToReadFS struct {
@@ -163,6 +207,14 @@ func (f FailToReadFS) Open(name string) (fs.File, error) {
 	return FailingFile(f), nil
 }
 
+type FailToOpenFS struct {
+	Error error
+}
+
+func (f FailToOpenFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: f.Error}
+}
+
 type FailingFile struct {
 	Error error
 }
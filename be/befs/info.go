@@ -0,0 +1,203 @@
+package befs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+// FileMode checks the mode of the named file, including its type bits, meets the
+// matcher's criteria.
+//
+//	befs.FileMode("bin/run.sh", be.Eq(fs.FileMode(0o755)))
+func FileMode(name string, matcher expect.Matcher[fs.FileMode]) expect.Matcher[fs.FS] {
+	return statMatcher(name, "mode", func(info fs.FileInfo) expect.MatchResult {
+		return matcher(info.Mode())
+	})
+}
+
+// FileSize checks the size in bytes of the named file meets the matcher's criteria.
+func FileSize(name string, matcher expect.Matcher[int64]) expect.Matcher[fs.FS] {
+	return statMatcher(name, "size", func(info fs.FileInfo) expect.MatchResult {
+		return matcher(info.Size())
+	})
+}
+
+// ModTime checks the modification time of the named file meets the matcher's criteria.
+func ModTime(name string, matcher expect.Matcher[time.Time]) expect.Matcher[fs.FS] {
+	return statMatcher(name, "modification time", func(info fs.FileInfo) expect.MatchResult {
+		return matcher(info.ModTime())
+	})
+}
+
+func statMatcher(name, property string, match func(fs.FileInfo) expect.MatchResult) expect.Matcher[fs.FS] {
+	return func(fileSystem fs.FS) expect.MatchResult {
+		info, err := fs.Stat(fileSystem, name)
+		if err != nil {
+			return expect.MatchResult{
+				Description: fmt.Sprintf("have file called %q", name),
+				Matches:     false,
+				But:         describeErr(err, "read", "read"),
+				SubjectName: fsSubjectName,
+			}
+		}
+
+		result := match(info)
+		result = result.WithDescription(fmt.Sprintf("have file called %q with %s %s", name, property, be.Complement(result)))
+		result.SubjectName = fsSubjectName
+		return result
+	}
+}
+
+// DirContaining checks the directory has entries with each of the names, and maybe others.
+func DirContaining(dir string, names ...string) expect.Matcher[fs.FS] {
+	return dirEntries(dir, fmt.Sprintf("have directory called %q containing %q", dir, names), func(entries []string) string {
+		if missing := without(names, entries); len(missing) > 0 {
+			return fmt.Sprintf("it was missing %q; it had %q", missing, entries)
+		}
+		return ""
+	})
+}
+
+// DirExactly checks the directory has entries with each of the names and nothing else.
+// The order of the names does not matter.
+func DirExactly(dir string, names ...string) expect.Matcher[fs.FS] {
+	return dirEntries(dir, fmt.Sprintf("have directory called %q containing exactly %q", dir, names), func(entries []string) string {
+		missing, unexpected := without(names, entries), without(entries, names)
+		switch {
+		case len(missing) > 0 && len(unexpected) > 0:
+			return fmt.Sprintf("it was missing %q and had unexpected %q", missing, unexpected)
+		case len(missing) > 0:
+			return fmt.Sprintf("it was missing %q", missing)
+		case len(unexpected) > 0:
+			return fmt.Sprintf("it had unexpected %q", unexpected)
+		}
+		return ""
+	})
+}
+
+func dirEntries(dir, description string, check func(entries []string) string) expect.Matcher[fs.FS] {
+	return func(fileSystem fs.FS) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: description,
+			Matches:     false,
+			SubjectName: fsSubjectName,
+		}
+
+		entries, err := fs.ReadDir(fileSystem, dir)
+		if err != nil {
+			result.But = describeErr(err, "read", "read")
+			return result
+		}
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+
+		result.But = check(names)
+		result.Matches = result.But == ""
+		return result
+	}
+}
+
+// without returns the names not in others.
+func without(names, others []string) []string {
+	var out []string
+	for _, n := range names {
+		if !slices.Contains(others, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// GlobMatches checks the names matching the pattern, in the syntax of path.Match, meet
+// the matcher's criteria. The names are sorted.
+//
+//	befs.GlobMatches("gen/*.go", be.Size[string](be.Eq(3)))
+func GlobMatches(pattern string, matcher expect.Matcher[[]string]) expect.Matcher[fs.FS] {
+	return func(fileSystem fs.FS) expect.MatchResult {
+		matches, err := fs.Glob(fileSystem, pattern)
+		if err != nil {
+			return expect.MatchResult{
+				Description: fmt.Sprintf("have files matching %q", pattern),
+				Matches:     false,
+				But:         fmt.Sprintf("the pattern is invalid: %v", err),
+				SubjectName: fsSubjectName,
+			}
+		}
+
+		result := matcher(matches)
+		result = result.WithDescription(fmt.Sprintf("have files matching %q %s", pattern, be.Complement(result)))
+		result.SubjectName = fsSubjectName
+		return result
+	}
+}
+
+// Symlink checks the named file is a symbolic link to the target. The file system must
+// implement fs.ReadLinkFS, as os.DirFS and fstest.MapFS do.
+func Symlink(name, target string) expect.Matcher[fs.FS] {
+	return func(fileSystem fs.FS) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have symbolic link called %q to %q", name, target),
+			Matches:     false,
+			SubjectName: fsSubjectName,
+		}
+
+		if _, ok := fileSystem.(fs.ReadLinkFS); !ok {
+			result.But = fmt.Sprintf("the file system, a %T, cannot read symbolic links", fileSystem)
+			return result
+		}
+		info, err := fs.Lstat(fileSystem, name)
+		if err != nil {
+			result.But = describeErr(err, "read", "read")
+			return result
+		}
+		if info.Mode()&fs.ModeSymlink == 0 {
+			result.But = fmt.Sprintf("it was not a symbolic link, its mode was %s", info.Mode())
+			return result
+		}
+		got, err := fs.ReadLink(fileSystem, name)
+		if err != nil {
+			result.But = describeErr(err, "read", "read")
+			return result
+		}
+
+		result.Matches = path.Clean(got) == path.Clean(target)
+		result.But = fmt.Sprintf("it linked to %q", got)
+		return result
+	}
+}
+
+// NotExist checks nothing exists at the name. A symbolic link exists even when its target
+// does not, so long as the file system implements fs.ReadLinkFS.
+func NotExist(name string) expect.Matcher[fs.FS] {
+	return func(fileSystem fs.FS) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("not have anything called %q", name),
+			Matches:     false,
+			SubjectName: fsSubjectName,
+		}
+
+		info, err := fs.Lstat(fileSystem, name)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			result.Matches = true
+		case err != nil:
+			result.But = fmt.Sprintf("it could not be checked: %v", err)
+		case info.Mode()&fs.ModeSymlink != 0:
+			result.But = "it was a symbolic link"
+		case info.IsDir():
+			result.But = "it was a directory"
+		default:
+			result.But = fmt.Sprintf("it was a file of %d bytes", info.Size())
+		}
+		return result
+	}
+}
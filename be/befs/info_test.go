@@ -0,0 +1,193 @@
+package befs_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/befs"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+var generated = time.Date(2024, 5, 27, 7, 32, 0, 0, time.UTC)
+
+func newGeneratedFS() fstest.MapFS {
+	return fstest.MapFS{
+		"gen/models.go":   {Data: []byte("package gen\n"), Mode: 0o644, ModTime: generated},
+		"gen/queries.go":  {Data: []byte("package gen\n\n// queries\n"), Mode: 0o644, ModTime: generated},
+		"gen/README.md":   {Data: []byte("# gen\n"), Mode: 0o600, ModTime: generated},
+		"bin/run.sh":      {Data: []byte("#!/bin/sh\n"), Mode: 0o755},
+		"bin/run":         {Data: []byte("run.sh"), Mode: fs.ModeSymlink},
+		"bin/empty":       {Mode: fs.ModeDir | 0o755},
+		"bin/nested/x.go": {Data: []byte("package nested\n")},
+	}
+}
+
+func ExampleFileMode() {
+	t := &expect.SpyTB{}
+
+	expect.It[fs.FS](t, newGeneratedFS()).To(
+		befs.FileMode("bin/run.sh", be.Eq(fs.FileMode(0o755))),
+		befs.FileMode("gen/README.md", be.Eq(fs.FileMode(0o644))),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected file system to have file called "gen/README.md" with mode equal to -rw-r--r--, but it was -rw-------]
+}
+
+func ExampleDirExactly() {
+	t := &expect.SpyTB{}
+
+	expect.It[fs.FS](t, newGeneratedFS()).To(
+		befs.DirContaining("gen", "models.go"),
+		befs.DirExactly("gen", "models.go", "queries.go"),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected file system to have directory called "gen" containing exactly ["models.go" "queries.go"], but it had unexpected ["README.md"]]
+}
+
+func ExampleGlobMatches() {
+	t := &expect.SpyTB{}
+
+	expect.It[fs.FS](t, newGeneratedFS()).To(befs.GlobMatches("gen/*.go", be.ShallowEq([]string{"gen/models.go", "gen/queries.go"})))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func TestStatMatchers(t *testing.T) {
+	fsys := newGeneratedFS()
+
+	expect.It[fs.FS](t, fsys).To(
+		befs.FileSize("gen/models.go", be.Eq(int64(12))),
+		befs.ModTime("gen/queries.go", be.Eq(generated)),
+		befs.FileMode("bin/empty", be.Eq(fs.ModeDir|0o755)),
+	)
+
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.FileSize("gen/queries.go", be.Eq(int64(12))),
+		`expected file system to have file called "gen/queries.go" with size equal to 12, but it was 24`,
+	)
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.ModTime("bin/run.sh", be.Eq(generated)),
+		`expected file system to have file called "bin/run.sh" with modification time equal to 2024-05-27 07:32:00 +0000 UTC`,
+	)
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.FileSize("gen/missing.go", be.Eq(int64(0))),
+		`expected file system to have file called "gen/missing.go", but it did not`,
+	)
+	spytb.VerifyFailingMatcher[fs.FS](t, FailToReadFS{Error: fmt.Errorf("disk on fire")}, befs.FileMode("x", be.Eq(fs.FileMode(0))),
+		`expected file system to have file called "x", but it could not be read: disk on fire`,
+	)
+	spytb.VerifyFailingMatcher[fs.FS](t, FailToReadFS{Error: fs.ErrPermission}, befs.FileMode("x", be.Eq(fs.FileMode(0))),
+		`expected file system to have file called "x", but permission to read it was denied: permission denied`,
+	)
+}
+
+func TestDirMatchers(t *testing.T) {
+	fsys := newGeneratedFS()
+
+	expect.It[fs.FS](t, fsys).To(
+		befs.DirContaining("bin", "run.sh", "nested"),
+		befs.DirExactly("bin/nested", "x.go"),
+		befs.DirExactly("bin/empty"),
+		befs.DirContaining("."),
+	)
+
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.DirContaining("gen", "models.go", "schema.go"),
+		`expected file system to have directory called "gen" containing ["models.go" "schema.go"], but it was missing ["schema.go"]; it had ["README.md" "models.go" "queries.go"]`,
+	)
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.DirExactly("gen", "models.go", "schema.go", "README.md"),
+		`expected file system to have directory called "gen" containing exactly ["models.go" "schema.go" "README.md"], but it was missing ["schema.go"] and had unexpected ["queries.go"]`,
+	)
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.DirExactly("bin/empty", "x"),
+		`expected file system to have directory called "bin/empty" containing exactly ["x"], but it was missing ["x"]`,
+	)
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.DirContaining("lib", "x"),
+		`expected file system to have directory called "lib" containing ["x"], but it did not`,
+	)
+}
+
+func TestGlobMatches(t *testing.T) {
+	fsys := newGeneratedFS()
+
+	expect.It[fs.FS](t, fsys).To(
+		befs.GlobMatches("*/*.md", be.ShallowEq([]string{"gen/README.md"})),
+		befs.GlobMatches("*/*/*.go", be.Size[string](be.Eq(1))),
+	)
+
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.GlobMatches("gen/*.txt", be.Size[string](be.Greater(0))),
+		`expected file system to have files matching "gen/*.txt" with a size greater than 0, but it was 0`,
+	)
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.GlobMatches("gen/[", be.Size[string](be.Greater(0))),
+		`expected file system to have files matching "gen/[", but the pattern is invalid: syntax error in pattern`,
+	)
+}
+
+func TestSymlink(t *testing.T) {
+	t.Run("map fs", func(t *testing.T) {
+		fsys := newGeneratedFS()
+
+		expect.It[fs.FS](t, fsys).To(befs.Symlink("bin/run", "run.sh"))
+
+		spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.Symlink("bin/run", "start.sh"),
+			`expected file system to have symbolic link called "bin/run" to "start.sh", but it linked to "run.sh"`,
+		)
+		spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.Symlink("bin/run.sh", "x"),
+			`expected file system to have symbolic link called "bin/run.sh" to "x", but it was not a symbolic link, its mode was -rwxr-xr-x`,
+		)
+		spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.Symlink("bin/stop", "x"),
+			`expected file system to have symbolic link called "bin/stop" to "x", but it did not`,
+		)
+		spytb.VerifyFailingMatcher[fs.FS](t, FailToReadFS{}, befs.Symlink("bin/run", "run.sh"),
+			`expected file system to have symbolic link called "bin/run" to "run.sh", but the file system, a befs_test.FailToReadFS, cannot read symbolic links`,
+		)
+	})
+
+	t.Run("dir fs", func(t *testing.T) {
+		dir := t.TempDir()
+		expect.NoError(t, os.WriteFile(filepath.Join(dir, "run.sh"), []byte("#!/bin/sh\n"), 0o755))
+		if err := os.Symlink("run.sh", filepath.Join(dir, "run")); err != nil {
+			t.Skipf("symbolic links are not supported: %v", err)
+		}
+
+		expect.It(t, os.DirFS(dir)).To(
+			befs.Symlink("run", "run.sh"),
+			befs.FileSize("run", be.Eq(int64(10))),
+			befs.DirExactly(".", "run", "run.sh"),
+			befs.NotExist("stop"),
+		)
+	})
+}
+
+func TestNotExist(t *testing.T) {
+	fsys := newGeneratedFS()
+
+	expect.It[fs.FS](t, fsys).To(
+		befs.NotExist("gen/schema.go"),
+		befs.NotExist("lib/x"),
+	)
+
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.NotExist("gen/models.go"),
+		`expected file system to not have anything called "gen/models.go", but it was a file of 12 bytes`,
+	)
+	spytb.VerifyFailingMatcher[fs.FS](t, fsys, befs.NotExist("gen"),
+		`expected file system to not have anything called "gen", but it was a directory`,
+	)
+
+	t.Run("dangling symbolic link", func(t *testing.T) {
+		mapFS := fstest.MapFS{"bin/stop": {Data: []byte("stop.sh"), Mode: fs.ModeSymlink}}
+		spytb.VerifyFailingMatcher[fs.FS](t, mapFS, befs.NotExist("bin/stop"),
+			`expected file system to not have anything called "bin/stop", but it was a symbolic link`,
+		)
+
+		dir := t.TempDir()
+		if err := os.Symlink("stop.sh", filepath.Join(dir, "stop")); err != nil {
+			t.Skipf("symbolic links are not supported: %v", err)
+		}
+		spytb.VerifyFailingMatcher(t, os.DirFS(dir), befs.NotExist("stop"),
+			`expected file system to not have anything called "stop", but it was a symbolic link`,
+		)
+	})
+}
@@ -0,0 +1,338 @@
+package befs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jsteenb2/expect"
+)
+
+// TreeOption configures TreeEqual.
+type TreeOption func(*treeConfig)
+
+type treeConfig struct {
+	ignore          []string
+	normalizeEOL    bool
+	compareModes    bool
+	updateDir       string
+	updateRequested bool
+}
+
+// IgnorePaths skips the paths matching any of the patterns, in the syntax of path.Match,
+// in both trees. A pattern without a slash is matched against base names, so "*.log"
+// ignores log files anywhere, and an ignored directory is skipped entirely.
+func IgnorePaths(patterns ...string) TreeOption {
+	return func(c *treeConfig) {
+		c.ignore = append(c.ignore, patterns...)
+	}
+}
+
+// NormalizeLineEndings treats \r\n and \n as the same when comparing file contents.
+func NormalizeLineEndings() TreeOption {
+	return func(c *treeConfig) {
+		c.normalizeEOL = true
+	}
+}
+
+// CompareModes also compares the permission bits of files.
+func CompareModes() TreeOption {
+	return func(c *treeConfig) {
+		c.compareModes = true
+	}
+}
+
+// UpdateGolden rewrites the golden directory on disk from the tree being matched when
+// update is set, so the match always passes. Pass the directory the expected file system
+// was read from and a flag of your own, i.e.
+//
+//	var update = flag.Bool("update", false, "update the golden files")
+//
+//	expect.It(t, os.DirFS(out)).To(befs.TreeEqual(os.DirFS("testdata/golden"), befs.UpdateGolden("testdata/golden", *update)))
+//
+// Ignored paths in the golden directory are left alone.
+func UpdateGolden(dir string, update bool) TreeOption {
+	return func(c *treeConfig) {
+		c.updateDir = dir
+		c.updateRequested = update
+	}
+}
+
+type treeEntry struct {
+	dir  bool
+	mode fs.FileMode
+}
+
+// TreeEqual walks both file systems and checks they hold the same directories and files
+// with the same contents. Every added, missing and changed file is reported, with a diff of
+// the lines of changed text files.
+func TreeEqual(expected fs.FS, opts ...TreeOption) expect.Matcher[fs.FS] {
+	var config treeConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(fileSystem fs.FS) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: "be equal to the expected tree",
+			Matches:     false,
+			SubjectName: fsSubjectName,
+		}
+
+		got, err := walkTree(fileSystem, config.ignore)
+		if err != nil {
+			result.But = fmt.Sprintf("it could not be read: %v", err)
+			return result
+		}
+
+		if config.updateRequested {
+			if err := updateGolden(config.updateDir, fileSystem, got, config.ignore); err != nil {
+				result.But = fmt.Sprintf("the golden tree could not be updated: %v", err)
+				return result
+			}
+			result.Matches = true
+			return result
+		}
+
+		want, err := walkTree(expected, config.ignore)
+		if err != nil {
+			result.But = fmt.Sprintf("the expected tree could not be read: %v", err)
+			return result
+		}
+
+		differences, err := compareTrees(fileSystem, expected, got, want, config)
+		if err != nil {
+			result.But = fmt.Sprintf("it could not be read: %v", err)
+			return result
+		}
+		if len(differences) == 0 {
+			result.Matches = true
+			return result
+		}
+
+		noun := "difference"
+		if len(differences) > 1 {
+			noun += "s"
+		}
+		result.But = fmt.Sprintf("it had %d %s:\n%s", len(differences), noun, strings.Join(differences, "\n"))
+		return result
+	}
+}
+
+func walkTree(fileSystem fs.FS, ignore []string) (map[string]treeEntry, error) {
+	entries := make(map[string]treeEntry)
+	err := fs.WalkDir(fileSystem, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		if ignored(name, ignore) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries[name] = treeEntry{dir: d.IsDir(), mode: info.Mode()}
+		return nil
+	})
+	return entries, err
+}
+
+func ignored(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		target := name
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(name)
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func compareTrees(gotFS, wantFS fs.FS, got, want map[string]treeEntry, config treeConfig) ([]string, error) {
+	names := make([]string, 0, len(got)+len(want))
+	for name := range got {
+		names = append(names, name)
+	}
+	for name := range want {
+		if _, ok := got[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var (
+		differences []string
+		// skipped holds the directories already reported as added or missing as a whole
+		skipped = make(map[string]bool)
+	)
+	for _, name := range names {
+		if withinAny(name, skipped) {
+			continue
+		}
+		g, inGot := got[name]
+		w, inWant := want[name]
+
+		switch {
+		case !inWant:
+			differences = append(differences, "added "+describeEntry(name, g))
+			if g.dir {
+				skipped[name] = true
+			}
+		case !inGot:
+			differences = append(differences, "missing "+describeEntry(name, w))
+			if w.dir {
+				skipped[name] = true
+			}
+		case g.dir != w.dir:
+			differences = append(differences, fmt.Sprintf("changed %s: it was %s, expected %s", name, kindOf(g), kindOf(w)))
+			skipped[name] = true
+		default:
+			if g.dir {
+				continue
+			}
+			var changes []string
+			if config.compareModes && g.mode.Perm() != w.mode.Perm() {
+				changes = append(changes, fmt.Sprintf("its mode was %s, expected %s", g.mode.Perm(), w.mode.Perm()))
+			}
+			change, err := compareContents(gotFS, wantFS, name, config.normalizeEOL)
+			if err != nil {
+				return nil, err
+			}
+			if change != "" {
+				changes = append(changes, change)
+			}
+			if len(changes) > 0 {
+				differences = append(differences, fmt.Sprintf("changed %s: %s", name, strings.Join(changes, "; ")))
+			}
+		}
+	}
+	return differences, nil
+}
+
+// withinAny reports whether the name is inside one of the directories. Sorted names don't
+// keep a directory's contents together, "a-x" sorts between "a" and "a/b".
+func withinAny(name string, dirs map[string]bool) bool {
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if dirs[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+func describeEntry(name string, e treeEntry) string {
+	if e.dir {
+		return name + "/"
+	}
+	return name
+}
+
+func kindOf(e treeEntry) string {
+	if e.dir {
+		return "a directory"
+	}
+	return "a file"
+}
+
+func compareContents(gotFS, wantFS fs.FS, name string, normalizeEOL bool) (string, error) {
+	got, err := fs.ReadFile(gotFS, name)
+	if err != nil {
+		return "", err
+	}
+	want, err := fs.ReadFile(wantFS, name)
+	if err != nil {
+		return "", err
+	}
+	if normalizeEOL {
+		got = bytes.ReplaceAll(got, []byte("\r\n"), []byte("\n"))
+		want = bytes.ReplaceAll(want, []byte("\r\n"), []byte("\n"))
+	}
+	if bytes.Equal(got, want) {
+		return "", nil
+	}
+
+	if isBinary(got) || isBinary(want) {
+		return fmt.Sprintf("the binary contents differed, it had %d bytes, expected %d", len(got), len(want)), nil
+	}
+	diff := lineDiff(string(want), string(got))
+	return "the contents differed\n" + indent(diff), nil
+}
+
+func isBinary(b []byte) bool {
+	return bytes.IndexByte(b, 0) != -1 || !utf8.Valid(b)
+}
+
+func indent(s string) string {
+	return "  " + strings.ReplaceAll(s, "\n", "\n  ")
+}
+
+// updateGolden makes dir hold the entries of the tree, leaving ignored paths alone.
+func updateGolden(dir string, fileSystem fs.FS, entries map[string]treeEntry, ignore []string) error {
+	if dir == "" {
+		return errors.New("no golden directory was given")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	existing, err := walkTree(os.DirFS(dir), ignore)
+	if err != nil {
+		return err
+	}
+	stale := make([]string, 0, len(existing))
+	for name, e := range existing {
+		if g, ok := entries[name]; !ok || g.dir != e.dir {
+			stale = append(stale, name)
+		}
+	}
+	// remove the deepest paths first so directories are empty when they are removed
+	slices.SortFunc(stale, func(a, b string) int { return strings.Compare(b, a) })
+	for _, name := range stale {
+		if err := os.RemoveAll(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		e, target := entries[name], filepath.Join(dir, filepath.FromSlash(name))
+		if e.dir {
+			if err := os.MkdirAll(target, e.mode.Perm()|0o700); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := fs.ReadFile(fileSystem, name)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, e.mode.Perm()|0o600); err != nil {
+			return err
+		}
+		if err := os.Chmod(target, e.mode.Perm()|0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package befs_test
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/befs"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func newGoldenFS() fstest.MapFS {
+	return fstest.MapFS{
+		"main.go":        {Data: []byte("package main\n\nfunc main() {\n\tconfig := load()\n\trun(config)\n}\n"), Mode: 0o644},
+		"gen/models.go":  {Data: []byte("package gen\n"), Mode: 0o644},
+		"gen/queries.go": {Data: []byte("package gen\n\n// queries\n"), Mode: 0o644},
+		"run.sh":         {Data: []byte("#!/bin/sh\n"), Mode: 0o755},
+	}
+}
+
+func ExampleTreeEqual() {
+	t := &expect.SpyTB{}
+
+	generated := newGoldenFS()
+	generated["main.go"] = &fstest.MapFile{Data: []byte("package main\n\nfunc main() {\n\tconfig := load()\n\tstart(config)\n}\n"), Mode: 0o644}
+	generated["gen/schema.go"] = &fstest.MapFile{Data: []byte("package gen\n")}
+	delete(generated, "gen/queries.go")
+
+	expect.It[fs.FS](t, generated).To(befs.TreeEqual(newGoldenFS()))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected file system to be equal to the expected tree, but it had 3 differences:
+	// missing gen/queries.go
+	// added gen/schema.go
+	// changed main.go: the contents differed
+	//   @@ -3,4 +3,4 @@
+	//    func main() {
+	//    	config := load()
+	//   -	run(config)
+	//   +	start(config)
+	//    }]
+}
+
+func ExampleIgnorePaths() {
+	t := &expect.SpyTB{}
+
+	generated := newGoldenFS()
+	generated["gen/models.go.orig"] = &fstest.MapFile{Data: []byte("package gen\n")}
+	generated["tmp/cache"] = &fstest.MapFile{Data: []byte("x")}
+
+	expect.It[fs.FS](t, generated).To(befs.TreeEqual(newGoldenFS(), befs.IgnorePaths("*.orig", "tmp")))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func TestTreeEqual(t *testing.T) {
+	t.Run("passes for the same tree", func(t *testing.T) {
+		expect.It[fs.FS](t, newGoldenFS()).To(befs.TreeEqual(newGoldenFS(), befs.CompareModes()))
+	})
+
+	t.Run("reports a missing or added directory once", func(t *testing.T) {
+		generated := newGoldenFS()
+		delete(generated, "gen/models.go")
+		delete(generated, "gen/queries.go")
+		generated["lib/a.go"] = &fstest.MapFile{Data: []byte("package lib\n")}
+		generated["lib/b.go"] = &fstest.MapFile{Data: []byte("package lib\n")}
+
+		spytb.VerifyFailingMatcher[fs.FS](t, generated, befs.TreeEqual(newGoldenFS()),
+			"expected file system to be equal to the expected tree, but it had 2 differences:\nmissing gen/\nadded lib/",
+		)
+	})
+
+	t.Run("reports missing directories once when their names interleave", func(t *testing.T) {
+		want := newGoldenFS()
+		want["gen-x/a.go"] = &fstest.MapFile{Data: []byte("package genx\n")}
+
+		generated := newGoldenFS()
+		delete(generated, "gen/models.go")
+		delete(generated, "gen/queries.go")
+
+		spytb.VerifyFailingMatcher[fs.FS](t, generated, befs.TreeEqual(want),
+			"expected file system to be equal to the expected tree, but it had 2 differences:\nmissing gen/\nmissing gen-x/",
+		)
+	})
+
+	t.Run("reports a file replaced by a directory", func(t *testing.T) {
+		generated := newGoldenFS()
+		delete(generated, "run.sh")
+		generated["run.sh/x"] = &fstest.MapFile{Data: []byte("x")}
+
+		spytb.VerifyFailingMatcher[fs.FS](t, generated, befs.TreeEqual(newGoldenFS()),
+			"expected file system to be equal to the expected tree, but it had 1 difference:\nchanged run.sh: it was a directory, expected a file",
+		)
+	})
+
+	t.Run("line endings", func(t *testing.T) {
+		generated := newGoldenFS()
+		generated["gen/models.go"] = &fstest.MapFile{Data: []byte("package gen\r\n"), Mode: 0o644}
+
+		expect.It[fs.FS](t, generated).To(befs.TreeEqual(newGoldenFS(), befs.NormalizeLineEndings()))
+		spytb.VerifyFailingMatcher[fs.FS](t, generated, befs.TreeEqual(newGoldenFS()),
+			"changed gen/models.go: the contents differed\n  @@ -1,1 +1,1 @@\n  -package gen\n  +package gen\\r",
+		)
+	})
+
+	t.Run("contents too large to diff", func(t *testing.T) {
+		var want, got strings.Builder
+		for i := range 2100 {
+			fmt.Fprintf(&want, "want %d\n", i)
+			fmt.Fprintf(&got, "got %d\n", i)
+		}
+		golden := newGoldenFS()
+		golden["gen/models.go"] = &fstest.MapFile{Data: []byte(want.String()), Mode: 0o644}
+		generated := newGoldenFS()
+		generated["gen/models.go"] = &fstest.MapFile{Data: []byte(got.String()), Mode: 0o644}
+
+		spytb.VerifyFailingMatcher[fs.FS](t, generated, befs.TreeEqual(golden),
+			"changed gen/models.go: the contents differed\n  too large to diff, it had 2100 lines, expected 2100",
+		)
+	})
+
+	t.Run("binary contents", func(t *testing.T) {
+		generated := newGoldenFS()
+		generated["gen/models.go"] = &fstest.MapFile{Data: []byte{0, 1, 2}, Mode: 0o644}
+
+		spytb.VerifyFailingMatcher[fs.FS](t, generated, befs.TreeEqual(newGoldenFS()),
+			"changed gen/models.go: the binary contents differed, it had 3 bytes, expected 12",
+		)
+	})
+
+	t.Run("modes", func(t *testing.T) {
+		generated := newGoldenFS()
+		generated["run.sh"].Mode = 0o644
+
+		expect.It[fs.FS](t, generated).To(befs.TreeEqual(newGoldenFS()))
+		spytb.VerifyFailingMatcher[fs.FS](t, generated, befs.TreeEqual(newGoldenFS(), befs.CompareModes()),
+			"changed run.sh: its mode was -rw-r--r--, expected -rwxr-xr-x",
+		)
+	})
+
+	t.Run("ignored paths with a directory", func(t *testing.T) {
+		generated := newGoldenFS()
+		generated["gen/cache/x"] = &fstest.MapFile{Data: []byte("x")}
+		golden := newGoldenFS()
+		golden["gen/extra.go"] = &fstest.MapFile{Data: []byte("x")}
+
+		expect.It[fs.FS](t, generated).To(befs.TreeEqual(golden, befs.IgnorePaths("gen/cache", "gen/extra.go")))
+		spytb.VerifyFailingMatcher[fs.FS](t, generated, befs.TreeEqual(golden, befs.IgnorePaths("cache")),
+			"it had 1 difference:\nmissing gen/extra.go",
+		)
+	})
+
+	t.Run("unreadable tree", func(t *testing.T) {
+		spytb.VerifyFailingMatcher[fs.FS](t, FailToReadFS{Error: fmt.Errorf("disk on fire")}, befs.TreeEqual(newGoldenFS()),
+			"expected file system to be equal to the expected tree, but it could not be read",
+		)
+	})
+}
+
+func TestUpdateGolden(t *testing.T) {
+	golden := t.TempDir()
+	expect.NoError(t, os.MkdirAll(filepath.Join(golden, "stale"), 0o755))
+	expect.NoError(t, os.WriteFile(filepath.Join(golden, "stale", "old.go"), []byte("old"), 0o644))
+	expect.NoError(t, os.WriteFile(filepath.Join(golden, "keep.log"), []byte("log"), 0o644))
+	expect.NoError(t, os.WriteFile(filepath.Join(golden, "main.go"), []byte("package old\n"), 0o644))
+
+	generated := newGoldenFS()
+	expect.It[fs.FS](t, generated).To(befs.TreeEqual(os.DirFS(golden), befs.IgnorePaths("*.log"), befs.UpdateGolden(golden, true)))
+
+	expect.It(t, os.DirFS(golden)).To(
+		befs.TreeEqual(generated, befs.IgnorePaths("*.log"), befs.CompareModes()),
+		befs.NotExist("stale"),
+		befs.FileSize("keep.log", be.Eq(int64(3))),
+	)
+
+	spytb.VerifyFailingMatcher[fs.FS](t, generated, befs.TreeEqual(os.DirFS(golden), befs.UpdateGolden("", true)),
+		"expected file system to be equal to the expected tree, but the golden tree could not be updated: no golden directory was given",
+	)
+}
@@ -0,0 +1,323 @@
+package behtml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/behttp"
+)
+
+const subjectName = "the document"
+
+// Document is a parsed HTML document that can be queried with CSS selectors.
+type Document struct {
+	*Node
+}
+
+// Parse parses the HTML. Parsing is tolerant of malformed markup, like a browser, so
+// an error is only returned when the reader fails.
+func Parse(r io.Reader) (*Document, error) {
+	root, err := parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Document{Node: root}, nil
+}
+
+// Find returns every element matching the CSS selector, in document order.
+func (d *Document) Find(sel string) ([]*Node, error) {
+	compiled, err := compileSelector(sel)
+	if err != nil {
+		return nil, err
+	}
+	return find(d.Node, compiled), nil
+}
+
+func (d *Document) String() string {
+	return subjectName
+}
+
+// RespDoc converts http response into a queryable document, fataling when not possible.
+// The body is read with behttp.ReadBody, so the response can still be matched on.
+func RespDoc(t expect.TB, res *http.Response) *Document {
+	t.Helper()
+
+	b, err := behttp.ReadBody(res)
+	if err != nil {
+		t.Fatalf("failed to read the response body: %v", err)
+	}
+
+	doc, err := Parse(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("failed to parse the response body: %v", err)
+	}
+	return doc
+}
+
+// Parsed parses the reader as HTML and runs the matcher on the document. Use it with
+// behttp.RespBody to match an HTML response.
+//
+//	expect.It(t, res).To(behttp.RespBody(behtml.Parsed(behtml.TextAt("h1", "Todos"))))
+func Parsed(matcher expect.Matcher[*Document]) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		doc, err := Parse(rdr)
+		if err != nil {
+			return expect.MatchResult{
+				Description: "be parseable as HTML",
+				Matches:     false,
+				But:         fmt.Sprintf("it could not be read: %v", err),
+				SubjectName: "HTML",
+			}
+		}
+		return matcher(doc)
+	}
+}
+
+// ContentType matches the content type of text/html.
+func ContentType() expect.Matcher[*http.Response] {
+	return behttp.ContentTypeMedia("text/html")
+}
+
+// TextAt provides a CSS selector to find an exact text match for the desired input in any of
+// the matching elements. Whitespace in the text is collapsed before comparing.
+func TextAt(sel, want string) expect.Matcher[*Document] {
+	return textAt(sel, fmt.Sprintf("have text %q at %q", want, sel), func(text string) bool {
+		return text == want
+	})
+}
+
+// ContainsTextAt provides a CSS selector to find text containing the desired input in any of
+// the matching elements.
+func ContainsTextAt(sel, want string) expect.Matcher[*Document] {
+	return textAt(sel, fmt.Sprintf("have text containing %q at %q", want, sel), func(text string) bool {
+		return strings.Contains(text, want)
+	})
+}
+
+func textAt(sel, description string, match func(string) bool) expect.Matcher[*Document] {
+	return func(doc *Document) expect.MatchResult {
+		nodes, result := findAll(doc, sel, description)
+		if len(nodes) == 0 {
+			return result
+		}
+
+		texts := make([]string, 0, len(nodes))
+		for _, n := range nodes {
+			texts = append(texts, n.Text())
+		}
+		result.Matches = slices.ContainsFunc(texts, match)
+		result.But = fmt.Sprintf("the text was %q", texts)
+		return result
+	}
+}
+
+// AttrAt checks the named attribute of the first element matching the selector meets the
+// matcher's criteria.
+func AttrAt(sel, name string, matcher expect.Matcher[string]) expect.Matcher[*Document] {
+	return func(doc *Document) expect.MatchResult {
+		nodes, result := findAll(doc, sel, fmt.Sprintf("have attribute %q at %q", name, sel))
+		if len(nodes) == 0 {
+			return result
+		}
+
+		v, ok := nodes[0].Attr(name)
+		if !ok {
+			result.But = fmt.Sprintf("%s had no %q attribute", nodes[0], name)
+			return result
+		}
+
+		r := matcher(v)
+		r = r.WithDescription(fmt.Sprintf("have attribute %q at %q %s", name, sel, be.Complement(r)))
+		r.SubjectName = subjectName
+		return r
+	}
+}
+
+// Count checks the number of elements matching the selector meets the matcher's criteria.
+func Count(sel string, matcher expect.Matcher[int]) expect.Matcher[*Document] {
+	return func(doc *Document) expect.MatchResult {
+		nodes, err := doc.Find(sel)
+		if err != nil {
+			return expect.MatchResult{
+				Description: fmt.Sprintf("have a count of %q", sel),
+				Matches:     false,
+				But:         err.Error(),
+				SubjectName: subjectName,
+			}
+		}
+
+		r := matcher(len(nodes))
+		r = r.WithDescription(fmt.Sprintf("have a count of %q %s", sel, be.Complement(r)))
+		r.SubjectName = subjectName
+		return r
+	}
+}
+
+// Exists checks at least one element matches the selector.
+func Exists(sel string) expect.Matcher[*Document] {
+	return func(doc *Document) expect.MatchResult {
+		nodes, result := findAll(doc, sel, fmt.Sprintf("have an element matching %q", sel))
+		result.Matches = len(nodes) > 0
+		return result
+	}
+}
+
+// FormField checks the value of the named input, select or textarea meets the matcher's
+// criteria. The value is what the browser would submit: the checked radio button or
+// checkbox, the selected option, or the text of a textarea.
+func FormField(name string, matcher expect.Matcher[string]) expect.Matcher[*Document] {
+	return func(doc *Document) expect.MatchResult {
+		var (
+			fields []*Node
+			names  []string
+		)
+		doc.walk(func(n *Node) {
+			if n.Tag != "input" && n.Tag != "select" && n.Tag != "textarea" {
+				return
+			}
+			fieldName, ok := n.Attr("name")
+			if !ok {
+				return
+			}
+			if fieldName == name {
+				fields = append(fields, n)
+			}
+			if !slices.Contains(names, fieldName) {
+				names = append(names, fieldName)
+			}
+		})
+
+		if len(fields) == 0 {
+			but := fmt.Sprintf("it had no field named %q", name)
+			if len(names) > 0 {
+				but += "; fields: " + strings.Join(names, ", ")
+			}
+			return expect.MatchResult{
+				Description: fmt.Sprintf("have form field %q", name),
+				Matches:     false,
+				But:         but,
+				SubjectName: subjectName,
+			}
+		}
+
+		result := matcher(fieldValue(fields))
+		result = result.WithDescription(fmt.Sprintf("have form field %q %s", name, be.Complement(result)))
+		result.SubjectName = subjectName
+		return result
+	}
+}
+
+func fieldValue(fields []*Node) string {
+	for _, f := range fields {
+		switch f.Tag {
+		case "textarea":
+			return f.rawText()
+		case "select":
+			return selectValue(f)
+		}
+
+		typ, _ := f.Attr("type")
+		if typ = strings.ToLower(typ); typ != "checkbox" && typ != "radio" {
+			v, _ := f.Attr("value")
+			return v
+		}
+		if _, checked := f.Attr("checked"); checked {
+			v, ok := f.Attr("value")
+			if !ok {
+				v = "on"
+			}
+			return v
+		}
+	}
+	return ""
+}
+
+func selectValue(sel *Node) string {
+	var options []*Node
+	sel.walk(func(n *Node) {
+		if n.Tag == "option" {
+			options = append(options, n)
+		}
+	})
+	if len(options) == 0 {
+		return ""
+	}
+
+	chosen := options[0]
+	for _, o := range options {
+		if _, ok := o.Attr("selected"); ok {
+			chosen = o
+			break
+		}
+	}
+	if v, ok := chosen.Attr("value"); ok {
+		return v
+	}
+	return chosen.Text()
+}
+
+// LinkTo checks the document has a link to the given href.
+func LinkTo(href string) expect.Matcher[*Document] {
+	return func(doc *Document) expect.MatchResult {
+		links := hrefs(doc)
+		but := fmt.Sprintf("it linked to %q", links)
+		if len(links) == 0 {
+			but = "it had no links"
+		}
+		return expect.MatchResult{
+			Description: fmt.Sprintf("link to %q", href),
+			Matches:     slices.Contains(links, href),
+			But:         but,
+			SubjectName: subjectName,
+		}
+	}
+}
+
+// Links checks the href of every link in the document, in document order, meets the
+// matcher's criteria.
+func Links(matcher expect.Matcher[[]string]) expect.Matcher[*Document] {
+	return func(doc *Document) expect.MatchResult {
+		result := matcher(hrefs(doc))
+		result = result.WithDescription("have links " + be.Complement(result))
+		result.SubjectName = subjectName
+		return result
+	}
+}
+
+func hrefs(doc *Document) []string {
+	var links []string
+	doc.walk(func(n *Node) {
+		if n.Tag != "a" {
+			return
+		}
+		if href, ok := n.Attr("href"); ok {
+			links = append(links, href)
+		}
+	})
+	return links
+}
+
+// findAll runs the selector, returning a failed result describing why nothing matched.
+func findAll(doc *Document, sel, description string) ([]*Node, expect.MatchResult) {
+	result := expect.MatchResult{
+		Description: description,
+		Matches:     false,
+		SubjectName: subjectName,
+	}
+
+	nodes, err := doc.Find(sel)
+	if err != nil {
+		result.But = err.Error()
+		return nil, result
+	}
+	if len(nodes) == 0 {
+		result.But = fmt.Sprintf("nothing matched %q", sel)
+	}
+	return nodes, result
+}
@@ -0,0 +1,321 @@
+package behtml_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/behtml"
+	"github.com/jsteenb2/expect/be/behttp"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+const page = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>Todos &amp; more</title>
+	<style>p { color: red }</style>
+</head>
+<body>
+	<nav class="top nav">
+		<a href="/" class="active">Home</a>
+		<a href="/todos">Todos</a>
+		<a href="/about" data-track=nav-about>About <em>us</em></a>
+	</nav>
+	<!-- the list -->
+	<ul id="todos">
+		<li class="todo done">Write   tests
+		<li class="todo">Ship it
+		<li class="todo" data-id="3">Celebrate
+	</ul>
+	<p>first<p>second
+	<table>
+		<tr><th>Name<th>Done
+		<tr><td>Write tests<td>yes
+	</table>
+	<form action="/todos" method="post">
+		<input type="text" name="title" value="Buy &quot;milk&quot;">
+		<input type="hidden" name="csrf" value="abc123"/>
+		<input type="radio" name="priority" value="low">
+		<input type="radio" name="priority" value="high" checked>
+		<input type="checkbox" name="notify" checked>
+		<select name="list">
+			<option value="home">Home
+			<option selected>Work
+		</select>
+		<textarea name="notes">
+Line one
+Line two</textarea>
+		<button type="submit" disabled>Add</button>
+	</form>
+	<script>document.write("<p>not a paragraph</p>")</script>
+</body>
+</html>`
+
+func ExampleTextAt() {
+	t := &expect.SpyTB{}
+
+	doc, _ := behtml.Parse(strings.NewReader(page))
+	expect.It(t, doc).To(
+		behtml.TextAt("title", "Todos & more"),
+		behtml.TextAt("#todos li:first-child", "Write tests"),
+		behtml.ContainsTextAt("nav a", "About"),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleTextAt_fail() {
+	t := &expect.SpyTB{}
+
+	doc, _ := behtml.Parse(strings.NewReader(page))
+	expect.It(t, doc).To(behtml.TextAt("li.todo", "Sleep"))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the document to have text "Sleep" at "li.todo", but the text was ["Write tests" "Ship it" "Celebrate"]]
+}
+
+func ExampleFormField() {
+	t := &expect.SpyTB{}
+
+	doc, _ := behtml.Parse(strings.NewReader(page))
+	expect.It(t, doc).To(
+		behtml.FormField("title", be.Eq(`Buy "milk"`)),
+		behtml.FormField("priority", be.Eq("high")),
+		behtml.FormField("list", be.Eq("Work")),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleParsed() {
+	t := &expect.SpyTB{}
+
+	res := behttp.Get("/").Do(newPageServer())
+	expect.It(t, res).To(
+		behtml.ContentType(),
+		behttp.RespBody(behtml.Parsed(behtml.Count("li", be.Eq(3)))),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func TestRespDoc(t *testing.T) {
+	t.Run("leaves the body readable", func(t *testing.T) {
+		res := behttp.Get("/").Do(newPageServer())
+
+		doc := behtml.RespDoc(t, res)
+		expect.It(t, doc).To(behtml.TextAt("h1, title", "Todos & more"))
+
+		// the body is still readable
+		expect.It(t, res).To(behttp.RespBody(behtml.Parsed(behtml.Exists("form"))))
+
+		spytb.VerifyFailingMatcher(t, res, behttp.RespBody(behtml.Parsed(behtml.Count("li", be.Eq(2)))),
+			`expected the response body to have a count of "li" equal to 2, but it was 3`,
+		)
+	})
+
+	t.Run("closes the original body", func(t *testing.T) {
+		body := &closeSpy{Reader: strings.NewReader(page)}
+		behtml.RespDoc(t, &http.Response{Body: body})
+		expect.It(t, body.closed).To(be.Eq(true))
+	})
+
+	t.Run("a nil body is an empty document", func(t *testing.T) {
+		doc := behtml.RespDoc(t, &http.Response{})
+		expect.It(t, doc).To(be.Not(behtml.Exists("p")))
+	})
+
+	t.Run("fails when the body cannot be read", func(t *testing.T) {
+		spyTB := &expect.SpyTB{}
+		behtml.RespDoc(spyTB, &http.Response{Body: io.NopCloser(iotest.ErrReader(errors.New("connection reset")))})
+		expect.It(t, spyTB).To(spytb.Error("failed to read the response body: connection reset"))
+	})
+}
+
+type closeSpy struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeSpy) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSelectors(t *testing.T) {
+	doc, err := behtml.Parse(strings.NewReader(page))
+	expect.NoError(t, err)
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{selector: "nav > a", want: []string{"Home", "Todos", "About us"}},
+		{selector: "a.active", want: []string{"Home"}},
+		{selector: "nav.top.nav a:last-child", want: []string{"About us"}},
+		{selector: "[data-track=nav-about] em", want: []string{"us"}},
+		{selector: `a[href^="/t"]`, want: []string{"Todos"}},
+		{selector: `a[href$=out]`, want: []string{"About us"}},
+		{selector: `a[href*="od"]`, want: []string{"Todos"}},
+		{selector: `nav[class~=nav] a[href='/']`, want: []string{"Home"}},
+		{selector: `[data-track|=nav]`, want: []string{"About us"}},
+		{selector: "li:nth-child(2n+1)", want: []string{"Write tests", "Celebrate"}},
+		{selector: "li:nth-child(even)", want: []string{"Ship it"}},
+		{selector: "li:nth-last-child(1)", want: []string{"Celebrate"}},
+		{selector: "li:nth-child(-n+2)", want: []string{"Write tests", "Ship it"}},
+		{selector: "li:not(.done)", want: []string{"Ship it", "Celebrate"}},
+		{selector: "li.done + li", want: []string{"Ship it"}},
+		{selector: "li.done ~ li", want: []string{"Ship it", "Celebrate"}},
+		{selector: "p", want: []string{"first", "second"}},
+		{selector: "tr:last-child td:first-of-type", want: []string{"Write tests"}},
+		{selector: "th", want: []string{"Name", "Done"}},
+		{selector: "input:checked, option:checked", want: []string{"", "", "Work"}},
+		{selector: "button:disabled", want: []string{"Add"}},
+		{selector: "ul#todos > *:only-child", want: nil},
+		{selector: "body   >   ul li[data-id]", want: []string{"Celebrate"}},
+		{selector: "script", want: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			nodes, err := doc.Find(tt.selector)
+			expect.NoError(t, err)
+
+			var got []string
+			for _, n := range nodes {
+				got = append(got, n.Text())
+			}
+			expect.It(t, got).To(be.ShallowEq(tt.want))
+		})
+	}
+
+	t.Run("invalid selectors", func(t *testing.T) {
+		for selector, want := range map[string]string{
+			"":                `the selector "" is invalid: it is empty`,
+			"a[href":          `the selector "a[href" is invalid: unexpected "" in attribute selector`,
+			"li:hover":        `the selector "li:hover" is invalid: the pseudo-class :hover is not supported`,
+			"li:nth-child(x)": `the selector "li:nth-child(x)" is invalid: "x" is not a valid nth expression`,
+			"a >":             `the selector "a >" is invalid: it is empty`,
+			"a)":              `the selector "a)" is invalid: unexpected ")"`,
+		} {
+			_, err := doc.Find(selector)
+			expect.It(t, err.Error()).To(be.Eq(want))
+		}
+	})
+}
+
+func TestMatchers(t *testing.T) {
+	doc, err := behtml.Parse(strings.NewReader(page))
+	expect.NoError(t, err)
+
+	t.Run("text", func(t *testing.T) {
+		expect.It(t, doc).To(
+			behtml.TextAt("nav", "Home Todos About us"),
+			behtml.TextAt("form select", "Home Work"),
+			behtml.ContainsTextAt("li", "Ship"),
+		)
+
+		spytb.VerifyFailingMatcher(t, doc, behtml.TextAt("h1", "Todos"),
+			`expected the document to have text "Todos" at "h1", but nothing matched "h1"`,
+		)
+		spytb.VerifyFailingMatcher(t, doc, behtml.ContainsTextAt("nav a", "Contact"),
+			`expected the document to have text containing "Contact" at "nav a", but the text was ["Home" "Todos" "About us"]`,
+		)
+		spytb.VerifyFailingMatcher(t, doc, behtml.TextAt("li[", "x"),
+			`expected the document to have text "x" at "li[", but the selector "li[" is invalid: expected an attribute name after [`,
+		)
+	})
+
+	t.Run("attributes", func(t *testing.T) {
+		expect.It(t, doc).To(
+			behtml.AttrAt("html", "lang", be.Eq("en")),
+			behtml.AttrAt("form", "METHOD", be.Eq("post")),
+			behtml.AttrAt("li[data-id]", "data-id", be.Eq("3")),
+		)
+
+		spytb.VerifyFailingMatcher(t, doc, behtml.AttrAt("nav a", "target", be.Eq("_blank")),
+			`expected the document to have attribute "target" at "nav a", but <a href="/" class="active"> had no "target" attribute`,
+		)
+		spytb.VerifyFailingMatcher(t, doc, behtml.AttrAt("form", "action", be.Eq("/")),
+			`expected the document to have attribute "action" at "form" equal to "/", but it was "/todos"`,
+		)
+	})
+
+	t.Run("counts", func(t *testing.T) {
+		expect.It(t, doc).To(
+			behtml.Count("li", be.Eq(3)),
+			behtml.Count("h1", be.Eq(0)),
+			behtml.Exists("textarea"),
+		)
+
+		spytb.VerifyFailingMatcher(t, doc, behtml.Exists("img"),
+			`expected the document to have an element matching "img", but nothing matched "img"`,
+		)
+	})
+
+	t.Run("form fields", func(t *testing.T) {
+		expect.It(t, doc).To(
+			behtml.FormField("csrf", be.Eq("abc123")),
+			behtml.FormField("notify", be.Eq("on")),
+			behtml.FormField("notes", be.Eq("Line one\nLine two")),
+		)
+
+		spytb.VerifyFailingMatcher(t, doc, behtml.FormField("email", be.Eq("")),
+			`expected the document to have form field "email", but it had no field named "email"; fields: title, csrf, priority, notify, list, notes`,
+		)
+		spytb.VerifyFailingMatcher(t, doc, behtml.FormField("csrf", be.Eq("xyz")),
+			`expected the document to have form field "csrf" equal to "xyz", but it was "abc123"`,
+		)
+	})
+
+	t.Run("links", func(t *testing.T) {
+		expect.It(t, doc).To(
+			behtml.LinkTo("/todos"),
+			behtml.Links(be.ShallowEq([]string{"/", "/todos", "/about"})),
+		)
+
+		spytb.VerifyFailingMatcher(t, doc, behtml.LinkTo("/contact"),
+			`expected the document to link to "/contact", but it linked to ["/" "/todos" "/about"]`,
+		)
+		spytb.VerifyFailingMatcher(t, doc, behtml.Links(be.Size[string](be.Eq(2))),
+			`expected the document to have links with a size equal to 2, but it was 3`,
+		)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("tolerates malformed markup", func(t *testing.T) {
+		doc, err := behtml.Parse(strings.NewReader(`<div><span>a < b</div></i><p>open <b>bold<br/>text<img src=x.png alt=>`))
+		expect.NoError(t, err)
+
+		expect.It(t, doc).To(
+			behtml.TextAt("div", "a < b"),
+			behtml.TextAt("p b", "bold text"),
+			behtml.AttrAt("img", "src", be.Eq("x.png")),
+			behtml.AttrAt("img", "alt", be.Eq("")),
+		)
+	})
+
+	t.Run("decodes character references", func(t *testing.T) {
+		doc, err := behtml.Parse(strings.NewReader(`<p title="&lt;ok&gt;">caf&eacute; &#8212; &#x41;</p>`))
+		expect.NoError(t, err)
+
+		expect.It(t, doc).To(
+			behtml.TextAt("p", "café — A"),
+			behtml.AttrAt("p", "title", be.Eq("<ok>")),
+		)
+	})
+}
+
+func newPageServer() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(page))
+	})
+}
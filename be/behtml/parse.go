@@ -0,0 +1,429 @@
+package behtml
+
+import (
+	"html"
+	"io"
+	"slices"
+	"strings"
+)
+
+// NodeType is the type of a Node.
+type NodeType int
+
+const (
+	DocumentNode NodeType = iota
+	ElementNode
+	TextNode
+	CommentNode
+)
+
+// Attr is an attribute of an element. Keys are lower case and values are unescaped.
+type Attr struct {
+	Key string
+	Val string
+}
+
+// Node is a node of a parsed HTML document.
+type Node struct {
+	Type NodeType
+	// Tag is the lower case tag name of an element.
+	Tag string
+	// Data is the unescaped content of a text or comment node.
+	Data     string
+	Attrs    []Attr
+	Parent   *Node
+	Children []*Node
+}
+
+// Attr returns the value of the named attribute.
+func (n *Node) Attr(key string) (string, bool) {
+	key = strings.ToLower(key)
+	for _, a := range n.Attrs {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// Text returns the text within the node with runs of whitespace collapsed to a single
+// space. The content of script and style elements is not included.
+func (n *Node) Text() string {
+	var sb strings.Builder
+	n.writeText(&sb)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+func (n *Node) writeText(sb *strings.Builder) {
+	switch {
+	case n.Type == TextNode:
+		sb.WriteString(n.Data)
+	case n.Type == ElementNode && (n.Tag == "script" || n.Tag == "style"):
+		return
+	}
+	for _, c := range n.Children {
+		c.writeText(sb)
+		if c.Type == ElementNode && !inlineElements[c.Tag] {
+			sb.WriteString(" ")
+		}
+	}
+}
+
+// rawText is the text within the node as written, used for textarea values.
+func (n *Node) rawText() string {
+	var sb strings.Builder
+	for _, c := range n.Children {
+		if c.Type == TextNode {
+			sb.WriteString(c.Data)
+		}
+	}
+	return sb.String()
+}
+
+// String renders the start tag of an element, i.e. <a class="nav" href="/">.
+func (n *Node) String() string {
+	switch n.Type {
+	case DocumentNode:
+		return "the document"
+	case TextNode:
+		return n.Data
+	case CommentNode:
+		return "<!--" + n.Data + "-->"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<" + n.Tag)
+	for _, a := range n.Attrs {
+		sb.WriteString(" " + a.Key)
+		if a.Val != "" {
+			sb.WriteString(`="` + html.EscapeString(a.Val) + `"`)
+		}
+	}
+	sb.WriteString(">")
+	return sb.String()
+}
+
+// elementChildren returns the children of the node that are elements.
+func (n *Node) elementChildren() []*Node {
+	var out []*Node
+	for _, c := range n.Children {
+		if c.Type == ElementNode {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (n *Node) walk(fn func(*Node)) {
+	for _, c := range n.Children {
+		if c.Type == ElementNode {
+			fn(c)
+			c.walk(fn)
+		}
+	}
+}
+
+var (
+	voidElements = set("area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "param", "source", "track", "wbr")
+
+	// rawTextElements hold text that is not parsed for tags, title and textarea
+	// content still has character references decoded.
+	rawTextElements = set("script", "style", "title", "textarea", "xmp", "iframe", "noembed", "noframes")
+
+	// closesP lists the elements that implicitly close an open paragraph.
+	closesP = set("address", "article", "aside", "blockquote", "details", "dialog", "div", "dl", "fieldset",
+		"figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hgroup",
+		"hr", "main", "menu", "nav", "ol", "p", "pre", "section", "table", "ul")
+
+	inlineElements = set("a", "abbr", "b", "bdi", "bdo", "cite", "code", "data", "dfn", "em", "i", "kbd",
+		"mark", "q", "s", "samp", "small", "span", "strong", "sub", "sup", "time", "u", "var")
+
+	scopeBoundaries = set("html", "body", "table", "td", "th", "caption", "button", "template")
+)
+
+func set(items ...string) map[string]bool {
+	m := make(map[string]bool, len(items))
+	for _, item := range items {
+		m[item] = true
+	}
+	return m
+}
+
+// parse builds a tree from the HTML. It is tolerant in the way browsers are: unknown
+// end tags are ignored, open elements are closed at the end of the document and common
+// optional end tags such as </p>, </li> and </td> are implied.
+func parse(r io.Reader) (*Node, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{s: string(b), doc: &Node{Type: DocumentNode}}
+	p.stack = []*Node{p.doc}
+	p.run()
+	return p.doc, nil
+}
+
+type parser struct {
+	s     string
+	pos   int
+	doc   *Node
+	stack []*Node
+}
+
+func (p *parser) top() *Node {
+	return p.stack[len(p.stack)-1]
+}
+
+func (p *parser) run() {
+	for p.pos < len(p.s) {
+		i := strings.IndexByte(p.s[p.pos:], '<')
+		if i == -1 {
+			p.addText(html.UnescapeString(p.s[p.pos:]))
+			return
+		}
+		if i > 0 {
+			p.addText(html.UnescapeString(p.s[p.pos : p.pos+i]))
+			p.pos += i
+		}
+
+		rest := p.s[p.pos:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end == -1 {
+				p.addChild(&Node{Type: CommentNode, Data: rest[4:]})
+				p.pos = len(p.s)
+				continue
+			}
+			p.addChild(&Node{Type: CommentNode, Data: rest[4 : 4+end]})
+			p.pos += 4 + end + 3
+		case strings.HasPrefix(rest, "<![CDATA["):
+			end := strings.Index(rest, "]]>")
+			if end == -1 {
+				end = len(rest)
+			}
+			p.addText(rest[9:end])
+			p.pos += min(end+3, len(rest))
+		case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+			// doctypes and processing instructions carry nothing worth matching on
+			p.skipPast('>')
+		case strings.HasPrefix(rest, "</") && len(rest) > 2 && isLetter(rest[2]):
+			p.pos += 2
+			name := strings.ToLower(p.readName())
+			p.skipPast('>')
+			p.closeTag(name)
+		case len(rest) > 1 && isLetter(rest[1]):
+			p.pos++
+			p.startTag()
+		default:
+			p.addText("<")
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) startTag() {
+	name := strings.ToLower(p.readName())
+
+	var (
+		attrs       []Attr
+		selfClosing bool
+	)
+	for p.pos < len(p.s) {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			break
+		}
+		c := p.s[p.pos]
+		if c == '>' {
+			p.pos++
+			break
+		}
+		if c == '/' {
+			p.pos++
+			selfClosing = p.pos < len(p.s) && p.s[p.pos] == '>'
+			continue
+		}
+
+		key := strings.ToLower(p.readAttrName())
+		p.skipSpace()
+		var val string
+		if p.pos < len(p.s) && p.s[p.pos] == '=' {
+			p.pos++
+			p.skipSpace()
+			val = html.UnescapeString(p.readAttrValue())
+		}
+		if !slices.ContainsFunc(attrs, func(a Attr) bool { return a.Key == key }) {
+			attrs = append(attrs, Attr{Key: key, Val: val})
+		}
+	}
+
+	p.closeImplied(name)
+	n := &Node{Type: ElementNode, Tag: name, Attrs: attrs}
+	p.addChild(n)
+	if voidElements[name] || selfClosing {
+		return
+	}
+
+	if rawTextElements[name] {
+		end := indexFold(p.s[p.pos:], "</"+name)
+		if end == -1 {
+			end = len(p.s) - p.pos
+		}
+		text := p.s[p.pos : p.pos+end]
+		if name == "title" || name == "textarea" {
+			text = html.UnescapeString(text)
+			// a leading newline in a textarea is not part of its value
+			if name == "textarea" {
+				text = strings.TrimPrefix(strings.TrimPrefix(text, "\r"), "\n")
+			}
+		}
+		if text != "" {
+			n.Children = append(n.Children, &Node{Type: TextNode, Data: text, Parent: n})
+		}
+		p.pos += end
+		p.skipPast('>')
+		return
+	}
+
+	p.stack = append(p.stack, n)
+}
+
+// closeImplied closes the open elements whose end tag is implied by the start of name.
+func (p *parser) closeImplied(name string) {
+	switch {
+	case name == "li":
+		p.closeWithin(set("li"), set("ul", "ol", "menu"))
+	case name == "dt" || name == "dd":
+		p.closeWithin(set("dt", "dd"), set("dl"))
+	case name == "option":
+		p.closeWithin(set("option"), set("select", "datalist", "optgroup"))
+	case name == "optgroup":
+		p.closeWithin(set("option", "optgroup"), set("select"))
+	case name == "tr":
+		p.closeWithin(set("tr", "td", "th"), set("table", "thead", "tbody", "tfoot"))
+	case name == "td" || name == "th":
+		p.closeWithin(set("td", "th"), set("tr", "table"))
+	case name == "thead" || name == "tbody" || name == "tfoot":
+		p.closeWithin(set("thead", "tbody", "tfoot", "tr", "td", "th"), set("table"))
+	}
+	if closesP[name] {
+		p.closeWithin(set("p"), scopeBoundaries)
+	}
+}
+
+// closeWithin pops up to and including the nearest open element in targets, unless
+// one of the boundaries is reached first.
+func (p *parser) closeWithin(targets, boundaries map[string]bool) {
+	for i := len(p.stack) - 1; i > 0; i-- {
+		tag := p.stack[i].Tag
+		if targets[tag] {
+			p.stack = p.stack[:i]
+			return
+		}
+		if boundaries[tag] {
+			return
+		}
+	}
+}
+
+func (p *parser) closeTag(name string) {
+	for i := len(p.stack) - 1; i > 0; i-- {
+		if p.stack[i].Tag == name {
+			p.stack = p.stack[:i]
+			return
+		}
+	}
+}
+
+func (p *parser) addChild(n *Node) {
+	parent := p.top()
+	n.Parent = parent
+	parent.Children = append(parent.Children, n)
+}
+
+func (p *parser) addText(text string) {
+	if text == "" {
+		return
+	}
+	parent := p.top()
+	if last := len(parent.Children) - 1; last >= 0 && parent.Children[last].Type == TextNode {
+		parent.Children[last].Data += text
+		return
+	}
+	p.addChild(&Node{Type: TextNode, Data: text})
+}
+
+func (p *parser) readName() string {
+	start := p.pos
+	for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && p.s[p.pos] != '/' && p.s[p.pos] != '>' {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *parser) readAttrName() string {
+	start := p.pos
+	// the first character may be = or /, which is then part of the name
+	p.pos++
+	for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && !strings.ContainsRune("/>=", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *parser) readAttrValue() string {
+	if p.pos >= len(p.s) {
+		return ""
+	}
+	if q := p.s[p.pos]; q == '"' || q == '\'' {
+		end := strings.IndexByte(p.s[p.pos+1:], q)
+		if end == -1 {
+			v := p.s[p.pos+1:]
+			p.pos = len(p.s)
+			return v
+		}
+		v := p.s[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return v
+	}
+	start := p.pos
+	for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && p.s[p.pos] != '>' {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && isSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) skipPast(c byte) {
+	i := strings.IndexByte(p.s[p.pos:], c)
+	if i == -1 {
+		p.pos = len(p.s)
+		return
+	}
+	p.pos += i + 1
+}
+
+// indexFold finds the ASCII lower case substr in s, ignoring case.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
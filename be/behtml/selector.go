@@ -0,0 +1,456 @@
+package behtml
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// selector is a compiled group of CSS selectors, i.e. "nav a, footer a".
+type selector []complexSelector
+
+// complexSelector is a chain of compound selectors joined by combinators, where
+// combinators[i] joins parts[i] and parts[i+1].
+type complexSelector struct {
+	parts       []compoundSelector
+	combinators []byte
+}
+
+// compoundSelector is an optional tag name followed by conditions, i.e. a.nav[href].
+type compoundSelector struct {
+	tag        string
+	conditions []func(*Node) bool
+}
+
+func (s selector) match(n *Node) bool {
+	return slices.ContainsFunc(s, func(c complexSelector) bool {
+		return c.matchAt(n, len(c.parts)-1)
+	})
+}
+
+func (c complexSelector) matchAt(n *Node, i int) bool {
+	if !c.parts[i].match(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	switch c.combinators[i-1] {
+	case '>':
+		return n.Parent != nil && n.Parent.Type == ElementNode && c.matchAt(n.Parent, i-1)
+	case '+':
+		prev := previousSiblings(n)
+		return len(prev) > 0 && c.matchAt(prev[len(prev)-1], i-1)
+	case '~':
+		return slices.ContainsFunc(previousSiblings(n), func(s *Node) bool {
+			return c.matchAt(s, i-1)
+		})
+	default:
+		for a := n.Parent; a != nil && a.Type == ElementNode; a = a.Parent {
+			if c.matchAt(a, i-1) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c compoundSelector) match(n *Node) bool {
+	if n.Type != ElementNode || (c.tag != "" && c.tag != "*" && c.tag != n.Tag) {
+		return false
+	}
+	for _, cond := range c.conditions {
+		if !cond(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// find returns every element below root that matches the selector, in document order.
+func find(root *Node, sel selector) []*Node {
+	var out []*Node
+	root.walk(func(n *Node) {
+		if sel.match(n) {
+			out = append(out, n)
+		}
+	})
+	return out
+}
+
+// compileSelector parses a CSS selector group. It supports type, universal, #id,
+// .class and attribute selectors ([a], [a=v], [a~=v], [a|=v], [a^=v], [a$=v] and
+// [a*=v]), the descendant, >, + and ~ combinators, and the :first-child, :last-child,
+// :only-child, :first-of-type, :last-of-type, :nth-child(), :nth-last-child(),
+// :nth-of-type(), :not(), :empty, :checked, :disabled and :enabled pseudo-classes.
+func compileSelector(s string) (selector, error) {
+	sp := &selectorParser{s: s}
+	sel, err := sp.parseGroup()
+	if err != nil {
+		return nil, fmt.Errorf("the selector %q is invalid: %w", s, err)
+	}
+	if sp.pos < len(sp.s) {
+		return nil, fmt.Errorf("the selector %q is invalid: unexpected %q", s, sp.s[sp.pos:])
+	}
+	return sel, nil
+}
+
+type selectorParser struct {
+	s   string
+	pos int
+}
+
+func (sp *selectorParser) parseGroup() (selector, error) {
+	var sel selector
+	for {
+		sp.skipSpace()
+		c, err := sp.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, c)
+
+		sp.skipSpace()
+		if !sp.consume(',') {
+			return sel, nil
+		}
+	}
+}
+
+func (sp *selectorParser) parseComplex() (complexSelector, error) {
+	var c complexSelector
+	for {
+		part, err := sp.parseCompound()
+		if err != nil {
+			return c, err
+		}
+		c.parts = append(c.parts, part)
+
+		hadSpace := sp.skipSpace()
+		if sp.pos >= len(sp.s) || sp.peek() == ',' || sp.peek() == ')' {
+			return c, nil
+		}
+		switch comb := sp.peek(); comb {
+		case '>', '+', '~':
+			sp.pos++
+			sp.skipSpace()
+			c.combinators = append(c.combinators, comb)
+		default:
+			if !hadSpace {
+				return c, fmt.Errorf("unexpected %q", sp.s[sp.pos:])
+			}
+			c.combinators = append(c.combinators, ' ')
+		}
+	}
+}
+
+func (sp *selectorParser) parseCompound() (compoundSelector, error) {
+	var c compoundSelector
+	if sp.consume('*') {
+		c.tag = "*"
+	} else if sp.pos < len(sp.s) && isIdentByte(sp.peek()) {
+		c.tag = strings.ToLower(sp.readIdent())
+	}
+
+	for sp.pos < len(sp.s) {
+		switch sp.peek() {
+		case '#':
+			sp.pos++
+			id := sp.readIdent()
+			if id == "" {
+				return c, fmt.Errorf("expected an id after #")
+			}
+			c.conditions = append(c.conditions, func(n *Node) bool {
+				v, _ := n.Attr("id")
+				return v == id
+			})
+		case '.':
+			sp.pos++
+			class := sp.readIdent()
+			if class == "" {
+				return c, fmt.Errorf("expected a class name after .")
+			}
+			c.conditions = append(c.conditions, func(n *Node) bool {
+				v, _ := n.Attr("class")
+				return slices.Contains(strings.Fields(v), class)
+			})
+		case '[':
+			cond, err := sp.parseAttr()
+			if err != nil {
+				return c, err
+			}
+			c.conditions = append(c.conditions, cond)
+		case ':':
+			cond, err := sp.parsePseudo()
+			if err != nil {
+				return c, err
+			}
+			c.conditions = append(c.conditions, cond)
+		default:
+			if c.tag == "" && len(c.conditions) == 0 {
+				return c, fmt.Errorf("unexpected %q", sp.s[sp.pos:])
+			}
+			return c, nil
+		}
+	}
+	if c.tag == "" && len(c.conditions) == 0 {
+		return c, fmt.Errorf("it is empty")
+	}
+	return c, nil
+}
+
+func (sp *selectorParser) parseAttr() (func(*Node) bool, error) {
+	sp.pos++ // [
+	sp.skipSpace()
+	key := strings.ToLower(sp.readIdent())
+	if key == "" {
+		return nil, fmt.Errorf("expected an attribute name after [")
+	}
+	sp.skipSpace()
+	if sp.consume(']') {
+		return func(n *Node) bool {
+			_, ok := n.Attr(key)
+			return ok
+		}, nil
+	}
+
+	var op string
+	for _, candidate := range []string{"~=", "|=", "^=", "$=", "*=", "="} {
+		if strings.HasPrefix(sp.s[sp.pos:], candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("unexpected %q in attribute selector", sp.s[sp.pos:])
+	}
+	sp.pos += len(op)
+	sp.skipSpace()
+
+	want, err := sp.readValue()
+	if err != nil {
+		return nil, err
+	}
+	sp.skipSpace()
+	if !sp.consume(']') {
+		return nil, fmt.Errorf("expected ] to close the attribute selector")
+	}
+
+	test := map[string]func(v string) bool{
+		"=":  func(v string) bool { return v == want },
+		"~=": func(v string) bool { return slices.Contains(strings.Fields(v), want) },
+		"|=": func(v string) bool { return v == want || strings.HasPrefix(v, want+"-") },
+		"^=": func(v string) bool { return want != "" && strings.HasPrefix(v, want) },
+		"$=": func(v string) bool { return want != "" && strings.HasSuffix(v, want) },
+		"*=": func(v string) bool { return want != "" && strings.Contains(v, want) },
+	}[op]
+	return func(n *Node) bool {
+		v, ok := n.Attr(key)
+		return ok && test(v)
+	}, nil
+}
+
+func (sp *selectorParser) parsePseudo() (func(*Node) bool, error) {
+	sp.pos++ // :
+	name := strings.ToLower(sp.readIdent())
+	switch name {
+	case "first-child":
+		return nthMatcher(0, 1, false, false), nil
+	case "last-child":
+		return nthMatcher(0, 1, true, false), nil
+	case "only-child":
+		return func(n *Node) bool { return len(siblings(n, false)) == 1 }, nil
+	case "first-of-type":
+		return nthMatcher(0, 1, false, true), nil
+	case "last-of-type":
+		return nthMatcher(0, 1, true, true), nil
+	case "empty":
+		return func(n *Node) bool {
+			return !slices.ContainsFunc(n.Children, func(c *Node) bool {
+				return c.Type == ElementNode || c.Type == TextNode && strings.TrimSpace(c.Data) != ""
+			})
+		}, nil
+	case "checked":
+		return func(n *Node) bool {
+			_, checked := n.Attr("checked")
+			_, selected := n.Attr("selected")
+			return n.Tag == "input" && checked || n.Tag == "option" && selected
+		}, nil
+	case "disabled":
+		return func(n *Node) bool { _, ok := n.Attr("disabled"); return ok }, nil
+	case "enabled":
+		return func(n *Node) bool { _, ok := n.Attr("disabled"); return !ok }, nil
+	case "nth-child", "nth-last-child", "nth-of-type", "nth-last-of-type":
+		arg, err := sp.readArgs()
+		if err != nil {
+			return nil, err
+		}
+		a, b, err := parseNth(arg)
+		if err != nil {
+			return nil, err
+		}
+		return nthMatcher(a, b, strings.Contains(name, "last"), strings.HasSuffix(name, "of-type")), nil
+	case "not":
+		if !sp.consume('(') {
+			return nil, fmt.Errorf("expected ( after :not")
+		}
+		inner, err := sp.parseGroup()
+		if err != nil {
+			return nil, err
+		}
+		sp.skipSpace()
+		if !sp.consume(')') {
+			return nil, fmt.Errorf("expected ) to close :not")
+		}
+		return func(n *Node) bool { return !inner.match(n) }, nil
+	case "":
+		return nil, fmt.Errorf("expected a pseudo-class after :")
+	default:
+		return nil, fmt.Errorf("the pseudo-class :%s is not supported", name)
+	}
+}
+
+// nthMatcher matches elements at a position of the form an+b among their siblings,
+// counting from the end when fromEnd is set and only siblings of the same type when
+// ofType is set.
+func nthMatcher(a, b int, fromEnd, ofType bool) func(*Node) bool {
+	return func(n *Node) bool {
+		sibs := siblings(n, ofType)
+		i := slices.Index(sibs, n)
+		if fromEnd {
+			i = len(sibs) - 1 - i
+		}
+		pos := i + 1
+
+		if a == 0 {
+			return pos == b
+		}
+		k := pos - b
+		return k%a == 0 && k/a >= 0
+	}
+}
+
+// parseNth parses the argument of :nth-child, i.e. odd, even, 3, 2n+1 or -n+3.
+func parseNth(arg string) (int, int, error) {
+	arg = strings.ToLower(strings.ReplaceAll(arg, " ", ""))
+	switch arg {
+	case "odd":
+		return 2, 1, nil
+	case "even":
+		return 2, 0, nil
+	}
+
+	before, after, hasN := strings.Cut(arg, "n")
+	if !hasN {
+		b, err := strconv.Atoi(arg)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%q is not a valid nth expression", arg)
+		}
+		return 0, b, nil
+	}
+
+	var a int
+	switch before {
+	case "", "+":
+		a = 1
+	case "-":
+		a = -1
+	default:
+		var err error
+		if a, err = strconv.Atoi(before); err != nil {
+			return 0, 0, fmt.Errorf("%q is not a valid nth expression", arg)
+		}
+	}
+
+	var b int
+	if after != "" {
+		var err error
+		if b, err = strconv.Atoi(after); err != nil {
+			return 0, 0, fmt.Errorf("%q is not a valid nth expression", arg)
+		}
+	}
+	return a, b, nil
+}
+
+func siblings(n *Node, ofType bool) []*Node {
+	if n.Parent == nil {
+		return []*Node{n}
+	}
+	var out []*Node
+	for _, s := range n.Parent.elementChildren() {
+		if !ofType || s.Tag == n.Tag {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func previousSiblings(n *Node) []*Node {
+	sibs := siblings(n, false)
+	return sibs[:slices.Index(sibs, n)]
+}
+
+func (sp *selectorParser) readArgs() (string, error) {
+	if !sp.consume('(') {
+		return "", fmt.Errorf("expected ( after the pseudo-class")
+	}
+	end := strings.IndexByte(sp.s[sp.pos:], ')')
+	if end == -1 {
+		return "", fmt.Errorf("expected ) to close the pseudo-class")
+	}
+	arg := sp.s[sp.pos : sp.pos+end]
+	sp.pos += end + 1
+	return arg, nil
+}
+
+func (sp *selectorParser) readValue() (string, error) {
+	if sp.pos < len(sp.s) && (sp.peek() == '"' || sp.peek() == '\'') {
+		q := sp.peek()
+		end := strings.IndexByte(sp.s[sp.pos+1:], q)
+		if end == -1 {
+			return "", fmt.Errorf("the string %s is not terminated", sp.s[sp.pos:])
+		}
+		v := sp.s[sp.pos+1 : sp.pos+1+end]
+		sp.pos += end + 2
+		return v, nil
+	}
+	v := sp.readIdent()
+	if v == "" {
+		return "", fmt.Errorf("expected an attribute value")
+	}
+	return v, nil
+}
+
+func (sp *selectorParser) readIdent() string {
+	start := sp.pos
+	for sp.pos < len(sp.s) && isIdentByte(sp.s[sp.pos]) {
+		sp.pos++
+	}
+	return sp.s[start:sp.pos]
+}
+
+func (sp *selectorParser) skipSpace() bool {
+	start := sp.pos
+	for sp.pos < len(sp.s) && isSpace(sp.s[sp.pos]) {
+		sp.pos++
+	}
+	return sp.pos > start
+}
+
+func (sp *selectorParser) consume(c byte) bool {
+	if sp.pos < len(sp.s) && sp.s[sp.pos] == c {
+		sp.pos++
+		return true
+	}
+	return false
+}
+
+func (sp *selectorParser) peek() byte {
+	return sp.s[sp.pos]
+}
+
+func isIdentByte(c byte) bool {
+	return isLetter(c) || c >= '0' && c <= '9' || c == '-' || c == '_' || c >= 0x80
+}
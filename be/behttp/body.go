@@ -0,0 +1,67 @@
+package behttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing/iotest"
+)
+
+// replayBody is a fully buffered response body. It is put back on the response
+// after it is read so that every matcher, and the caller, sees the entire body.
+type replayBody struct {
+	io.Reader
+	data []byte
+	err  error
+}
+
+func newReplayBody(data []byte, err error) *replayBody {
+	return &replayBody{
+		Reader: bodyReader(data, err),
+		data:   data,
+		err:    err,
+	}
+}
+
+func (r *replayBody) Close() error {
+	return nil
+}
+
+// ReadBody reads the whole response body and puts it back, so the response can still be
+// matched on and read again. A nil body reads as empty.
+func ReadBody(res *http.Response) ([]byte, error) {
+	return bufferBody(res)
+}
+
+// bufferBody reads the response body once, caching the bytes on the response. The
+// body is replaced with a fresh replay reader on each call.
+func bufferBody(res *http.Response) ([]byte, error) {
+	return buffer(&res.Body)
+}
+
+// buffer reads body once, caching the bytes in a replayBody that is put back in
+// its place. The body is replaced with a fresh replay reader on each call.
+func buffer(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	if rb, ok := (*body).(*replayBody); ok {
+		*body = newReplayBody(rb.data, rb.err)
+		return rb.data, rb.err
+	}
+
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	*body = newReplayBody(data, err)
+	return data, err
+}
+
+// bodyReader provides a reader over the buffered body which fails with the
+// original read error, if any, once the buffered bytes are exhausted.
+func bodyReader(data []byte, err error) io.Reader {
+	if err == nil {
+		return bytes.NewReader(data)
+	}
+	return io.MultiReader(bytes.NewReader(data), iotest.ErrReader(err))
+}
@@ -0,0 +1,215 @@
+package behttp
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jsteenb2/expect"
+)
+
+// dumpConfig controls the response dump attached to failing matchers.
+type dumpConfig struct {
+	bodyLimit int
+	request   bool
+	redacted  []string
+}
+
+func defaultDump() dumpConfig {
+	return dumpConfig{
+		bodyLimit: 512,
+		redacted:  []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"},
+	}
+}
+
+func newDumpConfig(opts []DumpOption) dumpConfig {
+	cfg := defaultDump()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// DumpOption changes the response dump attached to failing matchers, see Dumped and Req.Expect.
+type DumpOption func(*dumpConfig)
+
+// DumpBodyLimit sets the maximum number of body bytes included in the dump, 512 by
+// default. A limit of 0 omits the body.
+func DumpBodyLimit(n int) DumpOption {
+	return func(cfg *dumpConfig) {
+		cfg.bodyLimit = n
+	}
+}
+
+// DumpRequest includes the request that produced the response, taken from res.Request,
+// in the dump.
+func DumpRequest() DumpOption {
+	return func(cfg *dumpConfig) {
+		cfg.request = true
+	}
+}
+
+// RedactHeaders adds to the headers whose values are replaced with [REDACTED] in dumps,
+// so that credentials do not leak into test output. Authorization, Proxy-Authorization,
+// Cookie and Set-Cookie are always redacted.
+func RedactHeaders(names ...string) DumpOption {
+	return func(cfg *dumpConfig) {
+		cfg.redacted = append(slices.Clip(cfg.redacted), names...)
+	}
+}
+
+// Dumped changes the dump attached to the failures of the behttp response matcher. The
+// matcher's failure gets one dump, made with the options, however many of the matchers it
+// is composed of failed.
+//
+//	behttp.Dumped(behttp.StatusOK(), behttp.DumpRequest(), behttp.DumpBodyLimit(64))
+func Dumped(matcher expect.Matcher[*http.Response], opts ...DumpOption) expect.Matcher[*http.Response] {
+	cfg := newDumpConfig(opts)
+	return func(res *http.Response) expect.MatchResult {
+		return dumpedWith(matcher(res), res, cfg, "")
+	}
+}
+
+// responseDump is the dump of a response shown after a failure, as the MatchResult's
+// Detail. It is only rendered when the failure is.
+type responseDump struct {
+	res *http.Response
+	// body includes the body, which is left out for matchers whose failure already
+	// describes it.
+	body bool
+	cfg  *dumpConfig
+	// request is the request line of an Expectation, shown after the response.
+	request string
+}
+
+func (d responseDump) String() string {
+	cfg := defaultDump()
+	if d.cfg != nil {
+		cfg = *d.cfg
+	}
+	dump := dumpResponse(d.res, cfg, d.body)
+	if d.request != "" {
+		dump += "\n" + d.request
+	}
+	return dump
+}
+
+// withDump attaches a compact dump of the response to a failing result.
+func withDump(result expect.MatchResult, res *http.Response) expect.MatchResult {
+	return attachDump(result, res, true)
+}
+
+// withHeadDump attaches a dump of the response without its body, for matchers whose
+// failure already describes the body.
+func withHeadDump(result expect.MatchResult, res *http.Response) expect.MatchResult {
+	return attachDump(result, res, false)
+}
+
+func attachDump(result expect.MatchResult, res *http.Response, body bool) expect.MatchResult {
+	if result.Matches || res == nil {
+		return result
+	}
+	if result.But == "" {
+		result.But = "it did not"
+	}
+	result.Detail = responseDump{res: res, body: body}
+	return result
+}
+
+// dumpedWith makes the dump of a failing result with cfg, and the request line when one is
+// given. The dump a behttp matcher attached says whether to leave out the body, any other
+// failure gets a dump with it. A Detail given by another package is left alone.
+func dumpedWith(result expect.MatchResult, res *http.Response, cfg dumpConfig, request string) expect.MatchResult {
+	if result.Matches || res == nil {
+		return result
+	}
+	dump := responseDump{res: res, body: true}
+	switch d := result.Detail.(type) {
+	case nil:
+	case responseDump:
+		dump.body = d.body
+	default:
+		return result
+	}
+	dump.cfg, dump.request = &cfg, request
+	result.Detail = dump
+	return result
+}
+
+func dumpResponse(res *http.Response, cfg dumpConfig, body bool) string {
+	var lines []string
+	if cfg.request && res.Request != nil {
+		req := res.Request
+		lines = append(lines, "request:", fmt.Sprintf("\t%s %s %s", req.Method, req.URL.RequestURI(), protoOf(req.Proto)))
+		if req.Host != "" {
+			lines = append(lines, "\tHost: "+req.Host)
+		}
+		lines = append(lines, dumpHeaders(req.Header, cfg.redacted)...)
+	}
+
+	status := res.Status
+	if status == "" {
+		status = fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode))
+	}
+	lines = append(lines, "response:", fmt.Sprintf("\t%s %s", protoOf(res.Proto), status))
+	lines = append(lines, dumpHeaders(res.Header, cfg.redacted)...)
+
+	if !body {
+		return strings.Join(lines, "\n")
+	}
+	data, err := bufferBody(res)
+	switch {
+	case err != nil:
+		lines = append(lines, "", fmt.Sprintf("\t<body could not be read: %v>", err))
+	case len(data) > 0 && cfg.bodyLimit > 0:
+		lines = append(lines, "", "\t"+truncateBody(data, cfg.bodyLimit))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func dumpHeaders(header http.Header, redacted []string) []string {
+	var lines []string
+	for _, name := range slices.Sorted(maps.Keys(header)) {
+		value := strings.Join(header.Values(name), ", ")
+		if isRedacted(name, redacted) {
+			value = "[REDACTED]"
+		}
+		lines = append(lines, fmt.Sprintf("\t%s: %s", name, value))
+	}
+	return lines
+}
+
+func isRedacted(header string, redacted []string) bool {
+	return slices.ContainsFunc(redacted, func(h string) bool { return strings.EqualFold(h, header) })
+}
+
+func truncateBody(data []byte, limit int) string {
+	var more int
+	if len(data) > limit {
+		data, more = data[:limit], len(data)-limit
+		// avoid splitting a multibyte rune at the limit
+		for i := 0; i < utf8.UTFMax-1 && !utf8.Valid(data); i++ {
+			data, more = data[:len(data)-1], more+1
+		}
+	}
+
+	body := string(data)
+	if !utf8.Valid(data) {
+		body = fmt.Sprintf("%q", data)
+	}
+	body = strings.ReplaceAll(strings.TrimRight(body, "\n"), "\n", "\n\t")
+	if more > 0 {
+		body += fmt.Sprintf("... (%d more bytes)", more)
+	}
+	return body
+}
+
+func protoOf(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
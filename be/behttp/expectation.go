@@ -0,0 +1,104 @@
+package behttp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jsteenb2/expect"
+)
+
+// Expectation is a fluent chain of assertions against a response. Every failure
+// includes the request line and headers, which makes it obvious which call failed in
+// table tests. Go methods cannot take type parameters, so values within a JSON body are
+// matched by passing JSONPath to To.
+//
+//	res := behttp.Get("/todos/3").
+//		Expect(t, mux).
+//		Status(http.StatusOK).
+//		Header("Content-Type", "application/json").
+//		To(behttp.JSONPath("$.id", be.Eq(3))).
+//		Response
+type Expectation struct {
+	// Response is the response being asserted on, for any further checks.
+	Response *http.Response
+
+	t       expect.TB
+	request string
+	dump    dumpConfig
+}
+
+// Expect runs the request against the handler and returns an Expectation for the response.
+// The options change the response dump attached to failures, and which headers of the
+// request line are redacted.
+func (r *Req) Expect(t expect.TB, handler http.Handler, opts ...DumpOption) *Expectation {
+	req := r.serverRequest()
+	dump := newDumpConfig(opts)
+	return &Expectation{
+		Response: serve(handler, req),
+		t:        t,
+		request:  requestLine(req, dump),
+		dump:     dump,
+	}
+}
+
+// DoAndExpect runs the request against the handler, matches the response against the
+// matchers and returns the response for further checks.
+func (r *Req) DoAndExpect(t expect.TB, handler http.Handler, matchers ...expect.Matcher[*http.Response]) *http.Response {
+	t.Helper()
+	return r.Expect(t, handler).To(matchers...).Response
+}
+
+// To matches the response against the given matchers.
+func (e *Expectation) To(matchers ...expect.Matcher[*http.Response]) *Expectation {
+	e.t.Helper()
+
+	wrapped := make([]expect.Matcher[*http.Response], 0, len(matchers))
+	for _, m := range matchers {
+		wrapped = append(wrapped, e.withRequest(m))
+	}
+	expect.It(e.t, e.Response).To(wrapped...)
+	return e
+}
+
+// Status asserts the response has the given status code.
+func (e *Expectation) Status(status int) *Expectation {
+	e.t.Helper()
+	return e.To(Status(status))
+}
+
+// Header asserts the response has the header with the given value.
+func (e *Expectation) Header(header, value string) *Expectation {
+	e.t.Helper()
+	return e.To(headerOf(header, value, e.dump.redacted))
+}
+
+// ContentType asserts the response has the given Content-Type.
+func (e *Expectation) ContentType(want string) *Expectation {
+	e.t.Helper()
+	return e.To(ContentType(want))
+}
+
+// Body asserts the response body meets the matcher's criteria.
+func (e *Expectation) Body(matcher expect.Matcher[io.Reader]) *Expectation {
+	e.t.Helper()
+	return e.To(RespBody(matcher))
+}
+
+func (e *Expectation) withRequest(m expect.Matcher[*http.Response]) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		result := m(res)
+		if !result.Matches && result.But == "" {
+			result.But = "it did not"
+		}
+		return dumpedWith(result, res, e.dump, e.request)
+	}
+}
+
+// requestLine describes the request with its method, URL and headers, redacted as in the dump.
+func requestLine(req *http.Request, dump dumpConfig) string {
+	lines := []string{fmt.Sprintf("request: %s %s", req.Method, req.URL.RequestURI())}
+	lines = append(lines, dumpHeaders(req.Header, dump.redacted)...)
+	return strings.Join(lines, "\n")
+}
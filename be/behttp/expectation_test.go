@@ -0,0 +1,110 @@
+package behttp_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/behttp"
+	"github.com/jsteenb2/expect/be/bejson"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func ExampleReq_Expect() {
+	t := &expect.SpyTB{}
+
+	behttp.Get("/").
+		Expect(t, newMux()).
+		Status(http.StatusOK).
+		To(behttp.JSONPath("$.Method", be.Eq(http.MethodGet)))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleReq_Expect_fail() {
+	t := &expect.SpyTB{}
+
+	behttp.Delete("/").
+		Headers("Accept", "application/json").
+		Expect(t, newMux()).
+		Status(http.StatusOK)
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the response to have status of 200, but it was 204
+	// response:
+	// 	HTTP/1.1 204 No Content
+	// request: DELETE /
+	// 	Accept: application/json]
+}
+
+func TestExpectation(t *testing.T) {
+	svr := newMux()
+
+	t.Run("returns the response for further checks", func(t *testing.T) {
+		res := behttp.
+			Post("/headers", nil).
+			Headers("Foo", "bar").
+			Expect(t, svr).
+			Status(http.StatusAccepted).
+			Header("Foo", "bar").
+			Response
+
+		expect.It(t, res.Request).To(behttp.Method(http.MethodPost))
+	})
+
+	t.Run("DoAndExpect", func(t *testing.T) {
+		res := behttp.Put("/", nil).DoAndExpect(t, svr,
+			behttp.Status(http.StatusAccepted),
+			behttp.RespBody(haveFoo(http.MethodPut)),
+		)
+
+		expect.It(t, res).To(behttp.JSONPath("$.Name", be.Eq("name")))
+	})
+
+	t.Run("credentials in the request line are redacted", func(t *testing.T) {
+		spyTB := &expect.SpyTB{}
+
+		behttp.Get("/").
+			Bearer("s3cret-token").
+			Cookie(&http.Cookie{Name: "sid", Value: "topsecret"}).
+			Headers("X-Api-Key", "k3y").
+			Expect(spyTB, svr, behttp.RedactHeaders("X-Api-Key"), behttp.DumpBodyLimit(0)).
+			Status(http.StatusTeapot).
+			Header("Authorization", "Bearer other")
+
+		expect.It(t, spyTB).To(
+			spytb.Error("expected the response to have status of 418, but it was 200\n"+
+				"response:\n"+
+				"\tHTTP/1.1 200 OK\n"+
+				"request: GET /\n"+
+				"\tAuthorization: [REDACTED]\n"+
+				"\tCookie: [REDACTED]\n"+
+				"\tX-Api-Key: [REDACTED]"),
+		)
+		for _, secret := range []string{"s3cret-token", "topsecret", "k3y"} {
+			expect.It(t, fmt.Sprint(spyTB.ErrorCalls)).To(be.Not(be.Substring(secret)))
+		}
+	})
+
+	t.Run("every failure includes the request", func(t *testing.T) {
+		for _, method := range []string{http.MethodGet, http.MethodPatch} {
+			spyTB := &expect.SpyTB{}
+
+			behttp.Request(method, "/?page=2", nil).
+				Expect(spyTB, svr).
+				ContentType("text/html").
+				Body(bejson.Path("$.Method", be.Eq(http.MethodPost))).
+				To(behttp.StatusClass(4))
+
+			request := fmt.Sprintf("request: %s /?page=2", method)
+			expect.It(t, spyTB.ErrorCalls).To(be.Size[string](be.Eq(3)))
+			expect.It(t, spyTB).To(
+				spytb.Error(`expected the response to have header "Content-Type" of "text/html", but it was ""`),
+				spytb.Error(`{"Name":"name","Thing":"thing","Method":"`+method+`"}`+"\n"+request),
+				spytb.Error(fmt.Sprintf(`expected the response body to have $.Method equal to "POST", but it was %q`, method)),
+				spytb.Error("expected the response to have a 4xx status, but it was 2"),
+			)
+		}
+	})
+}
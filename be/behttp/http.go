@@ -3,9 +3,15 @@ package behttp
 import (
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 	
 	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/bejson"
 )
 
 const (
@@ -16,12 +22,12 @@ const (
 // Status returns a matcher that checks if the response status code is equal to the given status code.
 func Status(status int) expect.Matcher[*http.Response] {
 	return func(res *http.Response) expect.MatchResult {
-		return expect.MatchResult{
+		return withDump(expect.MatchResult{
 			Description: fmt.Sprintf("have status of %d", status),
-			But:         fmt.Sprintf("it was %d", res.StatusCode),
 			Matches:     res.StatusCode == status,
+			But:         fmt.Sprintf("it was %d", res.StatusCode),
 			SubjectName: subjectNameHTTPResp,
-		}
+		}, res)
 	}
 }
 
@@ -30,15 +36,103 @@ func StatusOK() expect.Matcher[*http.Response] {
 	return Status(http.StatusOK)
 }
 
+// StatusClass returns a matcher that checks the response status code falls within the
+// given class, where class is the leading digit of the code. For example, StatusClass(2)
+// matches any 2xx response and StatusClass(5) matches any 5xx response.
+func StatusClass(class int) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		return withDump(expect.MatchResult{
+			Description: fmt.Sprintf("have a %dxx status", class),
+			Matches:     res.StatusCode/100 == class,
+			But:         fmt.Sprintf("it was %d", res.StatusCode),
+			SubjectName: subjectNameHTTPResp,
+		}, res)
+	}
+}
+
+// RedirectTo returns a matcher that checks the response is a redirect (3xx) with a Location header of location.
+func RedirectTo(location string) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		got := res.Header.Get("Location")
+		isRedirect := res.StatusCode/100 == 3
+		
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("redirect to %q", location),
+			Matches:     isRedirect && got == location,
+			SubjectName: subjectNameHTTPResp,
+		}
+		switch {
+		case !isRedirect:
+			result.But = fmt.Sprintf("it had status %d with Location %q", res.StatusCode, got)
+		case got != location:
+			result.But = fmt.Sprintf("it redirected to %q", got)
+		}
+		return withDump(result, res)
+	}
+}
+
 // Header returns a matcher that checks if the response has a header with the given name and value.
+// The value the response had is not shown for redacted headers, such as Authorization and Cookie.
 func Header(header, value string) expect.Matcher[*http.Response] {
+	return headerOf(header, value, defaultDump().redacted)
+}
+
+func headerOf(header, value string, redacted []string) expect.Matcher[*http.Response] {
 	return func(res *http.Response) expect.MatchResult {
-		return expect.MatchResult{
+		got := res.Header.Get(header)
+		but := fmt.Sprintf("it was %q", got)
+		if got != "" && isRedacted(header, redacted) {
+			but = "it was [REDACTED]"
+		}
+		return withDump(expect.MatchResult{
 			Description: fmt.Sprintf("have header %q of %q", header, value),
-			Matches:     res.Header.Get(header) == value,
-			But:         fmt.Sprintf("it was %q", res.Header.Get(header)),
+			Matches:     got == value,
+			But:         but,
 			SubjectName: subjectNameHTTPResp,
+		}, res)
+	}
+}
+
+// HeaderMatching returns a matcher that checks the first value of the named header meets the matcher's criteria.
+// A missing header is matched as the empty string.
+func HeaderMatching(header string, matcher expect.Matcher[string]) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		result := matcher(res.Header.Get(header))
+		result = result.WithDescription(fmt.Sprintf("have header %q %s", header, be.Complement(result)))
+		result.SubjectName = subjectNameHTTPResp
+		return withDump(result, res)
+	}
+}
+
+// HeaderValues returns a matcher that checks all values of the named header meet the matcher's criteria.
+func HeaderValues(header string, matcher expect.Matcher[[]string]) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		values := res.Header.Values(header)
+		result := matcher(values)
+		result = result.WithDescription(fmt.Sprintf("have header %q values %s", header, be.Complement(result)))
+		result.SubjectName = subjectNameHTTPResp
+		if !result.Matches && result.But == "" {
+			result.But = fmt.Sprintf("they were %q", values)
 		}
+		return withDump(result, res)
+	}
+}
+
+// NoHeader returns a matcher that checks the response does not have the named header.
+// The values the response had are not shown for redacted headers, such as Authorization and Cookie.
+func NoHeader(header string) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		values := res.Header.Values(header)
+		but := fmt.Sprintf("it was %q", values)
+		if isRedacted(header, defaultDump().redacted) {
+			but = "it was [REDACTED]"
+		}
+		return withDump(expect.MatchResult{
+			Description: fmt.Sprintf("not have header %q", header),
+			Matches:     len(values) == 0,
+			But:         but,
+			SubjectName: subjectNameHTTPResp,
+		}, res)
 	}
 }
 
@@ -52,11 +146,149 @@ func ContentTypeJSON(res *http.Response) expect.MatchResult {
 	return ContentType("application/json")(res)
 }
 
+// ContentTypeMedia returns a matcher that checks the media type of the Content-Type header,
+// ignoring any parameters such as charset. The comparison is case-insensitive.
+func ContentTypeMedia(mediaType string) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		header := res.Header.Get("Content-Type")
+		got, _, err := mime.ParseMediaType(header)
+		
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have content type media %q", mediaType),
+			Matches:     err == nil && strings.EqualFold(got, mediaType),
+			But:         fmt.Sprintf("it was %q", header),
+			SubjectName: subjectNameHTTPResp,
+		}
+		if err != nil && header != "" {
+			result.But = fmt.Sprintf("it was %q which could not be parsed: %v", header, err)
+		}
+		return withDump(result, res)
+	}
+}
+
+// ContentLength returns a matcher that checks the response content length meets the matcher's criteria.
+// When the length is unknown, the length of the buffered body is used instead.
+func ContentLength(matcher expect.Matcher[int64]) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		length := res.ContentLength
+		if length < 0 {
+			data, _ := bufferBody(res)
+			length = int64(len(data))
+		}
+		result := matcher(length)
+		result = result.WithDescription("have content length " + be.Complement(result))
+		result.SubjectName = subjectNameHTTPResp
+		return withDump(result, res)
+	}
+}
+
+// Cookie returns a matcher that checks the response sets the named cookie, and that it
+// meets the matcher's criteria.
+func Cookie(name string, matcher expect.Matcher[*http.Cookie]) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		cookies := res.Cookies()
+		
+		var names []string
+		for _, c := range cookies {
+			if c.Name != name {
+				names = append(names, c.Name)
+				continue
+			}
+			result := matcher(c)
+			result = result.WithDescription(fmt.Sprintf("have cookie %q %s", name, be.Complement(result)))
+			result.SubjectName = subjectNameHTTPResp
+			return withDump(result, res)
+		}
+		
+		but := "it had no cookies"
+		if len(names) > 0 {
+			but = fmt.Sprintf("it did not; present cookies: %s", strings.Join(names, ", "))
+		}
+		return withDump(expect.MatchResult{
+			Description: fmt.Sprintf("have cookie %q", name),
+			Matches:     false,
+			But:         but,
+			SubjectName: subjectNameHTTPResp,
+		}, res)
+	}
+}
+
+// JSONPath returns a matcher that checks the value at the path within the JSON response body
+// meets the matcher's criteria. It is RespBody(bejson.Path(path, matcher)), see bejson.Path
+// for the path syntax.
+func JSONPath[T any](path string, matcher expect.Matcher[T]) expect.Matcher[*http.Response] {
+	return RespBody(bejson.Path(path, matcher))
+}
+
+// CacheControl returns a matcher that checks the Cache-Control header of the response has
+// the given directive, i.e. CacheControl("no-store"). Directive names are case-insensitive,
+// and a directive given with a value, such as "max-age=60", must have that value.
+func CacheControl(directive string) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		header := strings.Join(res.Header.Values("Cache-Control"), ", ")
+		name, value, hasValue := strings.Cut(directive, "=")
+		got, ok := cacheDirectives(header)[strings.ToLower(strings.TrimSpace(name))]
+		
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have cache control %q", directive),
+			Matches:     ok && (!hasValue || got == strings.TrimSpace(value)),
+			But:         fmt.Sprintf("it was %q", header),
+			SubjectName: subjectNameHTTPResp,
+		}
+		if header == "" {
+			result.But = "it had no Cache-Control header"
+		}
+		return withDump(result, res)
+	}
+}
+
+// MaxAge returns a matcher that checks the max-age directive of the Cache-Control header of
+// the response meets the matcher's criteria.
+func MaxAge(matcher expect.Matcher[time.Duration]) expect.Matcher[*http.Response] {
+	return func(res *http.Response) expect.MatchResult {
+		header := strings.Join(res.Header.Values("Cache-Control"), ", ")
+		value, ok := cacheDirectives(header)["max-age"]
+		seconds, err := strconv.Atoi(value)
+		if !ok || err != nil || seconds < 0 {
+			but := fmt.Sprintf("it had no max-age in %q", header)
+			if ok {
+				but = fmt.Sprintf("its max-age %q was not a number of seconds", value)
+			}
+			return withDump(expect.MatchResult{
+				Description: "have a max-age",
+				But:         but,
+				SubjectName: subjectNameHTTPResp,
+			}, res)
+		}
+		
+		result := matcher(time.Duration(seconds) * time.Second)
+		result = result.WithDescription("have max-age " + be.Complement(result))
+		result.SubjectName = subjectNameHTTPResp
+		return withDump(result, res)
+	}
+}
+
+// cacheDirectives parses a Cache-Control header into its directives, keyed by lowercase name
+// with any quotes removed from the values.
+func cacheDirectives(header string) map[string]string {
+	directives := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name == "" {
+			continue
+		}
+		directives[strings.ToLower(name)] = strings.Trim(value, `"`)
+	}
+	return directives
+}
+
 // RespBody returns a matcher that checks if the response body meets the given matchers' criteria. Note this will read the entire body using io.ReadAll.
+// The body is buffered on the first read, each matcher receives a fresh reader and res.Body is restored so it can be read again.
 func RespBody(bodyMatchers expect.Matcher[io.Reader]) expect.Matcher[*http.Response] {
 	return func(res *http.Response) expect.MatchResult {
-		result := bodyMatchers(res.Body)
+		data, err := bufferBody(res)
+		result := bodyMatchers(bodyReader(data, err))
 		result.SubjectName = responseBodySubjectName
-		return result
+		return withHeadDump(result, res)
 	}
 }
@@ -2,8 +2,10 @@ package behttp_test
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 	
@@ -32,7 +34,9 @@ func ExampleRespBody_fail() {
 	
 	expect.It(t, res.Result()).To(behttp.RespBody(beio.String(be.Eq("Goodbye, world"))))
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected the response body to be equal to "Goodbye, world", but it was "Hello, world"]
+	// Output: Test failed: [expected the response body to be equal to "Goodbye, world", but it was "Hello, world"
+	// response:
+	// 	HTTP/1.1 200 OK]
 }
 
 func ExampleHeader() {
@@ -79,7 +83,13 @@ func ExampleHeader_multiple_fail() {
 		behttp.Header("Content-Type", "text/html"),
 	)
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected the response to have header "Content-Encoding" of "gzip", but it was "" expected the response to have header "Content-Type" of "text/html", but it was "text/xml"]
+	// Output: Test failed: [expected the response to have header "Content-Encoding" of "gzip", but it was ""
+	// response:
+	// 	HTTP/1.1 200 OK
+	// 	Content-Type: text/xml expected the response to have header "Content-Type" of "text/html", but it was "text/xml"
+	// response:
+	// 	HTTP/1.1 200 OK
+	// 	Content-Type: text/xml]
 }
 
 func ExampleStatus() {
@@ -99,7 +109,9 @@ func ExampleStatus_fail() {
 	
 	expect.It(t, res.Result()).To(behttp.Status(http.StatusNotFound))
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected the response to have status of 404, but it was 418]
+	// Output: Test failed: [expected the response to have status of 404, but it was 418
+	// response:
+	// 	HTTP/1.1 418 I'm a teapot]
 }
 
 func ExampleHeader_fail() {
@@ -109,7 +121,35 @@ func ExampleHeader_fail() {
 	
 	expect.It(t, res.Result()).To(behttp.Header("Content-Type", "text/html"))
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected the response to have header "Content-Type" of "text/html", but it was "text/xml"]
+	// Output: Test failed: [expected the response to have header "Content-Type" of "text/html", but it was "text/xml"
+	// response:
+	// 	HTTP/1.1 200 OK
+	// 	Content-Type: text/xml]
+}
+
+func ExampleHeaderMatching_fail() {
+	t := &expect.SpyTB{}
+	res := httptest.NewRecorder()
+	res.Header().Add("Content-Type", "text/html")
+	res.Header().Add("X-Request-Id", "abc")
+	
+	expect.It(t, res.Result()).To(behttp.HeaderMatching("X-Requestid", be.Eq("abc")))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the response to have header "X-Requestid" equal to "abc", but it was ""
+	// response:
+	// 	HTTP/1.1 200 OK
+	// 	Content-Type: text/html
+	// 	X-Request-Id: abc]
+}
+
+func ExampleCookie() {
+	t := &expect.SpyTB{}
+	res := httptest.NewRecorder()
+	http.SetCookie(res, &http.Cookie{Name: "session", Value: "abc"})
+	
+	expect.It(t, res.Result()).To(behttp.Cookie("session", be.WithAnyValue[*http.Cookie]()))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
 }
 
 func TestHTTPTestMatchers(t *testing.T) {
@@ -148,6 +188,107 @@ func TestHTTPTestMatchers(t *testing.T) {
 			)
 		})
 		
+		t.Run("multiple body matchers each read the full body", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			res.Body.WriteString(`{"name": "Egg", "completed": false}`)
+			result := res.Result()
+			
+			expect.It(t, result).To(
+				behttp.RespBody(beio.String(be.Substring("Egg"))),
+				behttp.RespBody(beio.String(be.Substring("completed"))),
+				behttp.RespBody(bejson.MatchSchema([]byte(`{"required": ["name"]}`))),
+			)
+			
+			body, err := io.ReadAll(result.Body)
+			expect.NoError(t, err)
+			expect.It(t, string(body)).To(be.Eq(`{"name": "Egg", "completed": false}`))
+		})
+		
+		t.Run("ReadBody leaves the body to be read again", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			res.Body.WriteString("Hello, world")
+			result := res.Result()
+			
+			body, err := behttp.ReadBody(result)
+			expect.NoError(t, err)
+			expect.It(t, string(body)).To(be.Eq("Hello, world"))
+			expect.It(t, result).To(behttp.RespBody(beio.String(be.Eq("Hello, world"))))
+			
+			body, err = behttp.ReadBody(&http.Response{})
+			expect.NoError(t, err)
+			expect.It(t, len(body)).To(be.Eq(0))
+		})
+		
+		t.Run("failure includes a dump of the response", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			res.Header().Set("Content-Type", "text/plain")
+			res.Header().Set("Set-Cookie", "session=abc")
+			res.WriteHeader(http.StatusInternalServerError)
+			res.Body.WriteString(strings.Repeat("a", 512+10))
+			
+			spytb.VerifyFailingMatcher(
+				t,
+				res.Result(),
+				behttp.Status(http.StatusOK),
+				"expected the response to have status of 200, but it was 500\n"+
+					"response:\n"+
+					"\tHTTP/1.1 500 Internal Server Error\n"+
+					"\tContent-Type: text/plain\n"+
+					"\tSet-Cookie: [REDACTED]\n"+
+					"\n"+
+					"\t"+strings.Repeat("a", 512)+"... (10 more bytes)",
+			)
+		})
+		
+		t.Run("dump includes the originating request", func(t *testing.T) {
+			res := behttp.Get("/").Headers("Authorization", "Bearer s3cret", "Accept", "*/*").Do(newMux())
+			
+			spytb.VerifyFailingMatcher(
+				t,
+				res,
+				behttp.Dumped(behttp.Status(http.StatusTeapot), behttp.DumpRequest(), behttp.DumpBodyLimit(4)),
+				"expected the response to have status of 418, but it was 200\n"+
+					"request:\n"+
+					"\tGET / HTTP/1.1\n"+
+					"\tHost: example.com\n"+
+					"\tAccept: */*\n"+
+					"\tAuthorization: [REDACTED]\n"+
+					"response:\n"+
+					"\tHTTP/1.1 200 OK\n"+
+					"\n"+
+					"\t"+`{"Na... (43 more bytes)`,
+			)
+		})
+		
+		t.Run("composed matchers give one dump made with the options", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			res.Header().Set("Content-Type", "text/plain")
+			res.Header().Set("X-Api-Key", "s3cret")
+			res.WriteHeader(http.StatusInternalServerError)
+			
+			spyTB := &expect.SpyTB{}
+			expect.It(spyTB, res.Result()).To(
+				behttp.Dumped(
+					behttp.Status(http.StatusOK).And(behttp.HeaderMatching("Content-Type", be.Eq("application/json"))),
+					behttp.RedactHeaders("X-Api-Key"),
+				),
+				behttp.Status(http.StatusOK).Or(behttp.ContentTypeJSON),
+			)
+			
+			expect.It(t, spyTB.ErrorCalls).To(be.Size[string](be.Eq(2)))
+			expect.It(t, spyTB.ErrorCalls[0]).To(
+				be.Substring("expected the response to have status of 200 and have header \"Content-Type\" equal to \"application/json\", but it was 500 and it was \"text/plain\"\n"+
+					"response:\n"+
+					"\tHTTP/1.1 500 Internal Server Error\n"+
+					"\tContent-Type: text/plain\n"+
+					"\tX-Api-Key: [REDACTED]\n"),
+				be.Not(be.Substring("s3cret")),
+			)
+			for _, failure := range spyTB.ErrorCalls {
+				expect.It(t, strings.Count(failure, "response:")).To(be.Eq(1))
+			}
+		})
+		
 		t.Run("example of matching JSON", func(t *testing.T) {
 			type Todo struct {
 				Name        string    `json:"name"`
@@ -306,6 +447,212 @@ func TestHTTPTestMatchers(t *testing.T) {
 				spytb.Error(`expected the response to have header "Content-Type" of "text/html", but it was "text/xml"`),
 			)
 		})
+		
+		t.Run("HeaderMatching", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			res.Header().Add("Cache-Control", "public, max-age=60")
+			res.Header().Add("X-Request-Id", "abc")
+			
+			expect.It(t, res.Result()).To(behttp.HeaderMatching("Cache-Control", be.Substring("max-age=60")))
+			spytb.VerifyFailingMatcher(
+				t,
+				res.Result(),
+				behttp.HeaderMatching("X-Requestid", be.Eq("abc")),
+				"expected the response to have header \"X-Requestid\" equal to \"abc\", but it was \"\"\n"+
+					"response:\n"+
+					"\tHTTP/1.1 200 OK\n"+
+					"\tCache-Control: public, max-age=60\n"+
+					"\tX-Request-Id: abc",
+			)
+		})
+		
+		t.Run("HeaderValues", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			res.Header().Add("Vary", "Accept")
+			res.Header().Add("Vary", "Origin")
+			
+			expect.It(t, res.Result()).To(behttp.HeaderValues("Vary", be.ShallowEq([]string{"Accept", "Origin"})))
+			spytb.VerifyFailingMatcher(
+				t,
+				res.Result(),
+				behttp.HeaderValues("Vary", be.ContainingItem(be.Eq("Cookie"))),
+				"expected the response to have header \"Vary\" values containing an item equal to \"Cookie\", but it did not\n"+
+					"response:\n"+
+					"\tHTTP/1.1 200 OK\n"+
+					"\tVary: Accept, Origin",
+			)
+		})
+		
+		t.Run("NoHeader", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			res.Header().Add("Server", "nginx")
+			
+			expect.It(t, res.Result()).To(behttp.NoHeader("X-Powered-By"))
+			spytb.VerifyFailingMatcher(
+				t,
+				res.Result(),
+				behttp.NoHeader("Server"),
+				`expected the response to not have header "Server", but it was ["nginx"]`,
+			)
+		})
+		
+		t.Run("ContentTypeMedia", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			res.Header().Add("Content-Type", "Text/HTML; charset=utf-8")
+			
+			expect.It(t, res.Result()).To(behttp.ContentTypeMedia("text/html"))
+			spytb.VerifyFailingMatcher(
+				t,
+				res.Result(),
+				behttp.ContentTypeMedia("application/json"),
+				`expected the response to have content type media "application/json", but it was "Text/HTML; charset=utf-8"`,
+			)
+			
+			bad := httptest.NewRecorder()
+			bad.Header().Add("Content-Type", "text/html;;")
+			spytb.VerifyFailingMatcher(
+				t,
+				bad.Result(),
+				behttp.ContentTypeMedia("text/html"),
+				`expected the response to have content type media "text/html", but it was "text/html;;" which could not be parsed`,
+			)
+		})
+		
+		t.Run("ContentLength", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			res.Body.WriteString("hello")
+			
+			expect.It(t, res.Result()).To(behttp.ContentLength(be.Eq[int64](5)))
+			
+			withHeader := httptest.NewRecorder()
+			withHeader.Header().Set("Content-Length", "12")
+			spytb.VerifyFailingMatcher(
+				t,
+				withHeader.Result(),
+				behttp.ContentLength(be.Less[int64](10)),
+				`expected the response to have content length less than 10, but it was 12`,
+			)
+		})
+	})
+	
+	t.Run("redacted header values", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		res.Header().Set("Set-Cookie", "session=s3cret")
+		
+		spytb.VerifyFailingMatcher(t, res.Result(), behttp.Header("Set-Cookie", "session=other"),
+			`expected the response to have header "Set-Cookie" of "session=other", but it was [REDACTED]`,
+		)
+		spytb.VerifyFailingMatcher(t, res.Result(), behttp.NoHeader("Set-Cookie"),
+			`expected the response to not have header "Set-Cookie", but it was [REDACTED]`,
+		)
+		spytb.VerifyFailingMatcher(t, httptest.NewRecorder().Result(), behttp.Header("Set-Cookie", "session=other"),
+			`expected the response to have header "Set-Cookie" of "session=other", but it was ""`,
+		)
+	})
+	
+	t.Run("Caching", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		res.Header().Set("Cache-Control", `public, Max-Age=60, no-cache="Set-Cookie"`)
+		
+		expect.It(t, res.Result()).To(
+			behttp.CacheControl("public"),
+			behttp.CacheControl("max-age=60"),
+			behttp.CacheControl("no-cache"),
+			behttp.MaxAge(be.Eq(time.Minute)),
+		)
+		
+		spytb.VerifyFailingMatcher(t, res.Result(), behttp.CacheControl("no-store"),
+			`expected the response to have cache control "no-store", but it was "public, Max-Age=60, no-cache=\"Set-Cookie\""`,
+		)
+		spytb.VerifyFailingMatcher(t, res.Result(), behttp.CacheControl("max-age=3600"),
+			`expected the response to have cache control "max-age=3600"`,
+		)
+		spytb.VerifyFailingMatcher(t, res.Result(), behttp.MaxAge(be.Greater(time.Hour)),
+			`expected the response to have max-age greater than 1h0m0s, but it was 1m0s`,
+		)
+		spytb.VerifyFailingMatcher(t, httptest.NewRecorder().Result(), behttp.CacheControl("no-store"),
+			`expected the response to have cache control "no-store", but it had no Cache-Control header`,
+		)
+		spytb.VerifyFailingMatcher(t, httptest.NewRecorder().Result(), behttp.MaxAge(be.Greater(time.Duration(0))),
+			`expected the response to have a max-age, but it had no max-age in ""`,
+		)
+	})
+	
+	t.Run("Status class", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		res.WriteHeader(http.StatusNoContent)
+		expect.It(t, res.Result()).To(behttp.StatusClass(2))
+		
+		failed := httptest.NewRecorder()
+		failed.WriteHeader(http.StatusBadGateway)
+		failed.Body.WriteString("upstream timed out")
+		spytb.VerifyFailingMatcher(
+			t,
+			failed.Result(),
+			behttp.StatusClass(2),
+			"expected the response to have a 2xx status, but it was 502\n"+
+				"response:\n"+
+				"\tHTTP/1.1 502 Bad Gateway\n"+
+				"\n"+
+				"\tupstream timed out",
+		)
+	})
+	
+	t.Run("RedirectTo", func(t *testing.T) {
+		redirect := func(code int, location string) *http.Response {
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Location", location)
+			rec.WriteHeader(code)
+			return rec.Result()
+		}
+		
+		expect.It(t, redirect(http.StatusFound, "/login")).To(behttp.RedirectTo("/login"))
+		spytb.VerifyFailingMatcher(
+			t,
+			redirect(http.StatusSeeOther, "/home"),
+			behttp.RedirectTo("/login"),
+			`expected the response to redirect to "/login", but it redirected to "/home"`,
+		)
+		spytb.VerifyFailingMatcher(
+			t,
+			redirect(http.StatusOK, "/login"),
+			behttp.RedirectTo("/login"),
+			`expected the response to redirect to "/login", but it had status 200 with Location "/login"`,
+		)
+	})
+	
+	t.Run("Cookie", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		http.SetCookie(res, &http.Cookie{Name: "session", Value: "abc", HttpOnly: true})
+		http.SetCookie(res, &http.Cookie{Name: "theme", Value: "dark"})
+		
+		httpOnly := func(c *http.Cookie) expect.MatchResult {
+			return expect.MatchResult{
+				Description: "be http only",
+				Matches:     c.HttpOnly,
+				But:         "it was not",
+			}
+		}
+		
+		expect.It(t, res.Result()).To(behttp.Cookie("session", httpOnly))
+		spytb.VerifyFailingMatcher(
+			t,
+			res.Result(),
+			behttp.Cookie("theme", httpOnly),
+			`expected the response to have cookie "theme" http only, but it was not`,
+		)
+		spytb.VerifyFailingMatcher(
+			t,
+			res.Result(),
+			behttp.Cookie("sesion", httpOnly),
+			`expected the response to have cookie "sesion", but it did not; present cookies: session, theme`,
+		)
+		spytb.VerifyFailingMatcher(
+			t,
+			httptest.NewRecorder().Result(),
+			behttp.Cookie("session", httpOnly),
+			`expected the response to have cookie "session", but it had no cookies`,
+		)
 	})
 }
 
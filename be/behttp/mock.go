@@ -0,0 +1,342 @@
+package behttp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+// MockTB is the subset of testing.TB a Mock needs to verify itself once the test completes.
+type MockTB interface {
+	expect.TB
+	Cleanup(func())
+}
+
+// Mock is a scriptable stand-in for an HTTP service, built on httptest.Server. Routes
+// are declared with request matchers and canned responses. When the test completes, the
+// server is closed and every route's call count expectation is verified, as are any
+// requests that matched no route.
+//
+//	mock := behttp.NewMock(t)
+//	mock.On(http.MethodGet, "/todos/1").
+//		MatchHeaders("Accept", "application/json").
+//		Reply(http.StatusOK, `{"name":"Egg"}`).
+//		Times(1)
+//
+//	client := todos.NewClient(mock.URL)
+type Mock struct {
+	*httptest.Server
+
+	t MockTB
+
+	mu        sync.Mutex
+	routes    []*Route
+	unmatched []unmatchedRequest
+	// verified is set once the routes have been verified, so the check when the test
+	// completes does not report them again.
+	verified bool
+}
+
+type unmatchedRequest struct {
+	line    string
+	closest *Route
+	reasons []string
+}
+
+// NewMock starts a Mock server. It is closed and verified with t.Cleanup.
+func NewMock(t MockTB) *Mock {
+	m := &Mock{t: t}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	t.Cleanup(func() {
+		t.Helper()
+		m.Close()
+		m.verify(false)
+	})
+	return m
+}
+
+// On declares a route for requests with the given method and path. An empty method
+// matches any method. Additional request matchers narrow the route further. Routes
+// are matched in the order they are declared, and by default are expected to be
+// called at least once.
+func (m *Mock) On(method, path string, matchers ...expect.Matcher[*http.Request]) *Route {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := path
+	if method != "" {
+		name = method + " " + path
+		matchers = append([]expect.Matcher[*http.Request]{Method(method)}, matchers...)
+	}
+	matchers = append([]expect.Matcher[*http.Request]{Path(path)}, matchers...)
+
+	r := &Route{
+		mu:       &m.mu,
+		name:     name,
+		matchers: matchers,
+		reply:    reply{status: http.StatusOK},
+		min:      1,
+		max:      -1,
+	}
+	m.routes = append(m.routes, r)
+	return r
+}
+
+// Verify checks every route's call count expectation and reports any requests that
+// matched no route since the last Verify. It is called automatically when the test
+// completes, which only checks the routes when Verify has not been called already.
+func (m *Mock) Verify() {
+	m.t.Helper()
+	m.verify(true)
+}
+
+func (m *Mock) verify(routes bool) {
+	m.t.Helper()
+
+	m.mu.Lock()
+	var checked []*Route
+	if routes || !m.verified {
+		checked = slices.Clone(m.routes)
+	}
+	results := make([]expect.MatchResult, 0, len(checked))
+	for _, r := range checked {
+		results = append(results, beCalledExpectedTimes(r))
+	}
+	m.verified = true
+	unmatched := m.unmatched
+	m.unmatched = nil
+	m.mu.Unlock()
+
+	// report after unlocking, so a TB calling back into the mock does not deadlock
+	for i, r := range checked {
+		expect.It(m.t, r).To(func(*Route) expect.MatchResult { return results[i] })
+	}
+
+	for _, u := range unmatched {
+		msg := fmt.Sprintf("unexpected request %s matched no route", u.line)
+		if u.closest != nil {
+			msg += fmt.Sprintf("; closest %s failed:\n\t%s", u.closest, strings.Join(u.reasons, "\n\t"))
+		}
+		m.t.Error(msg)
+	}
+}
+
+func (m *Mock) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	// buffer the body upfront so every route's matchers can read it
+	_, _ = buffer(&req.Body)
+
+	// the matchers are run without the lock, so they can call back into the mock
+	m.mu.Lock()
+	routes := make([]routeMatchers, 0, len(m.routes))
+	for _, r := range m.routes {
+		routes = append(routes, routeMatchers{route: r, matchers: slices.Clip(r.matchers)})
+	}
+	m.mu.Unlock()
+
+	var (
+		matched     *Route
+		closest     *Route
+		bestReasons []string
+		bestScore   float64
+	)
+	for _, r := range routes {
+		reasons := r.mismatches(req)
+		if len(reasons) == 0 {
+			matched = r.route
+			break
+		}
+		// the closest route is the one with the largest share of passing matchers
+		score := float64(len(r.matchers)-len(reasons)) / float64(len(r.matchers))
+		if closest == nil || score > bestScore {
+			closest, bestReasons, bestScore = r.route, reasons, score
+		}
+	}
+
+	m.mu.Lock()
+	var response reply
+	if matched != nil {
+		matched.calls++
+		response = matched.reply
+	} else {
+		m.unmatched = append(m.unmatched, unmatchedRequest{
+			line:    req.Method + " " + req.URL.RequestURI(),
+			closest: closest,
+			reasons: bestReasons,
+		})
+	}
+	m.mu.Unlock()
+
+	if matched == nil {
+		http.Error(w, "behttp.Mock: no route matched "+req.Method+" "+req.URL.RequestURI(), http.StatusNotImplemented)
+		return
+	}
+	response.respond(w, req)
+}
+
+// routeMatchers is a route with the matchers it had when a request arrived, so they can be
+// run without holding the Mock's lock.
+type routeMatchers struct {
+	route    *Route
+	matchers []expect.Matcher[*http.Request]
+}
+
+func (r routeMatchers) mismatches(req *http.Request) []string {
+	var reasons []string
+	for _, matcher := range r.matchers {
+		result := matcher(req)
+		if result.Matches {
+			continue
+		}
+		if result.SubjectName == "" {
+			result.SubjectName = subjectNameHTTPReq
+		}
+		reasons = append(reasons, result.Error())
+	}
+	return reasons
+}
+
+// Route is a single route declared on a Mock, see Mock.On. A route can be changed while the
+// Mock is serving, its methods hold the Mock's lock.
+type Route struct {
+	// mu is the lock of the Mock the route was declared on.
+	mu *sync.Mutex
+
+	name     string
+	matchers []expect.Matcher[*http.Request]
+	reply    reply
+
+	// min and max bound the expected call count, a max of -1 is unbounded.
+	min, max int
+	calls    int
+}
+
+// reply is the canned response of a route. It is copied under the lock before it is
+// written, so it is not read while the route is being changed.
+type reply struct {
+	status  int
+	body    string
+	headers []string
+	handler http.Handler
+}
+
+// MatchHeaders restricts the route to requests with the given headers, following
+// the same key/value list convention as Req.Headers.
+func (r *Route) MatchHeaders(k, v string, rest ...string) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addList(addFunc(func(name, value string) {
+		r.matchers = append(r.matchers, RequestHeader(name, be.Eq(value)))
+	}), append([]string{k, v}, rest...)...)
+	return r
+}
+
+// MatchQueries restricts the route to requests with the given query parameters,
+// following the same key/value list convention as Req.Queries.
+func (r *Route) MatchQueries(k, v string, rest ...string) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	addList(addFunc(func(name, value string) {
+		r.matchers = append(r.matchers, Query(name, be.Eq(value)))
+	}), append([]string{k, v}, rest...)...)
+	return r
+}
+
+// Match restricts the route further with the given request matchers.
+func (r *Route) Match(matchers ...expect.Matcher[*http.Request]) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.matchers = append(r.matchers, matchers...)
+	return r
+}
+
+// Reply sets the canned response status and body.
+func (r *Route) Reply(status int, body string) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reply.status, r.reply.body = status, body
+	return r
+}
+
+// ReplyHeaders sets headers on the canned response, following the same key/value
+// list convention as Req.Headers.
+func (r *Route) ReplyHeaders(k, v string, rest ...string) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// copy rather than append in place, as a reply taken while serving may share the array
+	r.reply.headers = append(slices.Clip(r.reply.headers), k, v)
+	r.reply.headers = append(r.reply.headers, rest...)
+	return r
+}
+
+// ReplyWith serves matched requests with the given handler instead of a canned response.
+func (r *Route) ReplyWith(handler http.Handler) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reply.handler = handler
+	return r
+}
+
+// Times expects the route to be called exactly n times.
+func (r *Route) Times(n int) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.min, r.max = n, n
+	return r
+}
+
+// AtLeast expects the route to be called n or more times.
+func (r *Route) AtLeast(n int) *Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.min, r.max = n, -1
+	return r
+}
+
+// Never expects the route to not be called at all.
+func (r *Route) Never() *Route {
+	return r.Times(0)
+}
+
+func (r *Route) String() string {
+	return "route " + r.name
+}
+
+func (r reply) respond(w http.ResponseWriter, req *http.Request) {
+	if r.handler != nil {
+		r.handler.ServeHTTP(w, req)
+		return
+	}
+	addList(w.Header(), r.headers...)
+	w.WriteHeader(r.status)
+	_, _ = w.Write([]byte(r.body))
+}
+
+func beCalledExpectedTimes(r *Route) expect.MatchResult {
+	var description string
+	switch {
+	case r.max == 0:
+		description = "never be called"
+	case r.min == r.max:
+		description = fmt.Sprintf("be called %d time(s)", r.min)
+	default:
+		description = fmt.Sprintf("be called at least %d time(s)", r.min)
+	}
+	return expect.MatchResult{
+		Description: description,
+		Matches:     r.calls >= r.min && (r.max < 0 || r.calls <= r.max),
+		But:         fmt.Sprintf("it was called %d time(s)", r.calls),
+	}
+}
+
+// addFunc adapts a function to the Add method used by addList.
+type addFunc func(k, v string)
+
+func (f addFunc) Add(k, v string) {
+	f(k, v)
+}
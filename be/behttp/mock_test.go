@@ -0,0 +1,160 @@
+package behttp_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/behttp"
+	"github.com/jsteenb2/expect/be/beio"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func TestMock(t *testing.T) {
+	t.Run("serves canned responses to matching requests", func(t *testing.T) {
+		mock := behttp.NewMock(t)
+		mock.On(http.MethodGet, "/todos/1").
+			MatchHeaders("Accept", "application/json").
+			Reply(http.StatusOK, `{"name":"Egg"}`).
+			ReplyHeaders("Content-Type", "application/json").
+			Times(2)
+		mock.On(http.MethodPost, "/todos", behttp.RequestBody(beio.String(be.Substring("Bacon")))).
+			Reply(http.StatusCreated, "")
+		mock.On("", "/health").Never()
+
+		for range 2 {
+			req, err := http.NewRequest(http.MethodGet, mock.URL+"/todos/1", nil)
+			expect.NoError(t, err)
+			req.Header.Set("Accept", "application/json")
+
+			res, err := mock.Client().Do(req)
+			expect.NoError(t, err)
+			expect.It(t, res).To(
+				behttp.StatusOK(),
+				behttp.ContentTypeJSON,
+				behttp.RespBody(beio.String(be.Eq(`{"name":"Egg"}`))),
+			)
+		}
+
+		res, err := mock.Client().Post(mock.URL+"/todos", "application/json", strings.NewReader(`{"name":"Bacon"}`))
+		expect.NoError(t, err)
+		expect.It(t, res).To(behttp.Status(http.StatusCreated))
+	})
+
+	t.Run("routes can be changed while serving", func(t *testing.T) {
+		mock := behttp.NewMock(t)
+		route := mock.On(http.MethodGet, "/todos").AtLeast(0)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := range 50 {
+				route.Reply(http.StatusOK, "a").ReplyHeaders("X-Try", "1").MatchHeaders("Accept", "*/*")
+				route.AtLeast(i % 2)
+			}
+		}()
+
+		for range 20 {
+			req, err := http.NewRequest(http.MethodGet, mock.URL+"/todos", nil)
+			expect.NoError(t, err)
+			req.Header.Set("Accept", "*/*")
+
+			res, err := mock.Client().Do(req)
+			expect.NoError(t, err)
+			res.Body.Close()
+		}
+		<-done
+	})
+
+	t.Run("matches queries", func(t *testing.T) {
+		mock := behttp.NewMock(t)
+		mock.On(http.MethodGet, "/todos").MatchQueries("page", "2", "sort", "asc").Times(1)
+
+		res, err := mock.Client().Get(mock.URL + "/todos?sort=asc&page=2")
+		expect.NoError(t, err)
+		expect.It(t, res).To(behttp.StatusOK())
+	})
+
+	t.Run("reports call count expectations", func(t *testing.T) {
+		spyTB := &cleanupSpyTB{}
+		mock := behttp.NewMock(spyTB)
+		mock.On(http.MethodGet, "/once").Times(1)
+		mock.On(http.MethodGet, "/always")
+		mock.On(http.MethodGet, "/never").Never()
+
+		for range 2 {
+			_, err := mock.Client().Get(mock.URL + "/once")
+			expect.NoError(t, err)
+		}
+		_, err := mock.Client().Get(mock.URL + "/never")
+		expect.NoError(t, err)
+
+		spyTB.runCleanups()
+		expect.It(t, &spyTB.SpyTB).To(
+			spytb.Error("expected route GET /once to be called 1 time(s), but it was called 2 time(s)"),
+			spytb.Error("expected route GET /always to be called at least 1 time(s), but it was called 0 time(s)"),
+			spytb.Error("expected route GET /never to never be called, but it was called 1 time(s)"),
+		)
+	})
+
+	t.Run("Verify reports failures once", func(t *testing.T) {
+		spyTB := &cleanupSpyTB{}
+		mock := behttp.NewMock(spyTB)
+		mock.On(http.MethodGet, "/once").Times(1)
+
+		mock.Verify()
+		spyTB.runCleanups()
+		expect.It(t, spyTB.ErrorCalls).To(be.Size[string](be.Eq(1)))
+	})
+
+	t.Run("route matchers can call back into the mock", func(t *testing.T) {
+		mock := behttp.NewMock(t)
+		mock.On(http.MethodGet, "/inner").Reply(http.StatusOK, "inner")
+		mock.On(http.MethodGet, "/outer", func(*http.Request) expect.MatchResult {
+			res, err := mock.Client().Get(mock.URL + "/inner")
+			if err != nil {
+				return expect.MatchResult{Description: "reach the inner route", But: err.Error()}
+			}
+			res.Body.Close()
+			return expect.MatchResult{Description: "reach the inner route", Matches: res.StatusCode == http.StatusOK}
+		}).Reply(http.StatusOK, "outer")
+
+		res, err := mock.Client().Get(mock.URL + "/outer")
+		expect.NoError(t, err)
+		expect.It(t, res).To(behttp.RespBody(beio.String(be.Eq("outer"))))
+	})
+
+	t.Run("reports unmatched requests with the closest route", func(t *testing.T) {
+		spyTB := &cleanupSpyTB{}
+		mock := behttp.NewMock(spyTB)
+		mock.On(http.MethodPost, "/todos").AtLeast(0)
+		mock.On(http.MethodGet, "/todos").MatchHeaders("Accept", "application/json").AtLeast(0)
+
+		res, err := mock.Client().Get(mock.URL + "/todos")
+		expect.NoError(t, err)
+		expect.It(t, res).To(behttp.Status(http.StatusNotImplemented))
+
+		spyTB.runCleanups()
+		expect.It(t, &spyTB.SpyTB).To(
+			spytb.Error(`unexpected request GET /todos matched no route; closest route GET /todos failed:`),
+			spytb.Error(`expected the request to have header "Accept" equal to "application/json", but it was ""`),
+		)
+	})
+}
+
+type cleanupSpyTB struct {
+	expect.SpyTB
+	cleanups []func()
+}
+
+func (c *cleanupSpyTB) Cleanup(f func()) {
+	c.cleanups = append(c.cleanups, f)
+}
+
+func (c *cleanupSpyTB) runCleanups() {
+	for i := len(c.cleanups) - 1; i >= 0; i-- {
+		c.cleanups[i]()
+	}
+}
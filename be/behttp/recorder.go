@@ -0,0 +1,103 @@
+package behttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Recorder captures every request it receives so they can be asserted on with
+// expect.It. It is both an http.Handler, for use with httptest.NewServer, and an
+// http.RoundTripper, for use as an http.Client's Transport.
+//
+//	rec := behttp.NewRecorder(nil)
+//	client := &http.Client{Transport: rec}
+//	// exercise the code under test with client...
+//	expect.It(t, rec.Last()).To(behttp.Method(http.MethodPost), behttp.Path("/todos"))
+type Recorder struct {
+	next http.Handler
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+// NewRecorder creates a Recorder which responds to requests using next. When next
+// is nil, every request receives an empty 200 OK response.
+func NewRecorder(next http.Handler) *Recorder {
+	if next == nil {
+		next = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+	}
+	return &Recorder{next: next}
+}
+
+// NewRecordingServer starts an httptest.Server that records every request it
+// receives. The caller is responsible for closing the server.
+func NewRecordingServer(next http.Handler) (*httptest.Server, *Recorder) {
+	rec := NewRecorder(next)
+	return httptest.NewServer(rec), rec
+}
+
+// ServeHTTP records the request and then serves it with the wrapped handler.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.next.ServeHTTP(w, r.record(req))
+}
+
+// RoundTrip records the request and then serves it in process with the wrapped handler.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := r.record(req)
+
+	rec := httptest.NewRecorder()
+	r.next.ServeHTTP(rec, clone)
+
+	res := rec.Result()
+	res.Request = req
+	return res, nil
+}
+
+// Requests returns every request recorded so far, in the order they were received.
+func (r *Recorder) Requests() []*http.Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*http.Request(nil), r.requests...)
+}
+
+// Last returns the most recently recorded request, or nil when none have been received.
+func (r *Recorder) Last() *http.Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.requests) == 0 {
+		return nil
+	}
+	return r.requests[len(r.requests)-1]
+}
+
+// Reset discards all recorded requests.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = nil
+}
+
+// record stores a copy of the request with its body buffered, so it can still be
+// inspected once the request has been served. The returned request is handed to
+// the wrapped handler.
+func (r *Recorder) record(req *http.Request) *http.Request {
+	recorded := req.Clone(context.WithoutCancel(req.Context()))
+	data, err := buffer(&recorded.Body)
+
+	// like net/http, handlers are always given a non-nil body
+	served := req.Clone(req.Context())
+	served.Body = http.NoBody
+	if recorded.Body != nil {
+		served.Body = newReplayBody(data, err)
+	}
+
+	r.mu.Lock()
+	r.requests = append(r.requests, recorded)
+	r.mu.Unlock()
+
+	return served
+}
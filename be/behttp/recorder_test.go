@@ -0,0 +1,94 @@
+package behttp_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/behttp"
+	"github.com/jsteenb2/expect/be/beio"
+)
+
+func ExampleRecorder() {
+	t := &expect.SpyTB{}
+	rec := behttp.NewRecorder(nil)
+	client := &http.Client{Transport: rec}
+
+	res, err := client.Post("http://example.com/todos", "application/json", strings.NewReader(`{"name":"Egg"}`))
+	expect.NoError(t, err)
+	res.Body.Close()
+
+	expect.It(t, rec.Last()).To(
+		behttp.Method(http.MethodPost),
+		behttp.Path("/todos"),
+		behttp.RequestHeader("Content-Type", be.Eq("application/json")),
+		behttp.RequestBody(beio.String(be.Eq(`{"name":"Egg"}`))),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func TestRecorder(t *testing.T) {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.Copy(w, req.Body)
+	})
+
+	t.Run("as a round tripper", func(t *testing.T) {
+		rec := behttp.NewRecorder(echo)
+		client := &http.Client{Transport: rec}
+
+		res, err := client.Post("http://example.com/echo", "text/plain", strings.NewReader("hello"))
+		expect.NoError(t, err)
+
+		expect.It(t, res).To(
+			behttp.Status(http.StatusCreated),
+			behttp.RespBody(beio.String(be.Eq("hello"))),
+		)
+		expect.It(t, rec.Requests()).To(be.Size[*http.Request](be.Eq(1)))
+		expect.It(t, rec.Last()).To(behttp.RequestBody(beio.String(be.Eq("hello"))))
+	})
+
+	t.Run("a request without a body is served with an empty one", func(t *testing.T) {
+		rec := behttp.NewRecorder(echo)
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/echo", nil)
+		expect.NoError(t, err)
+		expect.It(t, req.Body == nil).To(be.Eq(true))
+
+		res, err := rec.RoundTrip(req)
+		expect.NoError(t, err)
+		expect.It(t, res).To(
+			behttp.Status(http.StatusCreated),
+			behttp.RespBody(beio.String(be.Eq(""))),
+		)
+	})
+
+	t.Run("as a server", func(t *testing.T) {
+		svr, rec := behttp.NewRecordingServer(echo)
+		defer svr.Close()
+
+		for _, body := range []string{"first", "second"} {
+			res, err := svr.Client().Post(svr.URL+"/echo?n=1", "text/plain", strings.NewReader(body))
+			expect.NoError(t, err)
+			expect.It(t, res).To(behttp.RespBody(beio.String(be.Eq(body))))
+			res.Body.Close()
+		}
+
+		requests := rec.Requests()
+		expect.It(t, requests).To(be.Size[*http.Request](be.Eq(2)))
+		expect.It(t, requests[0]).To(
+			behttp.Method(http.MethodPost),
+			behttp.Path("/echo"),
+			behttp.Query("n", be.Eq("1")),
+			behttp.RequestBody(beio.String(be.Eq("first"))),
+		)
+		expect.It(t, requests[1]).To(behttp.RequestBody(beio.String(be.Eq("second"))))
+
+		rec.Reset()
+		expect.It(t, rec.Last() == nil).To(be.Eq(true))
+	})
+}
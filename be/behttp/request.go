@@ -0,0 +1,155 @@
+package behttp
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+const (
+	subjectNameHTTPReq     = "the request"
+	requestBodySubjectName = subjectNameHTTPReq + " body"
+)
+
+// Method returns a matcher that checks if the request method is equal to the given method.
+func Method(method string) expect.Matcher[*http.Request] {
+	return func(req *http.Request) expect.MatchResult {
+		return expect.MatchResult{
+			Description: fmt.Sprintf("have method %s", method),
+			Matches:     req.Method == method,
+			But:         fmt.Sprintf("it was %s", req.Method),
+			SubjectName: subjectNameHTTPReq,
+		}
+	}
+}
+
+// Path returns a matcher that checks if the request URL path is equal to the given path.
+func Path(path string) expect.Matcher[*http.Request] {
+	return func(req *http.Request) expect.MatchResult {
+		return expect.MatchResult{
+			Description: fmt.Sprintf("have path %q", path),
+			Matches:     req.URL.Path == path,
+			But:         fmt.Sprintf("it was %q", req.URL.Path),
+			SubjectName: subjectNameHTTPReq,
+		}
+	}
+}
+
+// Query returns a matcher that checks the first value of the named query parameter meets the matcher's criteria.
+// A missing parameter is matched as the empty string.
+func Query(key string, matcher expect.Matcher[string]) expect.Matcher[*http.Request] {
+	return func(req *http.Request) expect.MatchResult {
+		result := matcher(req.URL.Query().Get(key))
+		result = result.WithDescription(fmt.Sprintf("have query %q %s", key, be.Complement(result)))
+		result.SubjectName = subjectNameHTTPReq
+		if !result.Matches {
+			result.But = withQuery(result.But, req)
+		}
+		return result
+	}
+}
+
+// RequestHeader returns a matcher that checks the first value of the named request header meets the matcher's criteria.
+// A missing header is matched as the empty string.
+func RequestHeader(header string, matcher expect.Matcher[string]) expect.Matcher[*http.Request] {
+	return func(req *http.Request) expect.MatchResult {
+		result := matcher(req.Header.Get(header))
+		result = result.WithDescription(fmt.Sprintf("have header %q %s", header, be.Complement(result)))
+		result.SubjectName = subjectNameHTTPReq
+		if !result.Matches {
+			result.But = withHeaderNames(result.But, req.Header)
+		}
+		return result
+	}
+}
+
+// RequestBody returns a matcher that checks if the request body meets the given matcher's criteria.
+// The body is buffered and restored on the request so it can be read again.
+func RequestBody(bodyMatcher expect.Matcher[io.Reader]) expect.Matcher[*http.Request] {
+	return func(req *http.Request) expect.MatchResult {
+		data, err := buffer(&req.Body)
+		result := bodyMatcher(bodyReader(data, err))
+		result.SubjectName = requestBodySubjectName
+		return result
+	}
+}
+
+// BasicAuth returns a matcher that checks the request carries basic auth credentials for the given user and password.
+func BasicAuth(user, password string) expect.Matcher[*http.Request] {
+	return func(req *http.Request) expect.MatchResult {
+		gotUser, gotPassword, ok := req.BasicAuth()
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have basic auth for user %q", user),
+			Matches:     ok && gotUser == user && gotPassword == password,
+			SubjectName: subjectNameHTTPReq,
+		}
+		switch {
+		case !ok:
+			result.But = "it had no basic auth credentials"
+		case gotUser != user:
+			result.But = fmt.Sprintf("it was for user %q", gotUser)
+		case gotPassword != password:
+			result.But = "the password did not match"
+		}
+		return result
+	}
+}
+
+// BearerToken returns a matcher that checks the request has an Authorization header with the given bearer token.
+func BearerToken(token string) expect.Matcher[*http.Request] {
+	return func(req *http.Request) expect.MatchResult {
+		got, ok := bearerToken(req.Header.Get("Authorization"))
+		result := expect.MatchResult{
+			Description: "have the expected bearer token",
+			Matches:     ok && got == token,
+			SubjectName: subjectNameHTTPReq,
+		}
+		switch {
+		case !ok:
+			result.But = "it had no bearer token"
+		case got != token:
+			result.But = "the token did not match"
+		}
+		return result
+	}
+}
+
+func bearerToken(auth string) (string, bool) {
+	const prefix = "Bearer "
+	if len(auth) < len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return "", false
+	}
+	return auth[len(prefix):], true
+}
+
+func withQuery(but string, req *http.Request) string {
+	query := "query: " + req.URL.RawQuery
+	if req.URL.RawQuery == "" {
+		query = "it had no query"
+	}
+	if but == "" {
+		return query
+	}
+	return but + "; " + query
+}
+
+// withHeaderNames appends the names of every header present to the failure
+// message, which makes typos in header names easy to spot.
+func withHeaderNames(but string, header http.Header) string {
+	names := slices.Sorted(maps.Keys(header))
+
+	present := "no headers were present"
+	if len(names) > 0 {
+		present = "present headers: " + strings.Join(names, ", ")
+	}
+	if but == "" {
+		return present
+	}
+	return but + "; " + present
+}
@@ -1,18 +1,48 @@
 package behttp
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"io"
+	"maps"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	
+	"github.com/jsteenb2/expect"
 )
 
 // Req is a request builder.
 type Req struct {
-	addr    string
-	method  string
-	body    io.Reader
-	headers []string
-	queries []string
+	addr        string
+	method      string
+	body        io.Reader
+	contentType string
+	headers     []string
+	queries     []string
+	cookies     []*http.Cookie
+	ctx         context.Context
+	host        string
+	remoteAddr  string
+	basicAuth   *credentials
+}
+
+type credentials struct {
+	user, password string
+}
+
+// MultipartFile is a file part of a multipart request body, see Req.Multipart.
+type MultipartFile struct {
+	// Field is the form field name of the part.
+	Field string
+	// Name is the file name of the part.
+	Name string
+	// Content is read in full to provide the contents of the part.
+	Content io.Reader
 }
 
 // Request runs creates a request for an http call. Use this if you need a
@@ -50,6 +80,87 @@ func Put(addr string, body io.Reader) *Req {
 	return Request(http.MethodPut, addr, body)
 }
 
+// JSONBody encodes v as the request body and sets the Content-Type to application/json.
+// The test is failed with t.Fatalf when v cannot be encoded, which is why it takes the TB:
+// the builder has no other way to report the error without panicking.
+func (r *Req) JSONBody(t expect.TB, v any) *Req {
+	t.Helper()
+	
+	b, err := json.Marshal(v)
+	expect.NoError(t, err)
+	
+	r.body = bytes.NewReader(b)
+	r.contentType = "application/json"
+	return r
+}
+
+// FormBody encodes the values as the request body and sets the Content-Type to application/x-www-form-urlencoded.
+func (r *Req) FormBody(values url.Values) *Req {
+	r.body = strings.NewReader(values.Encode())
+	r.contentType = "application/x-www-form-urlencoded"
+	return r
+}
+
+// Multipart encodes the fields and files as a multipart/form-data request body. The
+// test is failed with t.Fatalf when a file's content cannot be read.
+func (r *Req) Multipart(t expect.TB, fields url.Values, files ...MultipartFile) *Req {
+	t.Helper()
+	
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		for _, v := range fields[k] {
+			expect.NoError(t, w.WriteField(k, v))
+		}
+	}
+	for _, f := range files {
+		part, err := w.CreateFormFile(f.Field, f.Name)
+		expect.NoError(t, err)
+		_, err = io.Copy(part, f.Content)
+		expect.NoError(t, err)
+	}
+	expect.NoError(t, w.Close())
+	
+	r.body = &buf
+	r.contentType = w.FormDataContentType()
+	return r
+}
+
+// BasicAuth sets the Authorization header to use basic auth with the given credentials.
+func (r *Req) BasicAuth(user, password string) *Req {
+	r.basicAuth = &credentials{user: user, password: password}
+	return r
+}
+
+// Bearer sets the Authorization header to use the given bearer token.
+func (r *Req) Bearer(token string) *Req {
+	return r.Headers("Authorization", "Bearer "+token)
+}
+
+// Cookie adds the cookie to the request.
+func (r *Req) Cookie(c *http.Cookie) *Req {
+	r.cookies = append(r.cookies, c)
+	return r
+}
+
+// Context sets the request's context.
+func (r *Req) Context(ctx context.Context) *Req {
+	r.ctx = ctx
+	return r
+}
+
+// Host sets the request's Host, overriding the host from the address.
+func (r *Req) Host(host string) *Req {
+	r.host = host
+	return r
+}
+
+// RemoteAddr sets the request's RemoteAddr, as seen by the handler.
+func (r *Req) RemoteAddr(addr string) *Req {
+	r.remoteAddr = addr
+	return r
+}
+
 // Headers allows the user to set headers on the http request.
 func (r *Req) Headers(k, v string, rest ...string) *Req {
 	r.headers = append(r.headers, k, v)
@@ -57,6 +168,7 @@ func (r *Req) Headers(k, v string, rest ...string) *Req {
 	return r
 }
 
+// Queries allows the user to set query parameters on the http request.
 func (r *Req) Queries(k, v string, rest ...string) *Req {
 	r.queries = append(r.queries, k, v)
 	r.queries = append(r.queries, rest...)
@@ -65,18 +177,104 @@ func (r *Req) Queries(k, v string, rest ...string) *Req {
 
 // Do runs the request against the provided handler.
 func (r *Req) Do(handler http.Handler) *http.Response {
-	req := httptest.NewRequest(r.method, r.addr, r.body)
+	return serve(handler, r.serverRequest())
+}
+
+// Build creates the request for sending with an http.Client, so the address must be
+// absolute. Use Send to join the address to a server's URL and send it, or Do to serve
+// it in process. RemoteAddr is set on the request but has no effect on a client.
+func (r *Req) Build() (*http.Request, error) {
+	req, err := r.clientRequest(r.addr)
+	if err != nil {
+		return nil, err
+	}
+	if r.remoteAddr != "" {
+		req.RemoteAddr = r.remoteAddr
+	}
+	return req, nil
+}
+
+// Send makes the request with the client against the server at baseURL, for
+// example an httptest.Server's URL. The request address is joined to baseURL.
+// When client is nil, http.DefaultClient is used. RemoteAddr has no effect on
+// requests made with Send.
+func (r *Req) Send(client *http.Client, baseURL string) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	
+	req, err := r.clientRequest(joinURL(baseURL, r.addr))
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+func (r *Req) clientRequest(url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(r.context(), r.method, url, r.body)
+	if err != nil {
+		return nil, err
+	}
+	r.apply(req)
+	return req, nil
+}
+
+// serverRequest creates the request as an http.Handler receives it, ready to be served
+// in process.
+func (r *Req) serverRequest() *http.Request {
+	req := httptest.NewRequestWithContext(r.context(), r.method, r.addr, r.body)
+	r.apply(req)
+	if r.remoteAddr != "" {
+		req.RemoteAddr = r.remoteAddr
+	}
+	return req
+}
+
+// serve runs the request against the handler, recording the response. The
+// request is set on the response, as it would be for a client request.
+func serve(handler http.Handler, req *http.Request) *http.Response {
 	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
 	
+	res := rec.Result()
+	res.Request = req
+	return res
+}
+
+func (r *Req) context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+// apply sets the headers, queries, cookies and auth from the builder on the request.
+func (r *Req) apply(req *http.Request) {
 	addList(req.Header, r.headers...)
+	if r.contentType != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
 	
 	q := req.URL.Query()
 	addList(q, r.queries...)
 	req.URL.RawQuery = q.Encode()
 	
-	handler.ServeHTTP(rec, req)
-	
-	return rec.Result()
+	for _, c := range r.cookies {
+		req.AddCookie(c)
+	}
+	if r.basicAuth != nil {
+		req.SetBasicAuth(r.basicAuth.user, r.basicAuth.password)
+	}
+	if r.host != "" {
+		req.Host = r.host
+	}
+}
+
+func joinURL(baseURL, addr string) string {
+	if baseURL == "" {
+		return addr
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(addr, "/")
 }
 
 func addList(dst interface{ Add(k, v string) }, list ...string) {
@@ -2,16 +2,23 @@ package behttp_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	
 	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
 	"github.com/jsteenb2/expect/be/behttp"
+	"github.com/jsteenb2/expect/be/beio"
 	"github.com/jsteenb2/expect/be/bejson"
+	"github.com/jsteenb2/expect/spytb"
 )
 
 func TestHTTP(t *testing.T) {
@@ -88,6 +95,193 @@ func TestHTTP(t *testing.T) {
 			behttp.Header("ru", "bar").And(behttp.Header("red", "licorice")),
 		)
 	})
+	
+	t.Run("JSON body", func(t *testing.T) {
+		resp := behttp.Post("/echo", nil).JSONBody(t, foo{Name: "name"}).Do(svr)
+		
+		expect.It(t, resp).To(
+			behttp.StatusOK(),
+			behttp.Header("Echo-Content-Type", "application/json"),
+			behttp.RespBody(beio.String(be.Eq(`{"Name":"name","Thing":"","Method":""}`))),
+		)
+	})
+	
+	t.Run("JSON body that cannot be encoded", func(t *testing.T) {
+		spyTB := &expect.SpyTB{}
+		behttp.Get("/echo").JSONBody(spyTB, func() {})
+		
+		expect.It(t, spyTB).To(spytb.Error("unexpected error: json: unsupported type: func()"))
+	})
+	
+	t.Run("explicit content type wins", func(t *testing.T) {
+		resp := behttp.Put("/echo", nil).JSONBody(t, 1).Headers("Content-Type", "application/vnd.foo+json").Do(svr)
+		
+		expect.It(t, resp).To(behttp.HeaderValues("Echo-Content-Type", be.ShallowEq([]string{"application/vnd.foo+json"})))
+	})
+	
+	t.Run("form body", func(t *testing.T) {
+		resp := behttp.Post("/echo", nil).FormBody(url.Values{"name": {"Egg"}}).Do(svr)
+		
+		expect.It(t, resp).To(
+			behttp.Header("Echo-Content-Type", "application/x-www-form-urlencoded"),
+			behttp.Header("Echo-Form-Name", "Egg"),
+		)
+	})
+	
+	t.Run("multipart body", func(t *testing.T) {
+		resp := behttp.
+			Post("/echo", nil).
+			Multipart(t, url.Values{"name": {"Egg"}}, behttp.MultipartFile{
+				Field:   "upload",
+				Name:    "notes.txt",
+				Content: strings.NewReader("some notes"),
+			}).
+			Do(svr)
+		
+		expect.It(t, resp).To(
+			behttp.HeaderMatching("Echo-Content-Type", be.Substring("multipart/form-data; boundary=")),
+			behttp.Header("Echo-Form-Name", "Egg"),
+			behttp.Header("Echo-File-Upload", "notes.txt: some notes"),
+		)
+	})
+	
+	t.Run("auth, cookies, host and remote addr", func(t *testing.T) {
+		resp := behttp.
+			Get("/echo").
+			BasicAuth("pepper", "s3cret").
+			Cookie(&http.Cookie{Name: "session", Value: "abc"}).
+			Host("example.com").
+			RemoteAddr("10.0.0.1:1234").
+			Do(svr)
+		
+		expect.It(t, resp).To(
+			behttp.Header("Echo-User", "pepper"),
+			behttp.Header("Echo-Cookie-Session", "abc"),
+			behttp.Header("Echo-Host", "example.com"),
+			behttp.Header("Echo-Remote-Addr", "10.0.0.1:1234"),
+		)
+	})
+	
+	t.Run("bearer token", func(t *testing.T) {
+		resp := behttp.Get("/echo").Bearer("tok3n").Do(svr)
+		
+		expect.It(t, resp).To(behttp.Header("Echo-Authorization", "Bearer tok3n"))
+	})
+	
+	t.Run("Build", func(t *testing.T) {
+		req, err := behttp.
+			Put("/queries", strings.NewReader("body")).
+			Queries("ru", "bar").
+			Headers("Foo", "bar").
+			RemoteAddr("10.0.0.1:1234").
+			Build()
+		expect.NoError(t, err)
+		
+		expect.It(t, req).To(
+			behttp.Method(http.MethodPut),
+			behttp.Path("/queries"),
+			behttp.Query("ru", be.Eq("bar")),
+			behttp.RequestHeader("Foo", be.Eq("bar")),
+			behttp.RequestBody(beio.String(be.Eq("body"))),
+		)
+		expect.It(t, req.RemoteAddr).To(be.Eq("10.0.0.1:1234"))
+	})
+	
+	t.Run("context", func(t *testing.T) {
+		type ctxKey struct{}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+		
+		var got any
+		behttp.Get("/").Context(ctx).Do(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			got = req.Context().Value(ctxKey{})
+		}))
+		
+		expect.It(t, got).To(be.Eq[any]("value"))
+	})
+}
+
+func TestHTTPSend(t *testing.T) {
+	svr := httptest.NewServer(newMux())
+	defer svr.Close()
+	
+	t.Run("Get", func(t *testing.T) {
+		resp, err := behttp.Get("/").Send(svr.Client(), svr.URL)
+		expect.NoError(t, err)
+		
+		expect.It(t, resp).To(
+			behttp.StatusOK(),
+			behttp.RespBody(haveFoo(http.MethodGet)),
+		)
+	})
+	
+	t.Run("Headers", func(t *testing.T) {
+		resp, err := behttp.
+			Post("/headers", strings.NewReader(`a: foo`)).
+			Headers(
+				"Content-Type", "text/yml",
+				"Foo", "bar",
+			).
+			Send(svr.Client(), svr.URL+"/")
+		expect.NoError(t, err)
+		
+		expect.It(t, resp).To(
+			behttp.Status(http.StatusAccepted),
+			behttp.ContentType("text/yml").And(behttp.Header("Foo", "bar")),
+		)
+	})
+	
+	t.Run("Queries", func(t *testing.T) {
+		resp, err := behttp.
+			Put("queries", strings.NewReader(`a: foo`)).
+			Queries(
+				"ru", "bar",
+				"red", "licorice",
+			).
+			Send(svr.Client(), svr.URL)
+		expect.NoError(t, err)
+		
+		expect.It(t, resp).To(
+			behttp.Status(http.StatusAccepted),
+			behttp.Header("ru", "bar").And(behttp.Header("red", "licorice")),
+		)
+	})
+	
+	t.Run("JSON body with auth", func(t *testing.T) {
+		resp, err := behttp.Post("/echo", nil).JSONBody(t, foo{Name: "name"}).BasicAuth("pepper", "s3cret").Send(nil, svr.URL)
+		expect.NoError(t, err)
+		
+		expect.It(t, resp).To(
+			behttp.Header("Echo-Content-Type", "application/json"),
+			behttp.Header("Echo-User", "pepper"),
+			behttp.RespBody(beio.String(be.Eq(`{"Name":"name","Thing":"","Method":""}`))),
+		)
+	})
+	
+	t.Run("invalid base URL", func(t *testing.T) {
+		_, err := behttp.Get("/").Send(nil, "://nope")
+		expect.Error(t, err)
+	})
+	
+	t.Run("a built request can be sent with a client", func(t *testing.T) {
+		req, err := behttp.
+			Put(svr.URL+"/queries", strings.NewReader(`a: foo`)).
+			Queries("ru", "bar").
+			Build()
+		expect.NoError(t, err)
+		
+		resp, err := svr.Client().Do(req)
+		expect.NoError(t, err)
+		
+		expect.It(t, resp).To(
+			behttp.Status(http.StatusAccepted),
+			behttp.Header("ru", "bar"),
+		)
+	})
+	
+	t.Run("Build reports an invalid address", func(t *testing.T) {
+		_, err := behttp.Get("://nope").Build()
+		expect.Error(t, err)
+	})
 }
 
 type foo struct {
@@ -109,6 +303,39 @@ func newMux() http.Handler {
 		}
 		w.WriteHeader(http.StatusAccepted)
 	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Echo-Content-Type", req.Header.Get("Content-Type"))
+		w.Header().Set("Echo-Authorization", req.Header.Get("Authorization"))
+		w.Header().Set("Echo-Host", req.Host)
+		w.Header().Set("Echo-Remote-Addr", req.RemoteAddr)
+		if user, _, ok := req.BasicAuth(); ok {
+			w.Header().Set("Echo-User", user)
+		}
+		for _, c := range req.Cookies() {
+			w.Header().Set("Echo-Cookie-"+c.Name, c.Value)
+		}
+		switch mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType {
+		case "multipart/form-data":
+			if err := req.ParseMultipartForm(1 << 20); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			for field, files := range req.MultipartForm.File {
+				f, _ := files[0].Open()
+				b, _ := io.ReadAll(f)
+				w.Header().Set("Echo-File-"+field, files[0].Filename+": "+string(b))
+			}
+			echoForm(w, req)
+		case "application/x-www-form-urlencoded":
+			if err := req.ParseForm(); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			echoForm(w, req)
+		default:
+			_, _ = io.Copy(w, req.Body)
+		}
+	})
 	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
 		switch req.Method {
 		case http.MethodGet:
@@ -126,6 +353,12 @@ func newMux() http.Handler {
 	return mux
 }
 
+func echoForm(w http.ResponseWriter, req *http.Request) {
+	for k := range req.PostForm {
+		w.Header().Set("Echo-Form-"+k, req.PostForm.Get(k))
+	}
+}
+
 func haveFoo(method string) expect.Matcher[io.Reader] {
 	return bejson.Parsed[foo](func(got foo) expect.MatchResult {
 		want := foo{
@@ -0,0 +1,122 @@
+package behttp_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/behttp"
+	"github.com/jsteenb2/expect/be/beio"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func ExampleMethod() {
+	t := &expect.SpyTB{}
+	req := httptest.NewRequest(http.MethodPost, "/todos?page=2", nil)
+
+	expect.It(t, req).To(
+		behttp.Method(http.MethodPost),
+		behttp.Path("/todos"),
+		behttp.Query("page", be.Eq("2")),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleMethod_fail() {
+	t := &expect.SpyTB{}
+	req := httptest.NewRequest(http.MethodGet, "/todos", nil)
+
+	expect.It(t, req).To(behttp.Method(http.MethodPost))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the request to have method POST, but it was GET]
+}
+
+func TestRequestMatchers(t *testing.T) {
+	t.Run("Path", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/todos/1", nil)
+
+		expect.It(t, req).To(behttp.Path("/todos/1"))
+		spytb.VerifyFailingMatcher(t, req, behttp.Path("/todos"), `expected the request to have path "/todos", but it was "/todos/1"`)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/todos?page=2&sort=asc", nil)
+
+		expect.It(t, req).To(behttp.Query("sort", be.Eq("asc")))
+		spytb.VerifyFailingMatcher(
+			t,
+			req,
+			behttp.Query("page", be.Eq("3")),
+			`expected the request to have query "page" equal to "3", but it was "2"; query: page=2&sort=asc`,
+		)
+		spytb.VerifyFailingMatcher(
+			t,
+			httptest.NewRequest(http.MethodGet, "/todos", nil),
+			behttp.Query("page", be.Eq("3")),
+			`expected the request to have query "page" equal to "3", but it was ""; it had no query`,
+		)
+	})
+
+	t.Run("RequestHeader", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "application/json")
+
+		expect.It(t, req).To(behttp.RequestHeader("Accept", be.Eq("application/json")))
+		spytb.VerifyFailingMatcher(
+			t,
+			req,
+			behttp.RequestHeader("Accepts", be.Eq("application/json")),
+			`expected the request to have header "Accepts" equal to "application/json", but it was ""; present headers: Accept`,
+		)
+	})
+
+	t.Run("RequestBody", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"Egg"}`))
+
+		expect.It(t, req).To(
+			behttp.RequestBody(beio.String(be.Substring("Egg"))),
+			behttp.RequestBody(beio.String(be.Eq(`{"name":"Egg"}`))),
+		)
+		spytb.VerifyFailingMatcher(
+			t,
+			req,
+			behttp.RequestBody(beio.String(be.Substring("Bacon"))),
+			`expected the request body to contain "Bacon"`,
+		)
+
+		empty := httptest.NewRequest(http.MethodGet, "/", nil)
+		expect.It(t, empty).To(behttp.RequestBody(beio.String(be.Eq(""))))
+	})
+
+	t.Run("BasicAuth", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth("pepper", "s3cret")
+
+		expect.It(t, req).To(behttp.BasicAuth("pepper", "s3cret"))
+		spytb.VerifyFailingMatcher(t, req, behttp.BasicAuth("stanley", "s3cret"), `expected the request to have basic auth for user "stanley", but it was for user "pepper"`)
+		spytb.VerifyFailingMatcher(t, req, behttp.BasicAuth("pepper", "nope"), `expected the request to have basic auth for user "pepper", but the password did not match`)
+		spytb.VerifyFailingMatcher(
+			t,
+			httptest.NewRequest(http.MethodGet, "/", nil),
+			behttp.BasicAuth("pepper", "s3cret"),
+			`expected the request to have basic auth for user "pepper", but it had no basic auth credentials`,
+		)
+	})
+
+	t.Run("BearerToken", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "bearer tok3n")
+
+		expect.It(t, req).To(behttp.BearerToken("tok3n"))
+		spytb.VerifyFailingMatcher(t, req, behttp.BearerToken("other"), `expected the request to have the expected bearer token, but the token did not match`)
+
+		basic := httptest.NewRequest(http.MethodGet, "/", nil)
+		basic.SetBasicAuth("pepper", "s3cret")
+		spytb.VerifyFailingMatcher(t, basic, behttp.BearerToken("tok3n"), `expected the request to have the expected bearer token, but it had no bearer token`)
+	})
+}
@@ -0,0 +1,336 @@
+package behttp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+const subjectNameEventStream = "the event stream"
+
+// maxStreamLine is the longest line accepted in an event stream or NDJSON body.
+const maxStreamLine = 1 << 20
+
+// Event is a single server-sent event parsed from a text/event-stream body.
+type Event struct {
+	// ID is the last event ID seen in the stream at the time this event was dispatched.
+	ID string
+	// Event is the event type, "message" when the server did not name one.
+	Event string
+	// Data is the event data, multiple data lines are joined with a newline.
+	Data string
+	// Retry is the reconnection time sent with the event, zero when none was sent.
+	Retry time.Duration
+}
+
+// Events parses the reader as a text/event-stream and runs the matcher on every event in
+// it. The stream is read until EOF, so the server must end the stream. Use an EventStream
+// with NextEvent for streams that are held open.
+func Events(matcher expect.Matcher[[]Event]) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		var events []Event
+		er := newEventReader(rdr)
+		for {
+			ev, err := er.next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return expect.MatchResult{
+					Description: "have server-sent events",
+					Matches:     false,
+					But:         fmt.Sprintf("the stream could not be read: %v", err),
+					SubjectName: subjectNameEventStream,
+				}
+			}
+			events = append(events, ev)
+		}
+
+		result := matcher(events)
+		result = result.WithDescription("have events " + be.Complement(result))
+		result.SubjectName = subjectNameEventStream
+		return result
+	}
+}
+
+// EventStream reads server-sent events from a live response as they arrive. It is
+// used with NextEvent to assert on a stream that is held open by the server.
+//
+//	res, err := behttp.Get("/events").Send(svr.Client(), svr.URL)
+//	stream := behttp.NewEventStream(res)
+//	defer stream.Close()
+//	expect.It(t, stream).To(behttp.NextEvent(time.Second, haveData("hello")))
+type EventStream struct {
+	body      io.Closer
+	events    chan streamEvent
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type streamEvent struct {
+	event Event
+	err   error
+}
+
+// NewEventStream starts reading events from the response body.
+func NewEventStream(res *http.Response) *EventStream {
+	s := &EventStream{
+		body:   res.Body,
+		events: make(chan streamEvent),
+		done:   make(chan struct{}),
+	}
+	go s.read(newEventReader(res.Body))
+	return s
+}
+
+// Next waits up to timeout for the next event in the stream. It returns io.EOF once
+// the stream has ended.
+func (s *EventStream) Next(timeout time.Duration) (Event, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case ev, ok := <-s.events:
+		if !ok {
+			return Event{}, io.EOF
+		}
+		return ev.event, ev.err
+	case <-timer.C:
+		return Event{}, fmt.Errorf("timed out after %s waiting for an event", timeout)
+	}
+}
+
+// Close closes the response body, ending the stream.
+func (s *EventStream) Close() error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return s.body.Close()
+}
+
+func (s *EventStream) String() string {
+	return subjectNameEventStream
+}
+
+func (s *EventStream) read(er *eventReader) {
+	defer close(s.events)
+	for {
+		ev, err := er.next()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		select {
+		case s.events <- streamEvent{event: ev, err: err}:
+		case <-s.done:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// NextEvent waits up to timeout for the next event in the stream and runs the matcher
+// on it. Each NextEvent consumes one event, so successive matchers assert on
+// successive events.
+func NextEvent(timeout time.Duration, matcher expect.Matcher[Event]) expect.Matcher[*EventStream] {
+	return func(s *EventStream) expect.MatchResult {
+		ev, err := s.Next(timeout)
+		if err != nil {
+			but := fmt.Sprintf("it could not be read: %v", err)
+			if errors.Is(err, io.EOF) {
+				but = "the stream ended"
+			}
+			return expect.MatchResult{
+				Description: "have a next event",
+				Matches:     false,
+				But:         but,
+				SubjectName: subjectNameEventStream,
+			}
+		}
+
+		result := matcher(ev)
+		result = result.WithDescription("have a next event " + be.Complement(result))
+		result.SubjectName = subjectNameEventStream
+		if !result.Matches && result.But == "" {
+			result.But = fmt.Sprintf("it was %+v", ev)
+		}
+		return result
+	}
+}
+
+// eventReader parses events following the WHATWG server-sent events specification.
+type eventReader struct {
+	sc     *bufio.Scanner
+	lastID string
+}
+
+func newEventReader(r io.Reader) *eventReader {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxStreamLine)
+	sc.Split(scanStreamLines)
+	return &eventReader{sc: sc}
+}
+
+func (er *eventReader) next() (Event, error) {
+	var (
+		ev      Event
+		data    strings.Builder
+		hasData bool
+	)
+	for er.sc.Scan() {
+		line := er.sc.Text()
+		if line == "" {
+			if !hasData {
+				ev = Event{}
+				continue
+			}
+			ev.ID = er.lastID
+			ev.Data = strings.TrimSuffix(data.String(), "\n")
+			if ev.Event == "" {
+				ev.Event = "message"
+			}
+			return ev, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.Event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteString("\n")
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				er.lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+				ev.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := er.sc.Err(); err != nil {
+		return Event{}, err
+	}
+	// an event without a trailing blank line is discarded, as per the specification
+	return Event{}, io.EOF
+}
+
+// scanStreamLines splits on \n, \r\n or a lone \r.
+func scanStreamLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		// a trailing \r may be followed by a \n, wait for more data
+		return 0, nil, nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// NDJSONLines iterates over the lines of a newline delimited JSON stream as they
+// arrive, yielding each non-empty line. Iteration stops after the first error.
+//
+//	for line, err := range behttp.NDJSONLines(res.Body) {
+//		expect.NoError(t, err)
+//		expect.It[io.Reader](t, bytes.NewReader(line)).To(bejson.Parsed(haveTodo))
+//	}
+func NDJSONLines(r io.Reader) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		scanNDJSON(r, func(_ int, line []byte, err error) bool {
+			return yield(line, err)
+		})
+	}
+}
+
+// scanNDJSON calls yield with each non-blank line of the stream and its line number,
+// counting blank lines too, until yield returns false.
+func scanNDJSON(r io.Reader, yield func(lineNo int, line []byte, err error) bool) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxStreamLine)
+	sc.Split(scanStreamLines)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if !yield(lineNo, bytes.Clone(line), nil) {
+			return
+		}
+	}
+	if err := sc.Err(); err != nil {
+		yield(lineNo, nil, err)
+	}
+}
+
+// NDJSON parses every line of a newline delimited JSON stream into T and runs the
+// matcher on all of them. The stream is read until EOF.
+func NDJSON[T any](matcher expect.Matcher[[]T]) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		var (
+			things  []T
+			failure string
+		)
+		scanNDJSON(rdr, func(lineNo int, line []byte, err error) bool {
+			if err != nil {
+				failure = fmt.Sprintf("the stream could not be read: %v", err)
+				return false
+			}
+			var thing T
+			if err := json.Unmarshal(line, &thing); err != nil {
+				failure = fmt.Sprintf("line %d could not be parsed: %v", lineNo, err)
+				return false
+			}
+			things = append(things, thing)
+			return true
+		})
+		if failure != "" {
+			return ndjsonFailure[T](failure)
+		}
+
+		result := matcher(things)
+		result = result.WithDescription("have lines " + be.Complement(result))
+		result.SubjectName = "NDJSON"
+		return result
+	}
+}
+
+func ndjsonFailure[T any](but string) expect.MatchResult {
+	var thing T
+	return expect.MatchResult{
+		Description: fmt.Sprintf("have lines parseable into %T", thing),
+		Matches:     false,
+		But:         but,
+		SubjectName: "NDJSON",
+	}
+}
@@ -0,0 +1,229 @@
+package behttp_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/behttp"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func ExampleEvents() {
+	t := &expect.SpyTB{}
+
+	body := "event: greeting\ndata: hello\n\ndata: world\n\n"
+	expect.It[io.Reader](t, strings.NewReader(body)).To(behttp.Events(be.Size[behttp.Event](be.Eq(2))))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleNextEvent() {
+	t := &expect.SpyTB{}
+
+	svr := httptest.NewServer(newEventServer(0, "data: hello\n\n"))
+	defer svr.Close()
+
+	res, err := behttp.Get("/").Send(svr.Client(), svr.URL)
+	expect.NoError(t, err)
+	stream := behttp.NewEventStream(res)
+	defer stream.Close()
+
+	expect.It(t, stream).To(
+		behttp.NextEvent(time.Second, haveEventData("hello")),
+		behttp.NextEvent(time.Second, haveEventData("hello")),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the event stream to have a next event, but the stream ended]
+}
+
+func TestEvents(t *testing.T) {
+	t.Run("parses every field of the stream", func(t *testing.T) {
+		body := ": a comment\r\n" +
+			"id: 1\r\n" +
+			"event: todo\r\n" +
+			"data: {\"id\":1}\r\n" +
+			"retry: 2500\r\n" +
+			"\r\n" +
+			"data: first\rdata:second\r\r" +
+			"id\n" +
+			"data\n" +
+			"\n" +
+			"data: incomplete"
+
+		expect.It[io.Reader](t, strings.NewReader(body)).To(behttp.Events(be.ShallowEq([]behttp.Event{
+			{ID: "1", Event: "todo", Data: `{"id":1}`, Retry: 2500 * time.Millisecond},
+			{ID: "1", Event: "message", Data: "first\nsecond"},
+			{ID: "", Event: "message", Data: ""},
+		})))
+	})
+
+	t.Run("as a response body", func(t *testing.T) {
+		svr := httptest.NewServer(newEventServer(0, "data: a\n\n", "data: b\n\n"))
+		defer svr.Close()
+
+		res, err := behttp.Get("/").Send(svr.Client(), svr.URL)
+		expect.NoError(t, err)
+
+		expect.It(t, res).To(
+			behttp.ContentTypeMedia("text/event-stream"),
+			behttp.RespBody(behttp.Events(be.ContainingItem(be.Eq(behttp.Event{Event: "message", Data: "b"})))),
+		)
+
+		spytb.VerifyFailingMatcher(t, res, behttp.RespBody(behttp.Events(be.Size[behttp.Event](be.Eq(3)))),
+			"expected the response body to have events with a size equal to 3, but it was 2",
+		)
+	})
+}
+
+func TestNextEvent(t *testing.T) {
+	t.Run("asserts on events as they are flushed", func(t *testing.T) {
+		svr := httptest.NewServer(newEventServer(10*time.Millisecond,
+			"id: 1\ndata: one\n\n",
+			"id: 2\ndata: two\n\n",
+			"event: done\ndata: three\n\n",
+		))
+		defer svr.Close()
+
+		res, err := behttp.Get("/").Send(svr.Client(), svr.URL)
+		expect.NoError(t, err)
+		stream := behttp.NewEventStream(res)
+		defer stream.Close()
+
+		expect.It(t, stream).To(
+			behttp.NextEvent(time.Second, haveEventData("one")),
+			behttp.NextEvent(time.Second, haveEventData("two")),
+			behttp.NextEvent(time.Second, be.Eq(behttp.Event{ID: "2", Event: "done", Data: "three"})),
+		)
+	})
+
+	t.Run("times out waiting on a quiet stream", func(t *testing.T) {
+		release := make(chan struct{})
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer svr.Close()
+		defer close(release)
+
+		res, err := behttp.Get("/").Send(svr.Client(), svr.URL)
+		expect.NoError(t, err)
+		stream := behttp.NewEventStream(res)
+		defer stream.Close()
+
+		spytb.VerifyFailingMatcher(t, stream, behttp.NextEvent(10*time.Millisecond, haveEventData("one")),
+			"expected the event stream to have a next event, but it could not be read: timed out after 10ms waiting for an event",
+		)
+	})
+
+	t.Run("reports the event when the matcher does not", func(t *testing.T) {
+		svr := httptest.NewServer(newEventServer(0, "data: one\n\n"))
+		defer svr.Close()
+
+		res, err := behttp.Get("/").Send(svr.Client(), svr.URL)
+		expect.NoError(t, err)
+		stream := behttp.NewEventStream(res)
+		defer stream.Close()
+
+		spytb.VerifyFailingMatcher(t, stream, behttp.NextEvent(time.Second, haveEventData("two")),
+			"expected the event stream to have a next event with data \"two\", but it was {ID: Event:message Data:one Retry:0s}",
+		)
+	})
+}
+
+func TestNDJSON(t *testing.T) {
+	type todo struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	t.Run("iterates over lines", func(t *testing.T) {
+		var lines []string
+		for line, err := range behttp.NDJSONLines(strings.NewReader("{\"id\":1}\n\n  {\"id\":2}  \r\n{\"id\":3}")) {
+			expect.NoError(t, err)
+			lines = append(lines, string(line))
+		}
+		expect.It(t, lines).To(be.ShallowEq([]string{`{"id":1}`, `{"id":2}`, `{"id":3}`}))
+	})
+
+	t.Run("stops when the loop breaks", func(t *testing.T) {
+		var count int
+		for range behttp.NDJSONLines(strings.NewReader("1\n2\n3\n")) {
+			count++
+			break
+		}
+		expect.It(t, count).To(be.Eq(1))
+	})
+
+	t.Run("parses a chunked response", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			for i, name := range []string{"write", "test"} {
+				fmt.Fprintf(w, "{\"id\":%d,\"name\":%q}\n", i+1, name)
+				w.(http.Flusher).Flush()
+			}
+		}))
+		defer svr.Close()
+
+		res, err := behttp.Get("/").Send(svr.Client(), svr.URL)
+		expect.NoError(t, err)
+
+		expect.It(t, res).To(behttp.RespBody(behttp.NDJSON(be.ShallowEq([]todo{
+			{ID: 1, Name: "write"},
+			{ID: 2, Name: "test"},
+		}))))
+	})
+
+	t.Run("describes the lines matcher", func(t *testing.T) {
+		rdr := strings.NewReader("{\"id\":1}\n")
+		spytb.VerifyFailingMatcher[io.Reader](t, rdr, behttp.NDJSON[todo](be.Size[todo](be.Eq(2))),
+			"expected NDJSON to have lines with a size equal to 2, but it was 1",
+		)
+	})
+
+	t.Run("reports the line that could not be parsed", func(t *testing.T) {
+		rdr := strings.NewReader("{\"id\":1}\n{\"id\":\n")
+		spytb.VerifyFailingMatcher[io.Reader](t, rdr, behttp.NDJSON[todo](be.Size[todo](be.Eq(2))),
+			"expected NDJSON to have lines parseable into behttp_test.todo, but line 2 could not be parsed",
+		)
+	})
+
+	t.Run("blank lines are counted in line numbers", func(t *testing.T) {
+		rdr := strings.NewReader("{\"id\":1}\n\n\r\n{\"id\":\n")
+		spytb.VerifyFailingMatcher[io.Reader](t, rdr, behttp.NDJSON[todo](be.Size[todo](be.Eq(2))),
+			"expected NDJSON to have lines parseable into behttp_test.todo, but line 4 could not be parsed",
+		)
+	})
+}
+
+func haveEventData(data string) expect.Matcher[behttp.Event] {
+	return func(ev behttp.Event) expect.MatchResult {
+		return expect.MatchResult{
+			Description: fmt.Sprintf("with data %q", data),
+			Matches:     ev.Data == data,
+		}
+	}
+}
+
+// newEventServer writes each chunk of the event stream, flushing and pausing
+// between them.
+func newEventServer(pause time.Duration, chunks ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			io.WriteString(w, chunk)
+			w.(http.Flusher).Flush()
+			time.Sleep(pause)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package beio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jsteenb2/expect"
+)
+
+// EqualBytes checks the reader has exactly the bytes. When it does not, the rows around the
+// first difference are shown as a hex dump.
+func EqualBytes(want []byte) expect.Matcher[io.Reader] {
+	return func(reader io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have the expected %d bytes", len(want)),
+			Matches:     false,
+			SubjectName: subjectName,
+		}
+
+		got, err := io.ReadAll(reader)
+		if err != nil {
+			result.But = fmt.Sprintf("it could not be read: %v", err)
+			return result
+		}
+		if bytes.Equal(got, want) {
+			result.Matches = true
+			return result
+		}
+
+		at := 0
+		for at < len(got) && at < len(want) && got[at] == want[at] {
+			at++
+		}
+		result.But = fmt.Sprintf("it had %d bytes, differing from offset %#x:\n%s", len(got), at, dumpDiff(got, want, at))
+		return result
+	}
+}
+
+// HasPrefixBytes checks the reader starts with the prefix. Only the bytes of the prefix are read.
+func HasPrefixBytes(prefix []byte) expect.Matcher[io.Reader] {
+	return func(reader io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("start with % x", prefix),
+			Matches:     false,
+			SubjectName: subjectName,
+		}
+
+		got := make([]byte, len(prefix))
+		n, err := io.ReadFull(reader, got)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			result.But = fmt.Sprintf("it could not be read: %v", err)
+			return result
+		}
+		got = got[:n]
+
+		result.Matches = bytes.Equal(got, prefix)
+		if n < len(prefix) {
+			result.But = fmt.Sprintf("it ended after % x", got)
+		} else {
+			result.But = fmt.Sprintf("it started with % x", got)
+		}
+		return result
+	}
+}
+
+// SHA256 checks the SHA-256 checksum of everything in the reader is the hex encoded sum,
+// i.e. as printed by sha256sum. Upper and lower case digits are both accepted.
+func SHA256(sum string) expect.Matcher[io.Reader] {
+	return func(reader io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have the SHA-256 checksum %s", sum),
+			Matches:     false,
+			SubjectName: subjectName,
+		}
+
+		h := sha256.New()
+		n, err := io.Copy(h, reader)
+		if err != nil {
+			result.But = fmt.Sprintf("it could not be read: %v", err)
+			return result
+		}
+
+		got := hex.EncodeToString(h.Sum(nil))
+		result.Matches = got == strings.ToLower(sum)
+		result.But = fmt.Sprintf("it was %s, for %d bytes", got, n)
+		return result
+	}
+}
+
+// Empty checks the reader has no data.
+func Empty(reader io.Reader) expect.MatchResult {
+	result := expect.MatchResult{
+		Description: "be empty",
+		Matches:     false,
+		SubjectName: subjectName,
+	}
+
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		result.But = fmt.Sprintf("it could not be read: %v", err)
+		return result
+	}
+
+	result.Matches = len(got) == 0
+	result.But = fmt.Sprintf("it had %d bytes:\n%s", len(got), excerpt(got))
+	return result
+}
+
+// FailsAfter checks reading fails with the error, as checked by errors.Is, once exactly n
+// bytes have been read.
+//
+//	beio.FailsAfter(512, io.ErrUnexpectedEOF)
+func FailsAfter(n int64, want error) expect.Matcher[io.Reader] {
+	return func(reader io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("fail with %q after %d bytes", want, n),
+			Matches:     false,
+			SubjectName: subjectName,
+		}
+
+		read, err := io.Copy(io.Discard, reader)
+		switch {
+		case err == nil:
+			result.But = fmt.Sprintf("it read all %d bytes without an error", read)
+		case !errors.Is(err, want):
+			result.But = fmt.Sprintf("it failed with %q after %d bytes", err, read)
+		case read != n:
+			result.But = fmt.Sprintf("it failed after %d bytes", read)
+		default:
+			result.Matches = true
+		}
+		return result
+	}
+}
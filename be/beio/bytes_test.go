@@ -0,0 +1,106 @@
+package beio_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be/beio"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+// png is the start of a PNG file: its signature and the first chunk header.
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x10\x00\x00\x00\x10\x08\x06\x00\x00\x00")
+
+func ExampleEqualBytes() {
+	t := &expect.SpyTB{}
+
+	corrupted := bytes.Clone(png)
+	corrupted[20] = 0x20
+
+	expect.It[io.Reader](t, bytes.NewReader(corrupted)).To(beio.EqualBytes(png))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the reader to have the expected 29 bytes, but it had 29 bytes, differing from offset 0x14:
+	//   00000000  89 50 4e 47 0d 0a 1a 0a  00 00 00 0d 49 48 44 52  |.PNG........IHDR|
+	// - 00000010  00 00 00 10 00 00 00 10  08 06 00 00 00           |.............|
+	// + 00000010  00 00 00 10 20 00 00 10  08 06 00 00 00           |.... ........|]
+}
+
+func ExampleHasPrefixBytes() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, bytes.NewReader(png)).To(beio.HasPrefixBytes([]byte("\x89PNG")))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleSHA256() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader("hello world\n")).To(beio.SHA256("a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447"))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func TestEqualBytes(t *testing.T) {
+	expect.It[io.Reader](t, bytes.NewReader(png)).To(beio.EqualBytes(png))
+	expect.It[io.Reader](t, strings.NewReader("")).To(beio.EqualBytes(nil))
+
+	spytb.VerifyFailingMatcher[io.Reader](t, bytes.NewReader(png[:8]), beio.EqualBytes(png),
+		"expected the reader to have the expected 29 bytes, but it had 8 bytes, differing from offset 0x8:\n"+
+			"- 00000000  89 50 4e 47 0d 0a 1a 0a  00 00 00 0d 49 48 44 52  |.PNG........IHDR|\n"+
+			"+ 00000000  89 50 4e 47 0d 0a 1a 0a                           |.PNG....|\n"+
+			"- 00000010  00 00 00 10 00 00 00 10  08 06 00 00 00           |.............|",
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, iotest.ErrReader(fmt.Errorf("disk on fire")), beio.EqualBytes(png),
+		"expected the reader to have the expected 29 bytes, but it could not be read: disk on fire",
+	)
+}
+
+func TestHasPrefixBytes(t *testing.T) {
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader("GIF89a"), beio.HasPrefixBytes([]byte("\x89PNG")),
+		"expected the reader to start with 89 50 4e 47, but it started with 47 49 46 38",
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader("\x89P"), beio.HasPrefixBytes([]byte("\x89PNG")),
+		"expected the reader to start with 89 50 4e 47, but it ended after 89 50",
+	)
+}
+
+func TestSHA256(t *testing.T) {
+	expect.It[io.Reader](t, strings.NewReader("hello world\n")).To(beio.SHA256("A948904F2F0F479B8F8197694B30184B0D2ED1C1CD2A1EC0FB85D299A192A447"))
+
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(""), beio.SHA256("a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447"),
+		"expected the reader to have the SHA-256 checksum a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447, but it was e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855, for 0 bytes",
+	)
+}
+
+func TestEmpty(t *testing.T) {
+	expect.It[io.Reader](t, strings.NewReader("")).To(beio.Empty)
+
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader("oops"), beio.Empty,
+		"expected the reader to be empty, but it had 4 bytes:\n00000000  6f 6f 70 73                                       |oops|",
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(strings.Repeat("x", 100)), beio.Empty,
+		"|xxxxxxxxxxxxxxxx|\n... 36 more bytes",
+	)
+}
+
+func TestFailsAfter(t *testing.T) {
+	truncated := io.MultiReader(strings.NewReader("hello"), iotest.ErrReader(io.ErrUnexpectedEOF))
+
+	expect.It(t, truncated).To(beio.FailsAfter(5, io.ErrUnexpectedEOF))
+
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader("hello"), beio.FailsAfter(5, io.ErrUnexpectedEOF),
+		`expected the reader to fail with "unexpected EOF" after 5 bytes, but it read all 5 bytes without an error`,
+	)
+	spytb.VerifyFailingMatcher(t, io.MultiReader(strings.NewReader("hel"), iotest.ErrReader(io.ErrUnexpectedEOF)), beio.FailsAfter(5, io.ErrUnexpectedEOF),
+		`expected the reader to fail with "unexpected EOF" after 5 bytes, but it failed after 3 bytes`,
+	)
+	spytb.VerifyFailingMatcher(t, io.MultiReader(strings.NewReader("hello"), iotest.ErrReader(io.ErrClosedPipe)), beio.FailsAfter(5, io.ErrUnexpectedEOF),
+		`expected the reader to fail with "unexpected EOF" after 5 bytes, but it failed with "io: read/write on closed pipe" after 5 bytes`,
+	)
+}
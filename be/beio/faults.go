@@ -0,0 +1,152 @@
+package beio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing/iotest"
+	"time"
+
+	"github.com/jsteenb2/expect"
+)
+
+// ErrAfter returns a reader that reads at most n bytes from r and then fails with err.
+// It reports io.EOF as usual if r runs out before then.
+func ErrAfter(r io.Reader, n int64, err error) io.Reader {
+	return &errAfterReader{r: r, remaining: n, err: err}
+}
+
+type errAfterReader struct {
+	r         io.Reader
+	remaining int64
+	err       error
+}
+
+func (e *errAfterReader) Read(p []byte) (int, error) {
+	if e.remaining <= 0 {
+		return 0, e.err
+	}
+	if int64(len(p)) > e.remaining {
+		p = p[:e.remaining]
+	}
+	n, err := e.r.Read(p)
+	e.remaining -= int64(n)
+	return n, err
+}
+
+// OneByteReader returns a reader that reads at most one byte of r on each Read, like
+// iotest.OneByteReader.
+func OneByteReader(r io.Reader) io.Reader {
+	return limitEachRead(r, func(n int) int { return min(n, 1) })
+}
+
+// HalfReader returns a reader that reads half as many bytes of r as asked for on each Read,
+// rounding up, like iotest.HalfReader.
+func HalfReader(r io.Reader) io.Reader {
+	return limitEachRead(r, func(n int) int { return (n + 1) / 2 })
+}
+
+func limitEachRead(r io.Reader, limit func(n int) int) io.Reader {
+	return readerFunc(func(p []byte) (int, error) {
+		if len(p) == 0 {
+			return r.Read(p)
+		}
+		return r.Read(p[:limit(len(p))])
+	})
+}
+
+// TimeoutReader returns a reader that fails its second Read with iotest.ErrTimeout, without
+// reading anything, and then carries on reading r, like iotest.TimeoutReader.
+func TimeoutReader(r io.Reader) io.Reader {
+	reads := 0
+	return readerFunc(func(p []byte) (int, error) {
+		reads++
+		if reads == 2 {
+			return 0, iotest.ErrTimeout
+		}
+		return r.Read(p)
+	})
+}
+
+// SlowReader returns a wrapper which waits for the delay before each Read, for code with
+// deadlines or progress reporting.
+//
+//	beio.ReadsFullyWith(beio.SlowReader(10*time.Millisecond), bejson.Parsed(be.Eq(want)))
+func SlowReader(delay time.Duration) func(io.Reader) io.Reader {
+	return func(r io.Reader) io.Reader {
+		return readerFunc(func(p []byte) (int, error) {
+			time.Sleep(delay)
+			return r.Read(p)
+		})
+	}
+}
+
+// Compose combines wrappers like OneByteReader and SlowReader(delay) into one, the first
+// wrapping the reader closest to the data.
+func Compose(wrappers ...func(io.Reader) io.Reader) func(io.Reader) io.Reader {
+	return func(r io.Reader) io.Reader {
+		for _, wrap := range wrappers {
+			r = wrap(r)
+		}
+		return r
+	}
+}
+
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
+
+// ReadsFullyWith runs the matcher on the data in the reader delivered through the wrapper,
+// such as OneByteReader or iotest.DataErrReader, and checks the matcher read all of it but
+// any trailing white space, like the newline a json.Encoder ends with. It catches code which
+// assumes a single Read fills its buffer.
+//
+//	beio.ReadsFullyWith(iotest.OneByteReader, bejson.Parsed(be.Eq(want)))
+func ReadsFullyWith(wrap func(io.Reader) io.Reader, matcher expect.Matcher[io.Reader]) expect.Matcher[io.Reader] {
+	return func(reader io.Reader) expect.MatchResult {
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return expect.MatchResult{
+				Description: "be read fully through the wrapper",
+				Matches:     false,
+				But:         fmt.Sprintf("it could not be read: %v", err),
+				SubjectName: subjectName,
+			}
+		}
+
+		counter := &countingReader{r: bytes.NewReader(data)}
+		result := matcher(wrap(counter))
+		if !result.Matches {
+			if result.But == "" {
+				result.But = "it did not"
+			}
+			result.But += ", when read through the wrapper"
+			if result.SubjectName == "" {
+				result.SubjectName = subjectName
+			}
+			return result
+		}
+		if unread := data[counter.n:]; len(bytes.TrimSpace(unread)) > 0 {
+			return expect.MatchResult{
+				Description: fmt.Sprintf("be read fully through the wrapper and %s", result.Description),
+				Matches:     false,
+				But:         fmt.Sprintf("only %d of its %d bytes were read", counter.n, len(data)),
+				SubjectName: subjectName,
+			}
+		}
+		return result
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,137 @@
+package beio_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/beio"
+	"github.com/jsteenb2/expect/be/bejson"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+// haveMagic is a matcher with a bug: it assumes one Read fills its buffer.
+func haveMagic(magic string) expect.Matcher[io.Reader] {
+	return func(r io.Reader) expect.MatchResult {
+		buf := make([]byte, len(magic))
+		n, _ := r.Read(buf)
+		return expect.MatchResult{
+			Description: fmt.Sprintf("start with %q", magic),
+			Matches:     string(buf[:n]) == magic,
+			But:         fmt.Sprintf("it started with %q", buf[:n]),
+			SubjectName: "the reader",
+		}
+	}
+}
+
+func ExampleReadsFullyWith() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader("hello world")).To(beio.ReadsFullyWith(iotest.OneByteReader, beio.String(be.Eq("hello world"))))
+	expect.It[io.Reader](t, strings.NewReader("hello world")).To(beio.ReadsFullyWith(beio.OneByteReader, haveMagic("hello")))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the reader to start with "hello", but it started with "h", when read through the wrapper]
+}
+
+func ExampleErrAfter() {
+	t := &expect.SpyTB{}
+
+	r := beio.ErrAfter(strings.NewReader("hello world"), 5, io.ErrUnexpectedEOF)
+
+	expect.It(t, r).To(beio.HaveData(beio.ContainingString("world")))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the reader to have data in io.Reader, but it could not be read: unexpected EOF]
+}
+
+func TestFaultReaders(t *testing.T) {
+	const data = "the quick brown fox jumps over the lazy dog"
+
+	t.Run("they deliver all the data", func(t *testing.T) {
+		for name, wrap := range map[string]func(io.Reader) io.Reader{
+			"one byte": beio.OneByteReader,
+			"half":     beio.HalfReader,
+			"slow":     beio.SlowReader(time.Microsecond),
+			"composed": beio.Compose(beio.HalfReader, beio.SlowReader(time.Microsecond), beio.OneByteReader),
+		} {
+			t.Run(name, func(t *testing.T) {
+				expect.NoError(t, iotest.TestReader(wrap(strings.NewReader(data)), []byte(data)))
+				expect.It[io.Reader](t, strings.NewReader(data)).To(beio.ReadsFullyWith(wrap, beio.String(be.Eq(data))))
+			})
+		}
+	})
+
+	t.Run("one byte and half readers limit each read", func(t *testing.T) {
+		buf := make([]byte, 10)
+
+		n, err := beio.OneByteReader(strings.NewReader(data)).Read(buf)
+		expect.NoError(t, err)
+		expect.It(t, n).To(be.Eq(1))
+
+		n, err = beio.HalfReader(strings.NewReader(data)).Read(buf)
+		expect.NoError(t, err)
+		expect.It(t, n).To(be.Eq(5))
+	})
+
+	t.Run("timeout reader fails the second read", func(t *testing.T) {
+		r := beio.TimeoutReader(strings.NewReader(data))
+		buf := make([]byte, 4)
+
+		_, err := r.Read(buf)
+		expect.NoError(t, err)
+		n, err := r.Read(buf)
+		expect.It(t, n).To(be.Eq(0))
+		expect.It(t, errors.Is(err, iotest.ErrTimeout)).To(be.Eq(true))
+
+		rest, err := io.ReadAll(r)
+		expect.NoError(t, err)
+		expect.It(t, string(rest)).To(be.Eq(data[4:]))
+	})
+
+	t.Run("slow reader waits before each read", func(t *testing.T) {
+		start := time.Now()
+		_, err := io.ReadAll(beio.OneByteReader(beio.SlowReader(time.Millisecond)(strings.NewReader("abc"))))
+		expect.NoError(t, err)
+		expect.It(t, time.Since(start) >= 3*time.Millisecond).To(be.Eq(true))
+	})
+
+	t.Run("err after", func(t *testing.T) {
+		expect.It(t, beio.ErrAfter(strings.NewReader(data), 9, io.ErrClosedPipe)).To(beio.FailsAfter(9, io.ErrClosedPipe))
+		expect.It(t, beio.ErrAfter(strings.NewReader("abc"), 9, io.ErrClosedPipe)).To(beio.String(be.Eq("abc")))
+	})
+}
+
+func TestReadsFullyWith(t *testing.T) {
+	stopsEarly := func(r io.Reader) expect.MatchResult {
+		_, err := r.Read(make([]byte, 4))
+		return expect.MatchResult{Description: "be readable", Matches: err == nil}
+	}
+
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader("hello world"), beio.ReadsFullyWith(beio.HalfReader, stopsEarly),
+		"expected the reader to be read fully through the wrapper and be readable, but only 2 of its 11 bytes were read",
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader("hello world \n"), beio.ReadsFullyWith(beio.HalfReader, stopsEarly),
+		"expected the reader to be read fully through the wrapper and be readable, but only 2 of its 13 bytes were read",
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader("hello"), beio.ReadsFullyWith(iotest.TimeoutReader, beio.String(be.Eq("hello"))),
+		"expected the reader to have data in io.Reader, but it could not be read: timeout, when read through the wrapper",
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, iotest.ErrReader(io.ErrClosedPipe), beio.ReadsFullyWith(iotest.OneByteReader, beio.String(be.Eq("hello"))),
+		"expected the reader to be read fully through the wrapper, but it could not be read: io: read/write on closed pipe",
+	)
+
+	t.Run("trailing white space may be left unread", func(t *testing.T) {
+		type doc struct{ A int }
+		var buf bytes.Buffer
+		expect.NoError(t, json.NewEncoder(&buf).Encode(doc{A: 1}))
+
+		expect.It[io.Reader](t, &buf).To(beio.ReadsFullyWith(iotest.OneByteReader, bejson.Parsed(be.Eq(doc{A: 1}))))
+	})
+}
@@ -0,0 +1,60 @@
+package beio
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	subjectName = "the reader"
+	dumpWidth   = 16
+	// excerptBytes is how much of the data a failure shows when it cannot point at a difference.
+	excerptBytes = 64
+)
+
+func row(b []byte, offset int) []byte {
+	return b[offset:min(offset+dumpWidth, len(b))]
+}
+
+// dumpRow formats the 16 bytes of b starting at offset like a line of hexdump -C.
+func dumpRow(b []byte, offset int) string {
+	dump := strings.TrimSuffix(hex.Dump(row(b, offset)), "\n")
+	return fmt.Sprintf("%08x", offset) + dump[8:]
+}
+
+// excerpt dumps the start of b.
+func excerpt(b []byte) string {
+	var rows []string
+	for offset := 0; offset < min(len(b), excerptBytes); offset += dumpWidth {
+		rows = append(rows, dumpRow(b, offset))
+	}
+	if len(b) > excerptBytes {
+		rows = append(rows, fmt.Sprintf("... %d more bytes", len(b)-excerptBytes))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// dumpDiff dumps the rows around the first difference between got and want, marking the
+// expected rows with - and the rows it had with +.
+func dumpDiff(got, want []byte, at int) string {
+	const rows = 3
+	first := max(0, at-at%dumpWidth-dumpWidth)
+	var lines []string
+	for offset := first; offset < first+rows*dumpWidth; offset += dumpWidth {
+		inWant, inGot := offset < len(want), offset < len(got)
+		switch {
+		case inWant && inGot && bytes.Equal(row(want, offset), row(got, offset)):
+			lines = append(lines, "  "+dumpRow(got, offset))
+		default:
+			if inWant {
+				lines = append(lines, "- "+dumpRow(want, offset))
+			}
+			if inGot {
+				lines = append(lines, "+ "+dumpRow(got, offset))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
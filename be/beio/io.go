@@ -15,7 +15,7 @@ func ContainingByte(want []byte) expect.Matcher[[]byte] {
 			Description: fmt.Sprintf("contain %q", want),
 			Matches:     bytes.Contains(have, want),
 			SubjectName: "the reader",
-			But:         fmt.Sprintf("it didn't have %q", want),
+			But:         fmt.Sprintf("it didn't have %q in its %d bytes:\n%s", want, len(have), excerpt(have)),
 		}
 	}
 }
@@ -40,7 +40,8 @@ func HaveData(matcher expect.Matcher[[]byte]) expect.Matcher[io.Reader] {
 			return expect.MatchResult{
 				Description: "have data in io.Reader",
 				Matches:     false,
-				But:         "it could not be read",
+				But:         fmt.Sprintf("it could not be read: %v", err),
+				SubjectName: subjectName,
 			}
 		}
 		return matcher(all)
@@ -54,7 +55,8 @@ func String(matcher expect.Matcher[string]) expect.Matcher[io.Reader] {
 			return expect.MatchResult{
 				Description: "have data in io.Reader",
 				Matches:     false,
-				But:         "it could not be read",
+				But:         fmt.Sprintf("it could not be read: %v", err),
+				SubjectName: subjectName,
 			}
 		}
 		return matcher(string(all))
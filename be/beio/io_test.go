@@ -37,7 +37,8 @@ func ExampleContainingByte_fail() {
 		beio.ContainingByte([]byte("goodbye")),
 	))
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected the reader to contain "goodbye", but it didn't have "goodbye"]
+	// Output: Test failed: [expected the reader to contain "goodbye", but it didn't have "goodbye" in its 10 bytes:
+	// 00000000  68 65 6c 6c 6f 77 6f 72  6c 64                    |helloworld|]
 }
 
 func ExampleContainingString() {
@@ -0,0 +1,80 @@
+package beio
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+// Lines splits everything in the reader into lines and runs the matcher on them. Lines end
+// with \n or \r\n, which are removed, and a newline at the end does not start another line.
+func Lines(matcher expect.Matcher[[]string]) expect.Matcher[io.Reader] {
+	return linesMatcher("have lines", func(lines []string) expect.MatchResult {
+		return matcher(lines)
+	})
+}
+
+// LineCount checks the number of lines in the reader, counted as Lines splits them, meets
+// the matcher's criteria.
+func LineCount(matcher expect.Matcher[int]) expect.Matcher[io.Reader] {
+	return linesMatcher("have a line count", func(lines []string) expect.MatchResult {
+		return matcher(len(lines))
+	})
+}
+
+// FirstLine runs the matcher on the first line of the reader.
+func FirstLine(matcher expect.Matcher[string]) expect.Matcher[io.Reader] {
+	return linesMatcher("have a first line", func(lines []string) expect.MatchResult {
+		if len(lines) == 0 {
+			return expect.MatchResult{But: "it had no lines"}
+		}
+		return matcher(lines[0])
+	})
+}
+
+// LastLine runs the matcher on the last line of the reader.
+func LastLine(matcher expect.Matcher[string]) expect.Matcher[io.Reader] {
+	return linesMatcher("have a last line", func(lines []string) expect.MatchResult {
+		if len(lines) == 0 {
+			return expect.MatchResult{But: "it had no lines"}
+		}
+		return matcher(lines[len(lines)-1])
+	})
+}
+
+func linesMatcher(description string, match func(lines []string) expect.MatchResult) expect.Matcher[io.Reader] {
+	return func(reader io.Reader) expect.MatchResult {
+		all, err := io.ReadAll(reader)
+		if err != nil {
+			return expect.MatchResult{
+				Description: description,
+				Matches:     false,
+				But:         fmt.Sprintf("it could not be read: %v", err),
+				SubjectName: subjectName,
+			}
+		}
+
+		result := match(splitLines(string(all)))
+		if result.Description == "" {
+			result = result.WithDescription(description)
+		} else {
+			result = result.WithDescription(description + " " + be.Complement(result))
+		}
+		result.SubjectName = subjectName
+		return result
+	}
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
@@ -0,0 +1,73 @@
+package beio_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/beio"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+const logOutput = "starting server\r\nlistening on :8080\nshutting down\n"
+
+func ExampleLines() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(logOutput)).To(beio.Lines(be.ShallowEq([]string{
+		"starting server",
+		"listening on :8080",
+		"shutting down",
+	})))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleLastLine() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(logOutput)).To(beio.LastLine(be.Eq("stopped")))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the reader to have a last line equal to "stopped", but it was "shutting down"]
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		lines []string
+	}{
+		{name: "empty", input: "", lines: nil},
+		{name: "no newline at the end", input: "a\nb", lines: []string{"a", "b"}},
+		{name: "newline at the end", input: "a\nb\n", lines: []string{"a", "b"}},
+		{name: "blank lines", input: "\n\na\n\n", lines: []string{"", "", "a", ""}},
+		{name: "carriage returns", input: "a\r\nb\r\n", lines: []string{"a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect.It[io.Reader](t, strings.NewReader(tt.input)).To(beio.Lines(be.ShallowEq(tt.lines)))
+			expect.It[io.Reader](t, strings.NewReader(tt.input)).To(beio.LineCount(be.Eq(len(tt.lines))))
+		})
+	}
+
+	expect.It[io.Reader](t, strings.NewReader(logOutput)).To(beio.FirstLine(be.Eq("starting server")))
+
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(logOutput), beio.LineCount(be.Eq(2)),
+		"expected the reader to have a line count equal to 2, but it was 3",
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(""), beio.FirstLine(be.Eq("starting server")),
+		"expected the reader to have a first line, but it had no lines",
+	)
+	spytb.VerifyFailingMatcher[io.Reader](t, &failingReader{}, beio.Lines(be.Size[string](be.Eq(1))),
+		"expected the reader to have lines, but it could not be read: connection reset",
+	)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, fmt.Errorf("connection reset")
+}
@@ -0,0 +1,184 @@
+package beio
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+// RecordingWriter is an io.Writer that records every write made to it, keeping the
+// boundaries between them, and can fail writes so error paths are tested deterministically.
+// It is an http.Flusher too, and records flushes. The zero value is ready to use.
+//
+//	w := &beio.RecordingWriter{}
+//	w.FailAfter(512, io.ErrShortWrite)
+//	err := render(w)
+//	expect.It(t, w).To(beio.WroteTotal(512))
+type RecordingWriter struct {
+	mu      sync.Mutex
+	chunks  [][]byte
+	written int
+	flushes int
+	// flushedAt is how many chunks had been written at the last flush
+	flushedAt int
+
+	limit   int
+	limited bool
+	err     error
+}
+
+var (
+	_ io.Writer    = (*RecordingWriter)(nil)
+	_ http.Flusher = (*RecordingWriter)(nil)
+)
+
+// FailAfter makes the write that would take the total written past n bytes accept only
+// the bytes up to n and return err, and every later write return err.
+func (w *RecordingWriter) FailAfter(n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.limit, w.limited, w.err = n, true, err
+}
+
+// ShortWriteAfter makes writes past n bytes in total accept only the bytes up to n without
+// returning an error, breaking the io.Writer contract the way a faulty writer would.
+func (w *RecordingWriter) ShortWriteAfter(n int) {
+	w.FailAfter(n, nil)
+}
+
+// Write records p, or as much of it as the writer was set to accept.
+func (w *RecordingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := len(p)
+	if w.limited {
+		n = max(0, min(n, w.limit-w.written))
+	}
+	if n > 0 {
+		w.chunks = append(w.chunks, bytes.Clone(p[:n]))
+		w.written += n
+	}
+	if n < len(p) {
+		return n, w.err
+	}
+	return n, nil
+}
+
+// Flush records a flush.
+func (w *RecordingWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushes++
+	w.flushedAt = len(w.chunks)
+}
+
+// Chunks returns a copy of each write accepted so far, in order.
+func (w *RecordingWriter) Chunks() [][]byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	chunks := make([][]byte, len(w.chunks))
+	for i, c := range w.chunks {
+		chunks[i] = bytes.Clone(c)
+	}
+	return chunks
+}
+
+// Bytes returns everything written so far.
+func (w *RecordingWriter) Bytes() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return bytes.Join(w.chunks, nil)
+}
+
+// String returns everything written so far as a string.
+func (w *RecordingWriter) String() string {
+	return string(w.Bytes())
+}
+
+// Flushes returns how many times the writer was flushed.
+func (w *RecordingWriter) Flushes() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flushes
+}
+
+// Reset discards everything recorded, but keeps any failure set up.
+func (w *RecordingWriter) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.chunks, w.written, w.flushes, w.flushedAt = nil, 0, 0, 0
+}
+
+// WrittenChunks runs the matcher on the writes accepted by the writer, one chunk per call to
+// Write.
+//
+//	beio.WrittenChunks(be.Size[[]byte](be.Eq(3)))
+func WrittenChunks(matcher expect.Matcher[[][]byte]) expect.Matcher[*RecordingWriter] {
+	return func(w *RecordingWriter) expect.MatchResult {
+		result := matcher(w.Chunks())
+		result = result.WithDescription("have written chunks " + be.Complement(result))
+		result.SubjectName = "the writer"
+		return result
+	}
+}
+
+// WroteTotal checks the writer accepted n bytes in total.
+func WroteTotal(n int) expect.Matcher[*RecordingWriter] {
+	return func(w *RecordingWriter) expect.MatchResult {
+		w.mu.Lock()
+		written, writes := w.written, len(w.chunks)
+		w.mu.Unlock()
+
+		return expect.MatchResult{
+			Description: fmt.Sprintf("have written %d bytes", n),
+			Matches:     written == n,
+			But:         fmt.Sprintf("it wrote %d bytes in %d writes", written, writes),
+			SubjectName: "the writer",
+		}
+	}
+}
+
+// Flushed checks the writer was flushed after its last write. A writer that was never written
+// to has nothing to flush.
+func Flushed() expect.Matcher[*RecordingWriter] {
+	return func(w *RecordingWriter) expect.MatchResult {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		result := expect.MatchResult{
+			Description: "have been flushed",
+			Matches:     false,
+			SubjectName: "the writer",
+		}
+		switch {
+		case len(w.chunks) == 0:
+			result.Matches = true
+		case w.flushes == 0:
+			result.But = "it was never flushed"
+		case w.flushedAt < len(w.chunks):
+			result.But = fmt.Sprintf("it had %d writes after the last flush", len(w.chunks)-w.flushedAt)
+		default:
+			result.Matches = true
+		}
+		return result
+	}
+}
+
+// BufferFlushed checks nothing is left buffered in the bufio.Writer.
+func BufferFlushed() expect.Matcher[*bufio.Writer] {
+	return func(w *bufio.Writer) expect.MatchResult {
+		return expect.MatchResult{
+			Description: "have been flushed",
+			Matches:     w.Buffered() == 0,
+			But:         fmt.Sprintf("it had %d bytes buffered", w.Buffered()),
+			SubjectName: "the writer",
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package beio_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/beio"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+var errDiskFull = errors.New("disk full")
+
+func ExampleRecordingWriter() {
+	t := &expect.SpyTB{}
+
+	w := &beio.RecordingWriter{}
+	w.FailAfter(8, errDiskFull)
+
+	_, err := io.WriteString(w, "hello ")
+	expect.NoError(t, err)
+	_, err = io.WriteString(w, "world")
+	expect.It(t, errors.Is(err, errDiskFull)).To(be.Eq(true))
+
+	expect.It(t, w).To(
+		beio.WroteTotal(8),
+		beio.WrittenChunks(be.Size[[]byte](be.Eq(3))),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the writer to have written chunks with a size equal to 3, but it was 2]
+}
+
+func ExampleFlushed() {
+	t := &expect.SpyTB{}
+
+	w := &beio.RecordingWriter{}
+	fmt.Fprint(w, "hello")
+	w.Flush()
+	fmt.Fprint(w, " ")
+	fmt.Fprint(w, "world")
+
+	expect.It(t, w).To(beio.WroteTotal(11), beio.Flushed())
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the writer to have been flushed, but it had 2 writes after the last flush]
+}
+
+func ExampleBufferFlushed() {
+	t := &expect.SpyTB{}
+
+	bw := bufio.NewWriter(&beio.RecordingWriter{})
+	fmt.Fprintf(bw, "hello")
+
+	expect.It(t, bw).To(beio.BufferFlushed())
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the writer to have been flushed, but it had 5 bytes buffered]
+}
+
+func TestRecordingWriter(t *testing.T) {
+	t.Run("records each write", func(t *testing.T) {
+		w := &beio.RecordingWriter{}
+		fmt.Fprint(w, "a")
+		fmt.Fprint(w, "bc")
+
+		expect.It(t, w.String()).To(be.Eq("abc"))
+		expect.It(t, w).To(beio.WroteTotal(3), beio.WrittenChunks(func(chunks [][]byte) expect.MatchResult {
+			return expect.MatchResult{
+				Description: "be a then bc",
+				Matches:     len(chunks) == 2 && string(chunks[0]) == "a" && string(chunks[1]) == "bc",
+			}
+		}))
+
+		spytb.VerifyFailingMatcher(t, w, beio.WroteTotal(4),
+			"expected the writer to have written 4 bytes, but it wrote 3 bytes in 2 writes",
+		)
+
+		w.Reset()
+		expect.It(t, w).To(beio.WroteTotal(0))
+	})
+
+	t.Run("fails after n bytes", func(t *testing.T) {
+		w := &beio.RecordingWriter{}
+		w.FailAfter(4, errDiskFull)
+
+		n, err := w.Write([]byte("abc"))
+		expect.It(t, n).To(be.Eq(3))
+		expect.NoError(t, err)
+
+		n, err = w.Write([]byte("def"))
+		expect.It(t, n).To(be.Eq(1))
+		expect.It(t, err).To(be.Eq(errDiskFull))
+
+		n, err = w.Write([]byte("g"))
+		expect.It(t, n).To(be.Eq(0))
+		expect.It(t, err).To(be.Eq(errDiskFull))
+
+		expect.It(t, w.String()).To(be.Eq("abcd"))
+	})
+
+	t.Run("short writes", func(t *testing.T) {
+		w := &beio.RecordingWriter{}
+		w.ShortWriteAfter(2)
+
+		n, err := w.Write([]byte("abc"))
+		expect.It(t, n).To(be.Eq(2))
+		expect.NoError(t, err)
+
+		_, err = io.Copy(w, &repeatReader{})
+		expect.It(t, err).To(be.Eq(io.ErrShortWrite))
+	})
+}
+
+func TestFlushed(t *testing.T) {
+	w := &beio.RecordingWriter{}
+	expect.It(t, w).To(beio.Flushed())
+
+	fmt.Fprint(w, "a")
+	spytb.VerifyFailingMatcher(t, w, beio.Flushed(),
+		"expected the writer to have been flushed, but it was never flushed",
+	)
+
+	w.Flush()
+	expect.It(t, w).To(beio.Flushed())
+	expect.It(t, w.Flushes()).To(be.Eq(1))
+
+	fmt.Fprint(w, "b")
+	fmt.Fprint(w, "c")
+	spytb.VerifyFailingMatcher(t, w, beio.Flushed(),
+		"expected the writer to have been flushed, but it had 2 writes after the last flush",
+	)
+}
+
+func TestBufferFlushed(t *testing.T) {
+	bw := bufio.NewWriter(&beio.RecordingWriter{})
+	expect.It(t, bw).To(beio.BufferFlushed())
+
+	fmt.Fprint(bw, "abc")
+	spytb.VerifyFailingMatcher(t, bw, beio.BufferFlushed(),
+		"expected the writer to have been flushed, but it had 3 bytes buffered",
+	)
+
+	expect.NoError(t, bw.Flush())
+	expect.It(t, bw).To(beio.BufferFlushed())
+}
+
+type repeatReader struct{}
+
+func (repeatReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'x'
+	}
+	return len(p), nil
+}
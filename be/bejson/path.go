@@ -0,0 +1,87 @@
+package bejson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/internal/tree"
+)
+
+// Path parses the JSON and runs the matcher against the value found at the path. The
+// value is decoded into T, so Path("$.id", be.Eq(3)) works as expected for numbers.
+// Paths support a practical subset of JSONPath, shared with beyaml.Path and betoml.Path:
+// the root $, .key and ['key'] member access, and [n] array indexing, e.g. $.todos[0].name.
+func Path[T any](path string, matcher expect.Matcher[T]) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have %s", path),
+			SubjectName: "JSON",
+		}
+
+		segments, err := tree.ParsePath(path)
+		if err != nil {
+			result.But = err.Error()
+			return result
+		}
+
+		var doc any
+		if err := decodeJSON(rdr, &doc); err != nil {
+			result.But = fmt.Sprintf("it could not be parsed: %v", err)
+			return result
+		}
+
+		node := doc
+		for i, seg := range segments {
+			next, ok := lookupJSON(node, seg)
+			if !ok {
+				result.But = fmt.Sprintf("%s was not found", segments[:i+1])
+				return result
+			}
+			node = next
+		}
+
+		var thing T
+		if err := convertJSON(node, &thing); err != nil {
+			result.Description = fmt.Sprintf("have %s of type %T", path, thing)
+			result.But = fmt.Sprintf("it was %s", compactJSON(node))
+			return result
+		}
+
+		r := matcher(thing)
+		r = r.WithDescription(fmt.Sprintf("have %s %s", path, be.Complement(r)))
+		r.SubjectName = "JSON"
+		return r
+	}
+}
+
+// lookupJSON returns the value the path segment refers to within node.
+func lookupJSON(node, seg any) (any, bool) {
+	switch seg := seg.(type) {
+	case int:
+		arr, ok := node.([]any)
+		if !ok || seg >= len(arr) {
+			return nil, false
+		}
+		return arr[seg], true
+	case string:
+		obj, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		v, ok := obj[seg]
+		return v, ok
+	}
+	return nil, false
+}
+
+// convertJSON round trips the decoded value through JSON to decode it into v.
+func convertJSON(node, v any) error {
+	b, err := json.Marshal(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
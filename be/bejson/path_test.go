@@ -0,0 +1,93 @@
+package bejson_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/bejson"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func ExamplePath() {
+	t := &expect.SpyTB{}
+
+	someJSON := strings.NewReader(`{"id": 3, "todos": [{"name": "Egg"}]}`)
+
+	expect.It[io.Reader](t, someJSON).To(bejson.Path("$.todos[0].name", be.Eq("Egg")))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExamplePath_fail() {
+	t := &expect.SpyTB{}
+
+	someJSON := strings.NewReader(`{"id": 3}`)
+
+	expect.It[io.Reader](t, someJSON).To(bejson.Path("$.id", be.Eq(4)))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected JSON to have $.id equal to 4, but it was 3]
+}
+
+func TestPath(t *testing.T) {
+	const doc = `{"id": 3, "ratio": 0.5, "meta": {"first name": "Pepper"}, "tags": ["a", "b"]}`
+
+	t.Run("passing", func(t *testing.T) {
+		expect.It[io.Reader](t, strings.NewReader(doc)).To(bejson.Path("$.id", be.Eq(3)))
+		expect.It[io.Reader](t, strings.NewReader(doc)).To(bejson.Path("$.ratio", be.Less(1.0)))
+		expect.It[io.Reader](t, strings.NewReader(doc)).To(bejson.Path("$.meta['first name']", be.Eq("Pepper")))
+		expect.It[io.Reader](t, strings.NewReader(doc)).To(bejson.Path("tags[1]", be.Eq("b")))
+		expect.It[io.Reader](t, strings.NewReader(doc)).To(bejson.Path("$.tags", be.ShallowEq([]string{"a", "b"})))
+		expect.It[io.Reader](t, strings.NewReader(doc)).To(bejson.Path("$", be.Key("id", be.Eq[any](3.0))))
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			matcher expect.Matcher[io.Reader]
+			want    string
+		}{
+			{
+				name:    "missing key",
+				matcher: bejson.Path("$.meta.last", be.Eq("Stanley")),
+				want:    `expected JSON to have $.meta.last, but $.meta.last was not found`,
+			},
+			{
+				name:    "index out of range",
+				matcher: bejson.Path("$.tags[2]", be.Eq("c")),
+				want:    `expected JSON to have $.tags[2], but $.tags[2] was not found`,
+			},
+			{
+				name:    "indexing an object",
+				matcher: bejson.Path("$.meta[0].name", be.Eq("c")),
+				want:    `expected JSON to have $.meta[0].name, but $.meta[0] was not found`,
+			},
+			{
+				name:    "wrong type",
+				matcher: bejson.Path("$.tags", be.Eq("a")),
+				want:    `expected JSON to have $.tags of type string, but it was ["a","b"]`,
+			},
+			{
+				name:    "invalid path",
+				matcher: bejson.Path("$.tags[x]", be.Eq("a")),
+				want:    `expected JSON to have $.tags[x], but the path "$.tags[x]" is invalid: "x" is not an index`,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(doc), tt.matcher, tt.want)
+			})
+		}
+
+		spytb.VerifyFailingMatcher[io.Reader](
+			t,
+			strings.NewReader(`{`),
+			bejson.Path("$.id", be.Eq(3)),
+			`expected JSON to have $.id, but it could not be parsed: unexpected EOF`,
+		)
+	})
+}
@@ -0,0 +1,476 @@
+package bejson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jsteenb2/expect"
+)
+
+// maxRefDepth guards against $ref cycles that never consume any of the instance. The depth
+// counts the $refs followed at one location in the instance, so it starts again from zero
+// for each array item and object property.
+const maxRefDepth = 64
+
+// MatchSchema validates the JSON read from the io.Reader against the given JSON Schema.
+// A subset of draft 2020-12 is supported: type, enum, const, required, properties,
+// additionalProperties, items, prefixItems, min/max for numbers, strings, arrays and
+// objects, pattern, allOf/anyOf/oneOf/not and $ref within the schema document.
+// Patterns use Go's regexp syntax. No network fetches are made, a $ref outside of
+// the document is reported as a violation.
+func MatchSchema(schema []byte) expect.Matcher[io.Reader] {
+	var root any
+	schemaErr := decodeJSON(bytes.NewReader(schema), &root)
+	patterns := compilePatterns(root, map[string]patternRegexp{})
+
+	return func(rdr io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: "match the JSON schema",
+			SubjectName: "JSON",
+		}
+		if schemaErr != nil {
+			result.But = fmt.Sprintf("the schema could not be parsed: %v", schemaErr)
+			return result
+		}
+
+		var instance any
+		if err := decodeJSON(rdr, &instance); err != nil {
+			result.But = fmt.Sprintf("it could not be parsed: %v", err)
+			return result
+		}
+
+		v := schemaValidator{root: root, patterns: patterns}
+		v.validate(root, instance, "", 0)
+		if len(v.violations) == 0 {
+			result.Matches = true
+			return result
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "it had %d violation(s):", len(v.violations))
+		for _, violation := range v.violations {
+			sb.WriteString("\n\t")
+			sb.WriteString(violation)
+		}
+		result.But = sb.String()
+		return result
+	}
+}
+
+func decodeJSON(rdr io.Reader, v any) error {
+	dec := json.NewDecoder(rdr)
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after top-level value")
+	}
+	return nil
+}
+
+type schemaValidator struct {
+	root       any
+	patterns   map[string]patternRegexp
+	violations []string
+}
+
+// patternRegexp is a pattern from the schema, compiled once when the schema is loaded.
+type patternRegexp struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// compilePatterns compiles every pattern keyword found in the schema.
+func compilePatterns(schema any, patterns map[string]patternRegexp) map[string]patternRegexp {
+	switch s := schema.(type) {
+	case map[string]any:
+		if pattern, ok := s["pattern"].(string); ok {
+			if _, seen := patterns[pattern]; !seen {
+				re, err := regexp.Compile(pattern)
+				patterns[pattern] = patternRegexp{re: re, err: err}
+			}
+		}
+		for _, sub := range s {
+			compilePatterns(sub, patterns)
+		}
+	case []any:
+		for _, sub := range s {
+			compilePatterns(sub, patterns)
+		}
+	}
+	return patterns
+}
+
+func (v *schemaValidator) failf(path, format string, args ...any) {
+	if path == "" {
+		path = "(root)"
+	}
+	v.violations = append(v.violations, path+": "+fmt.Sprintf(format, args...))
+}
+
+// valid reports whether the instance satisfies the schema without recording any violations.
+func (v *schemaValidator) valid(schema, instance any, depth int) bool {
+	sub := schemaValidator{root: v.root, patterns: v.patterns}
+	sub.validate(schema, instance, "", depth)
+	return len(sub.violations) == 0
+}
+
+func (v *schemaValidator) validate(schema, instance any, path string, depth int) {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.failf(path, "no value is allowed by a false schema")
+		}
+		return
+	case map[string]any:
+		v.validateObjectSchema(s, instance, path, depth)
+	default:
+		v.failf(path, "invalid schema of type %T", schema)
+	}
+}
+
+func (v *schemaValidator) validateObjectSchema(s map[string]any, instance any, path string, depth int) {
+	if ref, ok := s["$ref"].(string); ok {
+		if depth >= maxRefDepth {
+			v.failf(path, "$ref %q exceeded the maximum depth of %d", ref, maxRefDepth)
+			return
+		}
+		target, err := v.resolveRef(ref)
+		if err != nil {
+			v.failf(path, "%v", err)
+			return
+		}
+		v.validate(target, instance, path, depth+1)
+	}
+
+	if t, ok := s["type"]; ok {
+		v.validateType(t, instance, path)
+	}
+	if enum, ok := s["enum"].([]any); ok {
+		if !containsJSON(enum, instance) {
+			v.failf(path, "value %s is not one of %s", compactJSON(instance), compactJSON(enum))
+		}
+	}
+	if c, ok := s["const"]; ok && !equalJSON(c, instance) {
+		v.failf(path, "value %s is not equal to %s", compactJSON(instance), compactJSON(c))
+	}
+
+	v.validateCombinators(s, instance, path, depth)
+
+	switch inst := instance.(type) {
+	case json.Number:
+		v.validateNumber(s, inst, path)
+	case string:
+		v.validateString(s, inst, path)
+	case []any:
+		v.validateArray(s, inst, path)
+	case map[string]any:
+		v.validateObject(s, inst, path)
+	}
+}
+
+func (v *schemaValidator) validateCombinators(s map[string]any, instance any, path string, depth int) {
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			v.validate(sub, instance, path, depth)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		var matched bool
+		for _, sub := range anyOf {
+			if v.valid(sub, instance, depth) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.failf(path, "value does not match any of the anyOf schemas")
+		}
+	}
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		var matched int
+		for _, sub := range oneOf {
+			if v.valid(sub, instance, depth) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.failf(path, "value matches %d of the oneOf schemas, expected exactly 1", matched)
+		}
+	}
+	if not, ok := s["not"]; ok && v.valid(not, instance, depth) {
+		v.failf(path, "value must not match the not schema")
+	}
+}
+
+func (v *schemaValidator) validateType(t, instance any, path string) {
+	var types []string
+	switch tt := t.(type) {
+	case string:
+		types = []string{tt}
+	case []any:
+		for _, typ := range tt {
+			if s, ok := typ.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+
+	got := jsonType(instance)
+	for _, typ := range types {
+		if typ == got || typ == "number" && got == "integer" {
+			return
+		}
+	}
+	v.failf(path, "expected type %s but got %s", strings.Join(types, " or "), got)
+}
+
+func (v *schemaValidator) validateNumber(s map[string]any, n json.Number, path string) {
+	f, err := n.Float64()
+	if err != nil {
+		v.failf(path, "invalid number %s", n)
+		return
+	}
+	if min, ok := schemaNumber(s, "minimum"); ok && f < min {
+		v.failf(path, "%s is less than the minimum of %v", n, min)
+	}
+	if max, ok := schemaNumber(s, "maximum"); ok && f > max {
+		v.failf(path, "%s is greater than the maximum of %v", n, max)
+	}
+	if min, ok := schemaNumber(s, "exclusiveMinimum"); ok && f <= min {
+		v.failf(path, "%s is not greater than the exclusive minimum of %v", n, min)
+	}
+	if max, ok := schemaNumber(s, "exclusiveMaximum"); ok && f >= max {
+		v.failf(path, "%s is not less than the exclusive maximum of %v", n, max)
+	}
+	if mult, ok := schemaNumber(s, "multipleOf"); ok && mult > 0 {
+		if q := f / mult; q != math.Trunc(q) {
+			v.failf(path, "%s is not a multiple of %v", n, mult)
+		}
+	}
+}
+
+func (v *schemaValidator) validateString(s map[string]any, str, path string) {
+	length := utf8.RuneCountInString(str)
+	if min, ok := schemaNumber(s, "minLength"); ok && float64(length) < min {
+		v.failf(path, "string length %d is less than the minLength of %v", length, min)
+	}
+	if max, ok := schemaNumber(s, "maxLength"); ok && float64(length) > max {
+		v.failf(path, "string length %d is greater than the maxLength of %v", length, max)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		compiled := v.patterns[pattern]
+		if compiled.err != nil {
+			v.failf(path, "invalid pattern %q: %v", pattern, compiled.err)
+		} else if !compiled.re.MatchString(str) {
+			v.failf(path, "%q does not match pattern %q", str, pattern)
+		}
+	}
+}
+
+func (v *schemaValidator) validateArray(s map[string]any, arr []any, path string) {
+	if min, ok := schemaNumber(s, "minItems"); ok && float64(len(arr)) < min {
+		v.failf(path, "array has %d item(s), fewer than the minItems of %v", len(arr), min)
+	}
+	if max, ok := schemaNumber(s, "maxItems"); ok && float64(len(arr)) > max {
+		v.failf(path, "array has %d item(s), more than the maxItems of %v", len(arr), max)
+	}
+	if unique, _ := s["uniqueItems"].(bool); unique {
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if equalJSON(arr[i], arr[j]) {
+					v.failf(path, "items at index %d and %d are not unique", i, j)
+				}
+			}
+		}
+	}
+
+	var prefixLen int
+	if prefix, ok := s["prefixItems"].([]any); ok {
+		for i, sub := range prefix {
+			if i >= len(arr) {
+				break
+			}
+			v.validate(sub, arr[i], path+"/"+strconv.Itoa(i), 0)
+		}
+		prefixLen = len(prefix)
+	}
+	if items, ok := s["items"]; ok {
+		for i := prefixLen; i < len(arr); i++ {
+			v.validate(items, arr[i], path+"/"+strconv.Itoa(i), 0)
+		}
+	}
+}
+
+func (v *schemaValidator) validateObject(s map[string]any, obj map[string]any, path string) {
+	if min, ok := schemaNumber(s, "minProperties"); ok && float64(len(obj)) < min {
+		v.failf(path, "object has %d properties, fewer than the minProperties of %v", len(obj), min)
+	}
+	if max, ok := schemaNumber(s, "maxProperties"); ok && float64(len(obj)) > max {
+		v.failf(path, "object has %d properties, more than the maxProperties of %v", len(obj), max)
+	}
+	if required, ok := s["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, ok := obj[name]; !ok {
+				v.failf(path, "missing required property %q", name)
+			}
+		}
+	}
+
+	props, _ := s["properties"].(map[string]any)
+	additional, hasAdditional := s["additionalProperties"]
+	for _, name := range slices.Sorted(maps.Keys(obj)) {
+		propPath := path + "/" + escapePointer(name)
+		if sub, ok := props[name]; ok {
+			v.validate(sub, obj[name], propPath, 0)
+			continue
+		}
+		if !hasAdditional {
+			continue
+		}
+		if allowed, ok := additional.(bool); ok && !allowed {
+			v.failf(propPath, "additional property %q is not allowed", name)
+			continue
+		}
+		v.validate(additional, obj[name], propPath, 0)
+	}
+}
+
+// resolveRef resolves a JSON pointer reference within the root schema document.
+func (v *schemaValidator) resolveRef(ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("$ref %q is not within the schema document", ref)
+	}
+	pointer, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		return nil, fmt.Errorf("$ref %q is invalid: %v", ref, err)
+	}
+
+	node := v.root
+	if pointer == "" {
+		return node, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch n := node.(type) {
+		case map[string]any:
+			next, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("$ref %q could not be resolved", ref)
+			}
+			node = next
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("$ref %q could not be resolved", ref)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("$ref %q could not be resolved", ref)
+		}
+	}
+	return node, nil
+}
+
+func jsonType(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if f, err := val.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func schemaNumber(s map[string]any, key string) (float64, bool) {
+	n, ok := s[key].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func containsJSON(list []any, v any) bool {
+	for _, item := range list {
+		if equalJSON(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalJSON(a, b any) bool {
+	an, aIsNum := a.(json.Number)
+	bn, bIsNum := b.(json.Number)
+	if aIsNum && bIsNum {
+		af, aErr := an.Float64()
+		bf, bErr := bn.Float64()
+		return aErr == nil && bErr == nil && af == bf
+	}
+
+	switch av := a.(type) {
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equalJSON(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, val := range av {
+			other, ok := bv[k]
+			if !ok || !equalJSON(val, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
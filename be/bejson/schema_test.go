@@ -0,0 +1,214 @@
+package bejson_test
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be/behttp"
+	"github.com/jsteenb2/expect/be/bejson"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+var personSchema = []byte(`{
+	"$defs": {
+		"name": {"type": "string", "minLength": 1, "pattern": "^[A-Z]"}
+	},
+	"type": "object",
+	"required": ["name", "age"],
+	"properties": {
+		"name": {"$ref": "#/$defs/name"},
+		"age": {"type": "integer", "minimum": 0, "maximum": 150},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2}
+	},
+	"additionalProperties": false
+}`)
+
+func ExampleMatchSchema() {
+	t := &expect.SpyTB{}
+
+	someJSON := strings.NewReader(`{"name": "Pepper", "age": 14, "role": "admin"}`)
+
+	expect.It[io.Reader](t, someJSON).To(bejson.MatchSchema(personSchema))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleMatchSchema_fail() {
+	t := &expect.SpyTB{}
+
+	someJSON := strings.NewReader(`{"name": "pepper", "role": "owner"}`)
+
+	expect.It[io.Reader](t, someJSON).To(bejson.MatchSchema(personSchema))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected JSON to match the JSON schema, but it had 3 violation(s):
+	// 	(root): missing required property "age"
+	// 	/name: "pepper" does not match pattern "^[A-Z]"
+	// 	/role: value "owner" is not one of ["admin","user"]]
+}
+
+func TestMatchSchema(t *testing.T) {
+	t.Run("passing", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			schema string
+			input  string
+		}{
+			{name: "true schema", schema: `true`, input: `{"anything": [1, 2]}`},
+			{name: "number accepts integer", schema: `{"type": "number"}`, input: `3`},
+			{name: "integer with fractional zero", schema: `{"type": "integer"}`, input: `3.0`},
+			{name: "nullable type list", schema: `{"type": ["string", "null"]}`, input: `null`},
+			{name: "const", schema: `{"const": {"a": [1, 2]}}`, input: `{"a": [1, 2.0]}`},
+			{name: "prefix items", schema: `{"prefixItems": [{"type": "string"}], "items": {"type": "integer"}}`, input: `["a", 1, 2]`},
+			{name: "additional properties schema", schema: `{"additionalProperties": {"type": "boolean"}}`, input: `{"a": true}`},
+			{name: "anyOf", schema: `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`, input: `1`},
+			{name: "oneOf", schema: `{"oneOf": [{"minimum": 5}, {"maximum": 2}]}`, input: `1`},
+			{name: "recursive ref", schema: `{"type": "object", "properties": {"child": {"$ref": "#"}}}`, input: `{"child": {"child": {}}}`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				expect.It[io.Reader](t, strings.NewReader(tt.input)).To(bejson.MatchSchema([]byte(tt.schema)))
+			})
+		}
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			schema string
+			input  string
+			want   string
+		}{
+			{
+				name:   "wrong type",
+				schema: `{"type": "string"}`,
+				input:  `12`,
+				want:   `(root): expected type string but got integer`,
+			},
+			{
+				name:   "nested array item",
+				schema: `{"properties": {"tags": {"items": {"type": "string"}}}}`,
+				input:  `{"tags": ["a", 2]}`,
+				want:   `/tags/1: expected type string but got integer`,
+			},
+			{
+				name:   "additional property",
+				schema: `{"properties": {"a": true}, "additionalProperties": false}`,
+				input:  `{"a": 1, "b/c": 2}`,
+				want:   `/b~1c: additional property "b/c" is not allowed`,
+			},
+			{
+				name:   "exclusive maximum",
+				schema: `{"exclusiveMaximum": 10}`,
+				input:  `10`,
+				want:   `(root): 10 is not less than the exclusive maximum of 10`,
+			},
+			{
+				name:   "max length counts runes",
+				schema: `{"maxLength": 2}`,
+				input:  `"héé"`,
+				want:   `(root): string length 3 is greater than the maxLength of 2`,
+			},
+			{
+				name:   "unique items",
+				schema: `{"uniqueItems": true}`,
+				input:  `[1, 2, 1]`,
+				want:   `(root): items at index 0 and 2 are not unique`,
+			},
+			{
+				name:   "not",
+				schema: `{"not": {"type": "null"}}`,
+				input:  `null`,
+				want:   `(root): value must not match the not schema`,
+			},
+			{
+				name:   "oneOf matching both",
+				schema: `{"oneOf": [{"minimum": 1}, {"maximum": 5}]}`,
+				input:  `3`,
+				want:   `(root): value matches 2 of the oneOf schemas, expected exactly 1`,
+			},
+			{
+				name:   "remote ref",
+				schema: `{"$ref": "https://example.com/schema.json"}`,
+				input:  `{}`,
+				want:   `(root): $ref "https://example.com/schema.json" is not within the schema document`,
+			},
+			{
+				name:   "missing ref",
+				schema: `{"$ref": "#/$defs/nope"}`,
+				input:  `{}`,
+				want:   `(root): $ref "#/$defs/nope" could not be resolved`,
+			},
+			{
+				name:   "ref cycle",
+				schema: `{"$defs": {"a": {"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"}`,
+				input:  `{}`,
+				want:   `exceeded the maximum depth of 64`,
+			},
+			{
+				name:   "invalid pattern",
+				schema: `{"properties": {"a": {"pattern": "(["}}}`,
+				input:  `{"a": "x"}`,
+				want:   `/a: invalid pattern "([": error parsing regexp`,
+			},
+			{
+				name:   "invalid schema",
+				schema: `{"type":`,
+				input:  `{}`,
+				want:   `expected JSON to match the JSON schema, but the schema could not be parsed: unexpected EOF`,
+			},
+			{
+				name:   "invalid instance",
+				schema: `true`,
+				input:  `{} {}`,
+				want:   `expected JSON to match the JSON schema, but it could not be parsed: unexpected data after top-level value`,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				spytb.VerifyFailingMatcher[io.Reader](
+					t,
+					strings.NewReader(tt.input),
+					bejson.MatchSchema([]byte(tt.schema)),
+					tt.want,
+				)
+			})
+		}
+	})
+
+	t.Run("recursive ref deeper than the ref depth", func(t *testing.T) {
+		input := strings.Repeat(`{"child": `, 70) + `{}` + strings.Repeat(`}`, 70)
+		schema := `{"type": "object", "properties": {"child": {"$ref": "#"}}}`
+
+		expect.It[io.Reader](t, strings.NewReader(input)).To(bejson.MatchSchema([]byte(schema)))
+	})
+
+	t.Run("reports every violation", func(t *testing.T) {
+		spyTB := &expect.SpyTB{}
+		input := `{"name": "", "age": -1, "tags": ["a", "b", 3], "extra": true}`
+
+		expect.It[io.Reader](spyTB, strings.NewReader(input)).To(bejson.MatchSchema(personSchema))
+		expect.It(t, spyTB).To(
+			spytb.Error("it had 6 violation(s):"),
+			spytb.Error(`/age: -1 is less than the minimum of 0`),
+			spytb.Error(`/extra: additional property "extra" is not allowed`),
+			spytb.Error(`/name: string length 0 is less than the minLength of 1`),
+			spytb.Error(`/name: "" does not match pattern "^[A-Z]"`),
+			spytb.Error(`/tags: array has 3 item(s), more than the maxItems of 2`),
+			spytb.Error(`/tags/2: expected type string but got integer`),
+		)
+	})
+
+	t.Run("composes with response body", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		res.Body.WriteString(`{"name": "Pepper", "age": 14}`)
+
+		expect.It(t, res.Result()).To(behttp.RespBody(bejson.MatchSchema(personSchema)))
+	})
+}
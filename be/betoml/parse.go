@@ -0,0 +1,660 @@
+package betoml
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jsteenb2/expect/be/internal/tree"
+)
+
+// parse reads a TOML 1.0 document into map[string]any, []any, string, int64, float64,
+// bool and time.Time values. Local date-times and dates are read in UTC, and local times
+// are on January 1st of year 0. Inline tables may span lines and end with a comma, as
+// TOML 1.1 allows.
+func parse(r io.Reader) (map[string]any, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	src := strings.TrimPrefix(string(b), "\ufeff")
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+
+	p := &parser{src: src, line: 1, root: newTable(definedTable)}
+	if err := p.parseDocument(); err != nil {
+		return nil, err
+	}
+	return p.root.toMap(), nil
+}
+
+type tableState int
+
+const (
+	// implicitTable was created as the parent of a [header], it can still be defined.
+	implicitTable tableState = iota
+	// definedTable was created by a [header].
+	definedTable
+	// dottedTable was created by a dotted key, i.e. a.b = 1.
+	dottedTable
+	// inlineTable was written as { ... } and cannot be extended.
+	inlineTable
+)
+
+type table struct {
+	state tableState
+	keys  map[string]any
+}
+
+// arrayOfTables is made by [[headers]].
+type arrayOfTables struct {
+	tables []*table
+}
+
+func newTable(state tableState) *table {
+	return &table{state: state, keys: make(map[string]any)}
+}
+
+func (t *table) toMap() map[string]any {
+	m := make(map[string]any, len(t.keys))
+	for k, v := range t.keys {
+		m[k] = toValue(v)
+	}
+	return m
+}
+
+func toValue(v any) any {
+	switch v := v.(type) {
+	case *table:
+		return v.toMap()
+	case *arrayOfTables:
+		l := make([]any, 0, len(v.tables))
+		for _, t := range v.tables {
+			l = append(l, t.toMap())
+		}
+		return l
+	case []any:
+		l := make([]any, 0, len(v))
+		for _, item := range v {
+			l = append(l, toValue(item))
+		}
+		return l
+	}
+	return v
+}
+
+type parser struct {
+	src     string
+	pos     int
+	line    int
+	root    *table
+	current *table
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) eof() bool               { return p.pos >= len(p.src) }
+func (p *parser) rest() string            { return p.src[p.pos:] }
+func (p *parser) hasPrefix(s string) bool { return strings.HasPrefix(p.rest(), s) }
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) advance(n int) {
+	for range n {
+		if p.eof() {
+			return
+		}
+		if p.src[p.pos] == '\n' {
+			p.line++
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) skipSpaces() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.advance(1)
+	}
+}
+
+// skipSpaceAndComments moves past white space, comments and, when lines is set, line breaks.
+func (p *parser) skipSpaceAndComments(lines bool) {
+	for !p.eof() {
+		switch c := p.peek(); {
+		case c == ' ' || c == '\t' || lines && c == '\n':
+			p.advance(1)
+		case c == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.advance(1)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// endOfLine expects nothing but a comment before the next line.
+func (p *parser) endOfLine() error {
+	p.skipSpaceAndComments(false)
+	if p.eof() {
+		return nil
+	}
+	if p.peek() != '\n' {
+		return p.errorf("expected the end of the line, found %q", p.currentLine())
+	}
+	p.advance(1)
+	return nil
+}
+
+func (p *parser) currentLine() string {
+	line, _, _ := strings.Cut(p.rest(), "\n")
+	return strings.TrimSpace(line)
+}
+
+func (p *parser) parseDocument() error {
+	p.current = p.root
+	for {
+		p.skipSpaceAndComments(true)
+		if p.eof() {
+			return nil
+		}
+
+		var err error
+		switch {
+		case p.hasPrefix("[["):
+			err = p.parseArrayTableHeader()
+		case p.peek() == '[':
+			err = p.parseTableHeader()
+		default:
+			err = p.parseKeyValue(p.current)
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.endOfLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) parseTableHeader() error {
+	p.advance(1)
+	keys, err := p.parseHeaderKey("]")
+	if err != nil {
+		return err
+	}
+
+	parent, err := p.descend(p.root, keys[:len(keys)-1], keys, implicitTable)
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1].(string)
+	switch existing := parent.keys[last].(type) {
+	case nil:
+		t := newTable(definedTable)
+		parent.keys[last] = t
+		p.current = t
+	case *table:
+		if existing.state != implicitTable {
+			return p.errorf("the table %s is already defined", dotted(keys))
+		}
+		existing.state = definedTable
+		p.current = existing
+	default:
+		return p.errorf("the key %s is already defined", dotted(keys))
+	}
+	return nil
+}
+
+func (p *parser) parseArrayTableHeader() error {
+	p.advance(2)
+	keys, err := p.parseHeaderKey("]]")
+	if err != nil {
+		return err
+	}
+
+	parent, err := p.descend(p.root, keys[:len(keys)-1], keys, implicitTable)
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1].(string)
+	t := newTable(definedTable)
+	switch existing := parent.keys[last].(type) {
+	case nil:
+		parent.keys[last] = &arrayOfTables{tables: []*table{t}}
+	case *arrayOfTables:
+		existing.tables = append(existing.tables, t)
+	case []any:
+		return p.errorf("cannot append to the static array %s", dotted(keys))
+	default:
+		return p.errorf("the key %s is already defined", dotted(keys))
+	}
+	p.current = t
+	return nil
+}
+
+func (p *parser) parseHeaderKey(end string) (tree.Path, error) {
+	p.skipSpaces()
+	keys, err := p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if !p.hasPrefix(end) {
+		return nil, p.errorf("expected %s after the table name", end)
+	}
+	p.advance(len(end))
+	return keys, nil
+}
+
+// descend walks to the table holding the last key, creating missing tables as it goes.
+// The last element of an array of tables is used.
+func (p *parser) descend(t *table, keys, full tree.Path, create tableState) (*table, error) {
+	for i, k := range keys {
+		switch next := t.keys[k.(string)].(type) {
+		case nil:
+			nt := newTable(create)
+			t.keys[k.(string)] = nt
+			t = nt
+		case *table:
+			if next.state == inlineTable {
+				return nil, p.errorf("the inline table %s cannot be extended", dotted(full[:i+1]))
+			}
+			if create == dottedTable && next.state != dottedTable {
+				return nil, p.errorf("the table %s is already defined", dotted(full[:i+1]))
+			}
+			t = next
+		case *arrayOfTables:
+			if create == dottedTable {
+				return nil, p.errorf("the key %s is already defined", dotted(full[:i+1]))
+			}
+			t = next.tables[len(next.tables)-1]
+		default:
+			return nil, p.errorf("the key %s is already defined", dotted(full[:i+1]))
+		}
+	}
+	return t, nil
+}
+
+func (p *parser) parseKeyValue(t *table) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipSpaces()
+	if p.peek() != '=' {
+		return p.errorf("expected = after the key %s", dotted(keys))
+	}
+	p.advance(1)
+	p.skipSpaces()
+
+	v, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+
+	parent, err := p.descend(t, keys[:len(keys)-1], keys, dottedTable)
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1].(string)
+	if _, dup := parent.keys[last]; dup {
+		return p.errorf("the key %s is already defined", dotted(keys))
+	}
+	parent.keys[last] = v
+	return nil
+}
+
+// parseKey reads a possibly dotted key of bare and quoted parts.
+func (p *parser) parseKey() (tree.Path, error) {
+	var keys tree.Path
+	for {
+		p.skipSpaces()
+		var (
+			key string
+			err error
+		)
+		switch c := p.peek(); {
+		case c == '"':
+			key, err = p.parseBasicString()
+		case c == '\'':
+			key, err = p.parseLiteralString()
+		default:
+			start := p.pos
+			for c := p.peek(); isBareKeyChar(c); c = p.peek() {
+				p.advance(1)
+			}
+			key = p.src[start:p.pos]
+			if key == "" {
+				return nil, p.errorf("expected a key, found %q", p.currentLine())
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+
+		p.skipSpaces()
+		if p.peek() != '.' {
+			return keys, nil
+		}
+		p.advance(1)
+	}
+}
+
+// dotted renders keys as a TOML dotted key, quoting the parts that are not bare keys.
+func dotted(keys tree.Path) string {
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		key := fmt.Sprint(k)
+		bare := key != ""
+		for i := range len(key) {
+			bare = bare && isBareKeyChar(key[i])
+		}
+		if !bare {
+			key = strconv.Quote(key)
+		}
+		parts = append(parts, key)
+	}
+	return strings.Join(parts, ".")
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *parser) parseValue() (any, error) {
+	switch c := p.peek(); {
+	case p.hasPrefix(`"""`):
+		return p.parseMultilineString('"')
+	case p.hasPrefix("'''"):
+		return p.parseMultilineString('\'')
+	case c == '"':
+		return p.parseBasicString()
+	case c == '\'':
+		return p.parseLiteralString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseInlineTable()
+	case p.hasPrefix("true"):
+		p.advance(4)
+		return true, nil
+	case p.hasPrefix("false"):
+		p.advance(5)
+		return false, nil
+	case c == 0 || c == '\n' || c == '#':
+		return nil, p.errorf("expected a value")
+	}
+	return p.parseNumberOrDate()
+}
+
+func (p *parser) parseBasicString() (string, error) {
+	p.advance(1)
+	var sb strings.Builder
+	for {
+		switch c := p.peek(); c {
+		case 0, '\n':
+			return "", p.errorf("the string is not closed")
+		case '"':
+			p.advance(1)
+			return sb.String(), nil
+		case '\\':
+			if err := p.readEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+			p.advance(1)
+		}
+	}
+}
+
+func (p *parser) parseLiteralString() (string, error) {
+	p.advance(1)
+	end := strings.IndexAny(p.rest(), "'\n")
+	if end == -1 || p.rest()[end] == '\n' {
+		return "", p.errorf("the string is not closed")
+	}
+	s := p.rest()[:end]
+	p.advance(end + 1)
+	return s, nil
+}
+
+func (p *parser) parseMultilineString(q byte) (string, error) {
+	p.advance(3)
+	if p.peek() == '\n' {
+		p.advance(1)
+	}
+
+	var sb strings.Builder
+	for {
+		c := p.peek()
+		switch {
+		case p.eof():
+			return "", p.errorf("the string is not closed")
+		case c == q && p.hasPrefix(strings.Repeat(string(q), 3)):
+			// up to two quotes may come right before the closing ones
+			n := 3
+			for n < 5 && p.pos+n < len(p.src) && p.src[p.pos+n] == q {
+				n++
+			}
+			sb.WriteString(strings.Repeat(string(q), n-3))
+			p.advance(n)
+			return sb.String(), nil
+		case c == '\\' && q == '"':
+			rest := strings.TrimLeft(p.rest()[1:], " \t")
+			if strings.HasPrefix(rest, "\n") {
+				// a line ending backslash trims the white space up to the next text
+				p.advance(1)
+				for c := p.peek(); c == ' ' || c == '\t' || c == '\n'; c = p.peek() {
+					p.advance(1)
+				}
+				continue
+			}
+			if err := p.readEscape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+			p.advance(1)
+		}
+	}
+}
+
+var escapes = map[byte]string{
+	'b': "\b", 't': "\t", 'n': "\n", 'f': "\f", 'r': "\r", '"': `"`, '\\': `\`,
+}
+
+func (p *parser) readEscape(sb *strings.Builder) error {
+	c := byte(0)
+	if p.pos+1 < len(p.src) {
+		c = p.src[p.pos+1]
+	}
+	if s, ok := escapes[c]; ok {
+		sb.WriteString(s)
+		p.advance(2)
+		return nil
+	}
+
+	size := map[byte]int{'u': 4, 'U': 8}[c]
+	if size == 0 || p.pos+2+size > len(p.src) {
+		return p.errorf("invalid escape \\%c", c)
+	}
+	hex := p.src[p.pos+2 : p.pos+2+size]
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || !utf8.ValidRune(rune(n)) {
+		return p.errorf("invalid escape \\%c%s", c, hex)
+	}
+	sb.WriteRune(rune(n))
+	p.advance(2 + size)
+	return nil
+}
+
+func (p *parser) parseArray() (any, error) {
+	p.advance(1)
+	l := []any{}
+	for {
+		p.skipSpaceAndComments(true)
+		if p.peek() == ']' {
+			p.advance(1)
+			return l, nil
+		}
+
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, v)
+
+		p.skipSpaceAndComments(true)
+		switch p.peek() {
+		case ',':
+			p.advance(1)
+		case ']':
+		default:
+			return nil, p.errorf("expected , or ] in the array, found %q", p.currentLine())
+		}
+	}
+}
+
+func (p *parser) parseInlineTable() (any, error) {
+	p.advance(1)
+	t := newTable(dottedTable)
+	for {
+		p.skipSpaceAndComments(true)
+		if p.peek() == '}' {
+			p.advance(1)
+			t.state = inlineTable
+			return t, nil
+		}
+
+		if err := p.parseKeyValue(t); err != nil {
+			return nil, err
+		}
+
+		p.skipSpaceAndComments(true)
+		switch p.peek() {
+		case ',':
+			p.advance(1)
+		case '}':
+		default:
+			return nil, p.errorf("expected , or } in the inline table, found %q", p.currentLine())
+		}
+	}
+}
+
+func (p *parser) parseNumberOrDate() (any, error) {
+	start := p.pos
+	for c := p.peek(); isBareKeyChar(c) || c == '+' || c == '.' || c == ':'; c = p.peek() {
+		p.advance(1)
+	}
+	// a date and time may be separated by a space
+	if p.pos-start == 10 && p.peek() == ' ' && p.pos+3 < len(p.src) && p.src[p.pos+3] == ':' {
+		p.advance(1)
+		for c := p.peek(); isBareKeyChar(c) || c == '+' || c == '.' || c == ':'; c = p.peek() {
+			p.advance(1)
+		}
+	}
+	s := p.src[start:p.pos]
+	if s == "" {
+		return nil, p.errorf("expected a value, found %q", p.currentLine())
+	}
+
+	if v, ok := parseDateTime(s); ok {
+		return v, nil
+	}
+	if v, ok := parseNumber(s); ok {
+		return v, nil
+	}
+	return nil, p.errorf("%q is not a valid value", s)
+}
+
+func parseDateTime(s string) (time.Time, bool) {
+	if len(s) >= 11 && (s[10] == ' ' || s[10] == 't') {
+		s = s[:10] + "T" + s[11:]
+	}
+	s = strings.Replace(s, "z", "Z", 1)
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, s, time.UTC); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.ParseInLocation("15:04:05.999999999", s, time.UTC); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func parseNumber(s string) (any, bool) {
+	switch strings.TrimLeft(s, "+-") {
+	case "inf":
+		if s[0] == '-' {
+			return math.Inf(-1), true
+		}
+		return math.Inf(1), true
+	case "nan":
+		return math.NaN(), true
+	}
+
+	if !validUnderscores(s) {
+		return nil, false
+	}
+	digits := strings.ReplaceAll(s, "_", "")
+
+	for prefix, base := range map[string]int{"0x": 16, "0o": 8, "0b": 2} {
+		if rest, ok := strings.CutPrefix(digits, prefix); ok {
+			n, err := strconv.ParseInt(rest, base, 64)
+			return n, err == nil && !strings.ContainsAny(rest, "+-")
+		}
+	}
+
+	unsigned := strings.TrimLeft(digits, "+-")
+	if len(digits)-len(unsigned) > 1 || unsigned == "" {
+		return nil, false
+	}
+	intPart, _, _ := strings.Cut(strings.ToLower(unsigned), ".")
+	intPart, _, _ = strings.Cut(intPart, "e")
+	if len(intPart) > 1 && intPart[0] == '0' {
+		return nil, false
+	}
+
+	if !strings.ContainsAny(unsigned, ".eE") {
+		n, err := strconv.ParseInt(digits, 10, 64)
+		return n, err == nil
+	}
+	if dot := strings.IndexByte(unsigned, '.'); dot != -1 && (dot == 0 || dot == len(unsigned)-1 || !isDigit(unsigned[dot-1]) || !isDigit(unsigned[dot+1])) {
+		return nil, false
+	}
+	f, err := strconv.ParseFloat(digits, 64)
+	return f, err == nil
+}
+
+// validUnderscores checks every underscore sits between two digits.
+func validUnderscores(s string) bool {
+	for i := range len(s) {
+		if s[i] == '_' && (i == 0 || i == len(s)-1 || !isHexDigit(s[i-1]) || !isHexDigit(s[i+1])) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
@@ -0,0 +1,41 @@
+package betoml
+
+import (
+	"io"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be/internal/tree"
+)
+
+var format = tree.Format{
+	Name: "TOML",
+	Map:  "a table",
+	List: "an array",
+	Tag:  "toml",
+	Parse: func(r io.Reader) (any, error) {
+		return parse(r)
+	},
+}
+
+// Parsed decodes the TOML into T and runs the matcher on it. Struct fields are matched by
+// their toml tag or, without one, case-insensitively by name, and time.Duration fields
+// accept strings like "5s". Dates and times decode into time.Time.
+func Parsed[T any](matcher expect.Matcher[T]) expect.Matcher[io.Reader] {
+	return tree.Parsed(format, matcher)
+}
+
+// Path decodes the value at the path into T and runs the matcher on it. Paths use the same
+// syntax as bejson.Path: the root $, .key and ['key'] member access, and [n] indexes, i.e.
+// $.servers[0].host or $.labels['app.kubernetes.io/name'].
+//
+//	betoml.Path("$.servers[0].port", be.Eq(8080))
+func Path[T any](path string, matcher expect.Matcher[T]) expect.Matcher[io.Reader] {
+	return tree.AtPath(format, path, matcher)
+}
+
+// Equivalent checks the TOML holds the same data as the expected document. Comments,
+// key order, string styles and whether tables are written inline, with headers or with
+// dotted keys are all ignored.
+func Equivalent(expected string) expect.Matcher[io.Reader] {
+	return tree.Equivalent(format, expected)
+}
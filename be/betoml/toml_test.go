@@ -0,0 +1,246 @@
+package betoml_test
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/befs"
+	"github.com/jsteenb2/expect/be/betoml"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+const config = `# service config
+name = "todos"
+replicas = 3
+released = 2024-05-27T07:32:00Z
+
+[server]
+host = "0.0.0.0"
+port = 8080
+timeout = "5s"
+
+[[servers]]
+host = "a.example.com"
+port = 443
+
+[[servers]]
+host = "b.example.com"
+port = 8443
+
+[labels]
+"app.kubernetes.io/name" = "todos"
+`
+
+type Config struct {
+	Name     string    `toml:"name"`
+	Replicas int       `toml:"replicas"`
+	Released time.Time `toml:"released"`
+	Server   struct {
+		Host    string
+		Port    int
+		Timeout time.Duration
+	} `toml:"server"`
+}
+
+func ExampleParsed() {
+	t := &expect.SpyTB{}
+
+	haveReplicas := func(want int) expect.Matcher[Config] {
+		return func(c Config) expect.MatchResult {
+			return expect.MatchResult{
+				Description: fmt.Sprintf("have %d replicas", want),
+				Matches:     c.Replicas == want,
+				But:         fmt.Sprintf("it had %d", c.Replicas),
+				SubjectName: "the config",
+			}
+		}
+	}
+
+	stubFS := fstest.MapFS{"config.toml": {Data: []byte(config)}}
+	expect.It[fs.FS](t, stubFS).To(befs.FileNamed("config.toml", betoml.Parsed(haveReplicas(3))))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleParsed_fail() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader("name = \"todos\"\nname = \"again\"\n")).To(betoml.Parsed(be.Eq(Config{Name: "todos"})))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected TOML to be parseable into betoml_test.Config, but it could not be parsed: line 2: the key name is already defined]
+}
+
+func ExamplePath() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(config)).To(
+		betoml.Path("$.servers[1].host", be.Eq("b.example.com")),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExamplePath_fail() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(config)).To(betoml.Path("server.port", be.Eq(9090)))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected TOML to have server.port equal to 9090, but it was 8080]
+}
+
+func ExampleEquivalent() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(config)).To(betoml.Equivalent(`
+name = 'todos'
+replicas = 3
+released = 2024-05-27 09:32:00+02:00
+server = { host = "0.0.0.0", port = 8080, timeout = "5s" }
+servers = [
+  { host = "a.example.com", port = 443 },
+  { host = "b.example.com", port = 8443 },
+]
+labels."app.kubernetes.io/name" = "todos"
+`))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleEquivalent_fail() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(config)).To(betoml.Equivalent(strings.Replace(config, "8443", "9443", 1)))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected TOML to be equivalent to the expected TOML, but it differed at $.servers[1].port: it was 8443, expected 9443]
+}
+
+func TestParsed(t *testing.T) {
+	var got Config
+	expect.It[io.Reader](t, strings.NewReader(config)).To(betoml.Parsed(func(c Config) expect.MatchResult {
+		got = c
+		return expect.MatchResult{Matches: true}
+	}))
+
+	expect.It(t, got.Name).To(be.Eq("todos"))
+	expect.It(t, got.Released.Equal(time.Date(2024, 5, 27, 7, 32, 0, 0, time.UTC))).To(be.Eq(true))
+	expect.It(t, got.Server.Timeout).To(be.Eq(5 * time.Second))
+	expect.It(t, got.Server.Port).To(be.Eq(8080))
+
+	for doc, want := range map[string]string{
+		"replicas = 3.5":     "it could not be parsed: cannot decode a float into int at $.replicas",
+		"released = 'today'": `it could not be parsed: cannot decode a string into time.Time at $.released: parsing time "today" as "2006-01-02T15:04:05Z07:00": cannot parse "today" as "2006"`,
+		"server = [1]":       "it could not be parsed: cannot decode an array into struct { Host string; Port int; Timeout time.Duration } at $.server",
+		"name = ":            "it could not be parsed: line 1: expected a value",
+	} {
+		spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(doc), betoml.Parsed(be.Eq(Config{})), want)
+	}
+}
+
+func TestPath(t *testing.T) {
+	expect.It[io.Reader](t, strings.NewReader(config)).To(betoml.Path("$.labels['app.kubernetes.io/name']", be.Eq("todos")))
+	expect.It[io.Reader](t, strings.NewReader(config)).To(betoml.Path(`labels["app.kubernetes.io/name"]`, be.Eq("todos")))
+	expect.It[io.Reader](t, strings.NewReader(config)).To(betoml.Path("server.timeout", be.Eq(5*time.Second)))
+
+	for path, want := range map[string]string{
+		"server.tls":      "expected TOML to have server.tls, but it had nothing at $.server.tls: $.server had no key tls",
+		"servers[2].host": "expected TOML to have servers[2].host, but it had nothing at $.servers[2].host: $.servers had 2 items",
+		"name.first":      `expected TOML to have name.first, but it had nothing at $.name.first: $.name was "todos", not a table`,
+		"server.port":     `expected TOML to have server.port, but cannot decode an integer into string at $.server.port`,
+	} {
+		spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(config), betoml.Path(path, be.Eq("localhost")), want)
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+		want     string
+	}{
+		{name: "value", got: "a = 1", expected: "a = 2", want: "it differed at $.a: it was 1, expected 2"},
+		{name: "type", got: "a = '1'", expected: "a = 1", want: `it differed at $.a: it was "1", expected 1`},
+		{name: "missing key", got: "[t]\na = 1", expected: "t = {a = 1, b = 2}", want: "it differed at $.t: key b was missing"},
+		{name: "extra key", got: "a = 1\nb = 2", expected: "a = 1", want: "key b was not expected"},
+		{name: "length", got: "a = [1, 2, 3]", expected: "a = [1, 2]", want: "it differed at $.a: it had 3 items, expected 2"},
+		{name: "kind", got: "a = [1]", expected: "a = {b = 1}", want: "it differed at $.a: it was an array, expected a table"},
+		{name: "date", got: "a = 2024-01-02", expected: "a = 2024-01-03", want: "it differed at $.a: it was 2024-01-02T00:00:00Z, expected 2024-01-03T00:00:00Z"},
+		{name: "invalid expected", got: "a = 1", expected: "a = [", want: "the expected TOML could not be parsed: line 1: expected a value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(tt.got), betoml.Equivalent(tt.expected),
+				"expected TOML to be equivalent to the expected TOML, but "+tt.want,
+			)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		toml     string
+		expected string
+	}{
+		{
+			name:     "strings",
+			toml:     `basic = "tab\there \"q\" \u00e9 \U0001F600"` + "\nliteral = 'C:\\path\\x'\nml = \"\"\"\nline one\nline \\\n    two\"\"\"\nmll = '''\nraw \\n '' '''\nquotes = \"\"\"\"x\"\"\"\"\"\n",
+			expected: `basic = "tab\there \"q\" é 😀"` + "\nliteral = \"C:\\\\path\\\\x\"\nml = \"line one\\nline two\"\nmll = \"raw \\\\n '' \"\nquotes = '\"x\"\"'\n",
+		},
+		{
+			name:     "numbers",
+			toml:     "i = +1_000\nneg = -17\nhex = 0xDEAD_beef\noct = 0o755\nbin = 0b1101\nf = 6.626e-34\ng = -0.01\nh = 1e3\ninf = -inf\n",
+			expected: "i = 1000\nneg = -17\nhex = 3735928559\noct = 493\nbin = 13\nf = 6.626E-34\ng = -1e-2\nh = 1000.0\ninf = -inf\n",
+		},
+		{
+			name:     "dates and times",
+			toml:     "odt = 1979-05-27T07:32:00.999-07:00\nldt = 1979-05-27t07:32:00\nld = 1979-05-27\nlt = 07:32:00.5\nspace = 1979-05-27 07:32:00Z\n",
+			expected: "odt = 1979-05-27T14:32:00.999Z\nldt = 1979-05-27T07:32:00Z\nld = 1979-05-27T00:00:00Z\nlt = 07:32:00.500\nspace = 1979-05-27T07:32:00Z\n",
+		},
+		{
+			name:     "arrays",
+			toml:     "a = [\n  1, # one\n  'two',\n  [3.0, true],\n  { four = 4 },\n]\nempty = []\n",
+			expected: "a = [1, \"two\", [3, true], {four = 4}]\nempty = []\n",
+		},
+		{
+			name:     "tables",
+			toml:     "top = 1\n[a.b.c]\nd = 1\n[a]\ne = 2\n[a.b]\nf.g = 3\n\"quoted key\".'x' = 4\n[[arr]]\nn = 1\n[arr.sub]\ns = 1\n[[arr]]\nn = 2\n",
+			expected: "top = 1\na = { e = 2, b = { c = { d = 1 }, f = { g = 3 }, \"quoted key\" = { x = 4 } } }\narr = [{ n = 1, sub = { s = 1 } }, { n = 2 }]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect.It[io.Reader](t, strings.NewReader(tt.toml)).To(betoml.Equivalent(tt.expected))
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for doc, want := range map[string]string{
+			"a = 1\na = 2":       "line 2: the key a is already defined",
+			"[a]\n[a]":           "line 2: the table a is already defined",
+			"a = {b = 1}\n[a.c]": "line 2: the inline table a cannot be extended",
+			"a.b = 1\n[a]":       "line 2: the table a is already defined",
+			"[a]\nb = 1\n[a.b]":  "line 3: the key a.b is already defined",
+			"a = [1]\n[[a]]":     "line 2: cannot append to the static array a",
+			"a = \"open":         "line 1: the string is not closed",
+			"a = \"\\q\"":        `line 1: invalid escape \q`,
+			"a = 01":             `line 1: "01" is not a valid value`,
+			"a = 1__0":           `line 1: "1__0" is not a valid value`,
+			"a = .5":             `line 1: ".5" is not a valid value`,
+			"a = 1 b = 2":        `line 1: expected the end of the line, found "b = 2"`,
+			"a = [1 2]":          `line 1: expected , or ] in the array, found "2]"`,
+			"[a":                 "line 1: expected ] after the table name",
+			"= 1":                `line 1: expected a key, found "= 1"`,
+			"a 1":                "line 1: expected = after the key a",
+		} {
+			spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(doc), betoml.Equivalent("a = 1"), "it could not be parsed: "+want)
+		}
+	})
+}
@@ -0,0 +1,419 @@
+package bews
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Close codes defined by RFC 6455.
+const (
+	CloseNormal         = 1000
+	CloseGoingAway      = 1001
+	CloseProtocolError  = 1002
+	CloseUnsupported    = 1003
+	CloseNoStatus       = 1005
+	CloseAbnormal       = 1006
+	CloseInvalidPayload = 1007
+	ClosePolicy         = 1008
+	CloseTooLarge       = 1009
+	CloseInternalError  = 1011
+)
+
+// MessageType is the type of a WebSocket data message.
+type MessageType int
+
+const (
+	// TextMessage is a UTF-8 text message.
+	TextMessage MessageType = opText
+	// BinaryMessage is a binary message.
+	BinaryMessage MessageType = opBinary
+)
+
+func (m MessageType) String() string {
+	switch m {
+	case TextMessage:
+		return "text"
+	case BinaryMessage:
+		return "binary"
+	default:
+		return fmt.Sprintf("MessageType(%d)", int(m))
+	}
+}
+
+// Message is a complete, reassembled data message.
+type Message struct {
+	Type MessageType
+	Data []byte
+}
+
+func (m Message) String() string {
+	if m.Type == TextMessage {
+		return fmt.Sprintf("text message %q", m.Data)
+	}
+	return fmt.Sprintf("binary message [% x]", m.Data)
+}
+
+// CloseError is returned by Next once the peer has closed the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("the connection was closed with code %d", e.Code)
+	}
+	return fmt.Sprintf("the connection was closed with code %d %q", e.Code, e.Reason)
+}
+
+// handshakeGUID is the fixed GUID used to derive Sec-WebSocket-Accept.
+const handshakeGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Conn is a minimal RFC 6455 WebSocket connection for tests. Messages are read in the
+// background as they arrive, pings are answered and the closing handshake is
+// completed automatically. Use it with the Receive, ReceiveJSON and Closed matchers.
+//
+//	conn, err := bews.DialServer(svr, "/ws", nil)
+//	expect.NoError(t, err)
+//	defer conn.Close()
+//
+//	expect.NoError(t, conn.SendText("hello"))
+//	expect.It(t, conn).To(bews.Receive(time.Second, beio.ContainingString("hello")))
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool
+
+	writeMu   sync.Mutex
+	closeSent atomic.Bool
+
+	messages  chan Message
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// err is set before messages is closed.
+	err error
+}
+
+// Dial opens a WebSocket connection to the URL, which may use the ws, wss, http or
+// https scheme. The header is sent with the opening handshake.
+func Dial(rawURL string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return dial(u, header, nil)
+}
+
+// DialServer opens a WebSocket connection to the path on the test server, trusting
+// its certificate when it was started with TLS.
+func DialServer(svr *httptest.Server, path string, header http.Header) (*Conn, error) {
+	u, err := url.Parse(svr.URL + path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tlsConfig *tls.Config
+	if svr.TLS != nil {
+		if tr, ok := svr.Client().Transport.(*http.Transport); ok {
+			tlsConfig = tr.TLSClientConfig.Clone()
+		}
+	}
+	return dial(u, header, tlsConfig)
+}
+
+func dial(u *url.URL, header http.Header, tlsConfig *tls.Config) (*Conn, error) {
+	secure := false
+	switch u.Scheme {
+	case "ws", "http":
+	case "wss", "https":
+		secure = true
+	default:
+		return nil, fmt.Errorf("the scheme %q is not supported", u.Scheme)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		port := "80"
+		if secure {
+			port = "443"
+		}
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if secure {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = u.Hostname()
+		}
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := handshake(conn, u, header)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func handshake(conn net.Conn, u *url.URL, header http.Header) (*Conn, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header.Clone(),
+		Host:       u.Host,
+	}
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	if err := req.Write(conn); err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		res.Body.Close()
+		return nil, fmt.Errorf("the handshake failed with status %s", res.Status)
+	}
+	if got, want := res.Header.Get("Sec-WebSocket-Accept"), acceptKey(key); got != want {
+		res.Body.Close()
+		return nil, fmt.Errorf("the handshake failed, Sec-WebSocket-Accept was %q not %q", got, want)
+	}
+	conn.SetDeadline(time.Time{})
+
+	return newConn(conn, br, true), nil
+}
+
+// Upgrade completes the server side of the opening handshake, which makes it easy to
+// write a WebSocket handler for a test server. A failed handshake is answered with a
+// 400 Bad Request.
+func Upgrade(w http.ResponseWriter, req *http.Request) (*Conn, error) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if req.Method != http.MethodGet ||
+		!headerContains(req.Header, "Connection", "upgrade") ||
+		!headerContains(req.Header, "Upgrade", "websocket") ||
+		req.Header.Get("Sec-WebSocket-Version") != "13" ||
+		key == "" {
+		http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+		return nil, errors.New("the request is not a websocket handshake")
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("the response writer cannot be hijacked")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	res := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(res)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return newConn(conn, rw.Reader, false), nil
+}
+
+func newConn(conn net.Conn, br *bufio.Reader, client bool) *Conn {
+	c := &Conn{
+		conn:     conn,
+		br:       br,
+		client:   client,
+		messages: make(chan Message),
+		done:     make(chan struct{}),
+	}
+	go c.read()
+	return c
+}
+
+// SendText sends a text message.
+func (c *Conn) SendText(text string) error {
+	return c.write(opText, []byte(text))
+}
+
+// SendBinary sends a binary message.
+func (c *Conn) SendBinary(data []byte) error {
+	return c.write(opBinary, data)
+}
+
+// SendJSON sends v encoded as JSON in a text message.
+func (c *Conn) SendJSON(v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.write(opText, b)
+}
+
+// SendClose starts the closing handshake with the code and reason. Messages already
+// in flight can still be received, and Closed reports the code the peer replies with.
+func (c *Conn) SendClose(code int, reason string) error {
+	if !c.closeSent.CompareAndSwap(false, true) {
+		return nil
+	}
+	return c.write(opClose, closePayload(code, reason))
+}
+
+// Next waits up to timeout for the next message. Once the peer has closed the
+// connection, it returns a *CloseError.
+func (c *Conn) Next(timeout time.Duration) (Message, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case msg, ok := <-c.messages:
+		if !ok {
+			return Message{}, c.err
+		}
+		return msg, nil
+	case <-timer.C:
+		return Message{}, fmt.Errorf("timed out after %s waiting for a message", timeout)
+	}
+}
+
+// Close closes the underlying connection without a closing handshake.
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return c.conn.Close()
+}
+
+func (c *Conn) String() string {
+	return subjectName
+}
+
+func (c *Conn) write(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return writeFrame(c.conn, opcode, payload, c.client)
+}
+
+func (c *Conn) read() {
+	defer close(c.messages)
+	defer c.conn.Close()
+
+	var (
+		msgType MessageType
+		data    []byte
+	)
+	for {
+		f, err := readFrame(c.br)
+		if err != nil {
+			c.err = &CloseError{Code: CloseAbnormal, Reason: err.Error()}
+			return
+		}
+
+		switch f.opcode {
+		case opPing:
+			c.write(opPong, f.payload)
+			continue
+		case opPong:
+			continue
+		case opClose:
+			code, reason := parseClosePayload(f.payload)
+			c.SendClose(code, "")
+			c.err = &CloseError{Code: code, Reason: reason}
+			return
+		case opText, opBinary:
+			if msgType != 0 {
+				c.err = c.protocolError("a new message started before the last one finished")
+				return
+			}
+			msgType, data = MessageType(f.opcode), f.payload
+		case opContinuation:
+			if msgType == 0 {
+				c.err = c.protocolError("a continuation frame had no message to continue")
+				return
+			}
+			if len(data)+len(f.payload) > maxMessageSize {
+				c.err = c.protocolError("the message is larger than the size limit")
+				return
+			}
+			data = append(data, f.payload...)
+		default:
+			c.err = c.protocolError(fmt.Sprintf("the opcode %#x is not supported", f.opcode))
+			return
+		}
+
+		if !f.fin {
+			continue
+		}
+		select {
+		case c.messages <- Message{Type: msgType, Data: data}:
+		case <-c.done:
+			c.err = &CloseError{Code: CloseAbnormal, Reason: "the connection was closed locally"}
+			return
+		}
+		msgType, data = 0, nil
+	}
+}
+
+func (c *Conn) protocolError(reason string) error {
+	c.SendClose(CloseProtocolError, reason)
+	return &CloseError{Code: CloseProtocolError, Reason: reason}
+}
+
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + handshakeGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+func headerContains(header http.Header, name, token string) bool {
+	for _, v := range header.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
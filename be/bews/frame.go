@@ -0,0 +1,144 @@
+package bews
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// maxMessageSize is the largest message accepted from the peer.
+const maxMessageSize = 32 << 20
+
+type frame struct {
+	fin     bool
+	opcode  byte
+	payload []byte
+}
+
+func (f frame) isControl() bool {
+	return f.opcode&0x8 != 0
+}
+
+func readFrame(br *bufio.Reader) (frame, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(br, head[:]); err != nil {
+		return frame{}, err
+	}
+
+	f := frame{
+		fin:    head[0]&0x80 != 0,
+		opcode: head[0] & 0x0f,
+	}
+	if head[0]&0x70 != 0 {
+		return frame{}, errors.New("a frame had reserved bits set")
+	}
+
+	masked := head[1]&0x80 != 0
+	size := uint64(head[1] & 0x7f)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(br, ext[:]); err != nil {
+			return frame{}, err
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(br, ext[:]); err != nil {
+			return frame{}, err
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	if f.isControl() && (size > 125 || !f.fin) {
+		return frame{}, errors.New("a control frame was fragmented or too large")
+	}
+	if size > maxMessageSize {
+		return frame{}, fmt.Errorf("a frame of %d bytes is larger than the %d byte limit", size, maxMessageSize)
+	}
+
+	var key [4]byte
+	if masked {
+		if _, err := io.ReadFull(br, key[:]); err != nil {
+			return frame{}, err
+		}
+	}
+
+	f.payload = make([]byte, size)
+	if _, err := io.ReadFull(br, f.payload); err != nil {
+		return frame{}, err
+	}
+	if masked {
+		maskBytes(key, f.payload)
+	}
+	return f, nil
+}
+
+// writeFrame writes a single final frame. Frames sent by a client must be masked.
+func writeFrame(w io.Writer, opcode byte, payload []byte, mask bool) error {
+	buf := make([]byte, 0, 14+len(payload))
+	buf = append(buf, 0x80|opcode)
+
+	var maskBit byte
+	if mask {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xffff:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+
+	if !mask {
+		buf = append(buf, payload...)
+		_, err := w.Write(buf)
+		return err
+	}
+
+	var key [4]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return err
+	}
+	buf = append(buf, key[:]...)
+	start := len(buf)
+	buf = append(buf, payload...)
+	maskBytes(key, buf[start:])
+	_, err := w.Write(buf)
+	return err
+}
+
+func maskBytes(key [4]byte, b []byte) {
+	for i := range b {
+		b[i] ^= key[i%4]
+	}
+}
+
+func closePayload(code int, reason string) []byte {
+	if code == CloseNoStatus {
+		return nil
+	}
+	return append(binary.BigEndian.AppendUint16(nil, uint16(code)), reason...)
+}
+
+func parseClosePayload(payload []byte) (int, string) {
+	if len(payload) < 2 {
+		return CloseNoStatus, ""
+	}
+	return int(binary.BigEndian.Uint16(payload)), string(payload[2:])
+}
@@ -0,0 +1,91 @@
+package bews
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/bejson"
+)
+
+const subjectName = "the websocket"
+
+// Receive waits up to timeout for the next message and runs the matcher on its
+// payload. Each Receive consumes one message, so successive matchers assert on
+// successive messages.
+func Receive(timeout time.Duration, matcher expect.Matcher[[]byte]) expect.Matcher[*Conn] {
+	return func(c *Conn) expect.MatchResult {
+		msg, err := c.Next(timeout)
+		if err != nil {
+			return expect.MatchResult{
+				Description: "receive a message",
+				Matches:     false,
+				But:         receiveFailure(err),
+				SubjectName: subjectName,
+			}
+		}
+
+		result := matcher(msg.Data)
+		result = result.WithDescription("receive a message " + be.Complement(result))
+		result.SubjectName = subjectName
+		if !result.Matches && result.But == "" {
+			result.But = fmt.Sprintf("it received a %s", msg)
+		}
+		return result
+	}
+}
+
+// ReceiveJSON waits up to timeout for the next message, parses its payload into T with
+// bejson.Parsed and runs the matcher on it.
+func ReceiveJSON[T any](timeout time.Duration, matcher expect.Matcher[T]) expect.Matcher[*Conn] {
+	parsed := bejson.Parsed(matcher)
+	return Receive(timeout, func(data []byte) expect.MatchResult {
+		result := parsed(bytes.NewReader(data))
+		if !result.Matches && result.But == "" {
+			result.But = fmt.Sprintf("it was %s", data)
+		}
+		return result
+	})
+}
+
+// Closed waits up to timeout for the peer to close the connection and checks it
+// closes with the given close code. Any message received first fails the match.
+func Closed(timeout time.Duration, code int) expect.Matcher[*Conn] {
+	return func(c *Conn) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("be closed with code %d", code),
+			SubjectName: subjectName,
+		}
+
+		msg, err := c.Next(timeout)
+		var closeErr *CloseError
+		switch {
+		case err == nil:
+			result.But = fmt.Sprintf("it received a %s", msg)
+		case errors.As(err, &closeErr):
+			result.Matches = closeErr.Code == code
+			result.But = "it was closed with code " + closeDetail(closeErr)
+		default:
+			result.But = fmt.Sprintf("it was still open after %s", timeout)
+		}
+		return result
+	}
+}
+
+func receiveFailure(err error) string {
+	var closeErr *CloseError
+	if errors.As(err, &closeErr) {
+		return "it was closed with code " + closeDetail(closeErr)
+	}
+	return fmt.Sprintf("it could not be received: %v", err)
+}
+
+func closeDetail(err *CloseError) string {
+	if err.Reason == "" {
+		return fmt.Sprint(err.Code)
+	}
+	return fmt.Sprintf("%d %q", err.Code, err.Reason)
+}
@@ -0,0 +1,277 @@
+package bews_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/beio"
+	"github.com/jsteenb2/expect/be/bews"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func ExampleReceive() {
+	t := &expect.SpyTB{}
+
+	svr := httptest.NewServer(newEchoServer())
+	defer svr.Close()
+
+	conn, err := bews.DialServer(svr, "/echo", nil)
+	expect.NoError(t, err)
+	defer conn.Close()
+
+	expect.NoError(t, conn.SendText("hello"))
+	expect.It(t, conn).To(bews.Receive(time.Second, beio.ContainingString("hello")))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleReceive_fail() {
+	t := &expect.SpyTB{}
+
+	svr := httptest.NewServer(newEchoServer())
+	defer svr.Close()
+
+	conn, err := bews.DialServer(svr, "/echo", nil)
+	expect.NoError(t, err)
+	defer conn.Close()
+
+	expect.NoError(t, conn.SendText("hello"))
+	expect.It(t, conn).To(bews.Receive(time.Second, haveText("goodbye")))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the websocket to receive a message with text "goodbye", but it received a text message "hello"]
+}
+
+func ExampleReceiveJSON() {
+	t := &expect.SpyTB{}
+
+	svr := httptest.NewServer(newEchoServer())
+	defer svr.Close()
+
+	conn, err := bews.DialServer(svr, "/echo", nil)
+	expect.NoError(t, err)
+	defer conn.Close()
+
+	type Todo struct {
+		Name string `json:"name"`
+	}
+
+	expect.NoError(t, conn.SendJSON(Todo{Name: "write tests"}))
+	expect.It(t, conn).To(bews.ReceiveJSON(time.Second, be.Eq(Todo{Name: "write tests"})))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleClosed() {
+	t := &expect.SpyTB{}
+
+	svr := httptest.NewServer(newEchoServer())
+	defer svr.Close()
+
+	conn, err := bews.DialServer(svr, "/echo", nil)
+	expect.NoError(t, err)
+	defer conn.Close()
+
+	expect.NoError(t, conn.SendText("close"))
+	expect.It(t, conn).To(bews.Closed(time.Second, bews.CloseNormal))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected the websocket to be closed with code 1000, but it was closed with code 4000 "bye"]
+}
+
+func TestReceive(t *testing.T) {
+	svr := httptest.NewServer(newEchoServer())
+	defer svr.Close()
+
+	t.Run("binary and large messages", func(t *testing.T) {
+		conn, err := bews.DialServer(svr, "/echo", nil)
+		expect.NoError(t, err)
+		defer conn.Close()
+
+		medium := strings.Repeat("m", 1000)
+		large := strings.Repeat("l", 70000)
+		expect.NoError(t, conn.SendBinary([]byte{0xde, 0xad}))
+		expect.NoError(t, conn.SendText(medium))
+		expect.NoError(t, conn.SendText(large))
+
+		expect.It(t, conn).To(
+			bews.Receive(time.Second, beio.ContainingByte([]byte{0xde, 0xad})),
+			bews.Receive(time.Second, haveText(medium)),
+			bews.Receive(time.Second, haveText(large)),
+		)
+	})
+
+	t.Run("reports the message type", func(t *testing.T) {
+		conn, err := bews.DialServer(svr, "/echo", nil)
+		expect.NoError(t, err)
+		defer conn.Close()
+
+		expect.NoError(t, conn.SendBinary([]byte{0xca, 0xfe}))
+		spytb.VerifyFailingMatcher(t, conn, bews.Receive(time.Second, haveText("cafe")),
+			"expected the websocket to receive a message with text \"cafe\", but it received a binary message [ca fe]",
+		)
+	})
+
+	t.Run("sends the handshake headers", func(t *testing.T) {
+		conn, err := bews.DialServer(svr, "/greet", http.Header{"X-Name": {"Pepper"}})
+		expect.NoError(t, err)
+		defer conn.Close()
+
+		expect.It(t, conn).To(bews.Receive(time.Second, haveText("hello Pepper")))
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		conn, err := bews.DialServer(svr, "/echo", nil)
+		expect.NoError(t, err)
+		defer conn.Close()
+
+		spytb.VerifyFailingMatcher(t, conn, bews.Receive(10*time.Millisecond, haveText("hello")),
+			"expected the websocket to receive a message, but it could not be received: timed out after 10ms waiting for a message",
+		)
+	})
+
+	t.Run("reports a closed connection", func(t *testing.T) {
+		conn, err := bews.DialServer(svr, "/echo", nil)
+		expect.NoError(t, err)
+		defer conn.Close()
+
+		expect.NoError(t, conn.SendText("close"))
+		spytb.VerifyFailingMatcher(t, conn, bews.Receive(time.Second, haveText("hello")),
+			`expected the websocket to receive a message, but it was closed with code 4000 "bye"`,
+		)
+	})
+
+	t.Run("over TLS", func(t *testing.T) {
+		svr := httptest.NewTLSServer(newEchoServer())
+		defer svr.Close()
+
+		conn, err := bews.DialServer(svr, "/echo", nil)
+		expect.NoError(t, err)
+		defer conn.Close()
+
+		expect.NoError(t, conn.SendText("secure"))
+		expect.It(t, conn).To(bews.Receive(time.Second, haveText("secure")))
+	})
+
+	t.Run("fails the handshake for a plain handler", func(t *testing.T) {
+		_, err := bews.DialServer(svr, "/plain", nil)
+		expect.It(t, err.Error()).To(be.Eq("the handshake failed with status 404 Not Found"))
+
+		wrongAccept := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Upgrade", "websocket")
+			w.Header().Set("Connection", "Upgrade")
+			w.Header().Set("Sec-WebSocket-Accept", "nope")
+			w.WriteHeader(http.StatusSwitchingProtocols)
+		}))
+		defer wrongAccept.Close()
+		_, err = bews.DialServer(wrongAccept, "/", nil)
+		expect.It(t, err.Error()).To(be.Substring(`the handshake failed, Sec-WebSocket-Accept was "nope" not`))
+
+		_, err = bews.Dial(strings.Replace(svr.URL, "http", "ftp", 1), nil)
+		expect.It(t, err.Error()).To(be.Eq(`the scheme "ftp" is not supported`))
+	})
+}
+
+func TestReceiveJSON(t *testing.T) {
+	svr := httptest.NewServer(newEchoServer())
+	defer svr.Close()
+
+	conn, err := bews.DialServer(svr, "/echo", nil)
+	expect.NoError(t, err)
+	defer conn.Close()
+
+	expect.NoError(t, conn.SendText("not json"))
+	spytb.VerifyFailingMatcher(t, conn, bews.ReceiveJSON(time.Second, be.Eq(42)),
+		"expected the websocket to receive a message parseable into int, but it could not be parsed: invalid character 'o' in literal null (expecting 'u')",
+	)
+}
+
+func TestClosed(t *testing.T) {
+	svr := httptest.NewServer(newEchoServer())
+	defer svr.Close()
+
+	t.Run("completes a closing handshake started by the client", func(t *testing.T) {
+		conn, err := bews.DialServer(svr, "/echo", nil)
+		expect.NoError(t, err)
+		defer conn.Close()
+
+		expect.NoError(t, conn.SendText("hello"))
+		expect.It(t, conn).To(bews.Receive(time.Second, haveText("hello")))
+
+		expect.NoError(t, conn.SendClose(bews.CloseGoingAway, "done"))
+		expect.It(t, conn).To(bews.Closed(time.Second, bews.CloseGoingAway))
+	})
+
+	t.Run("fails when a message arrives first", func(t *testing.T) {
+		conn, err := bews.DialServer(svr, "/echo", nil)
+		expect.NoError(t, err)
+		defer conn.Close()
+
+		expect.NoError(t, conn.SendText("hello"))
+		spytb.VerifyFailingMatcher(t, conn, bews.Closed(time.Second, bews.CloseNormal),
+			`expected the websocket to be closed with code 1000, but it received a text message "hello"`,
+		)
+	})
+
+	t.Run("fails when the connection stays open", func(t *testing.T) {
+		conn, err := bews.DialServer(svr, "/echo", nil)
+		expect.NoError(t, err)
+		defer conn.Close()
+
+		spytb.VerifyFailingMatcher(t, conn, bews.Closed(10*time.Millisecond, bews.CloseNormal),
+			"expected the websocket to be closed with code 1000, but it was still open after 10ms",
+		)
+	})
+}
+
+func haveText(text string) expect.Matcher[[]byte] {
+	return func(data []byte) expect.MatchResult {
+		return expect.MatchResult{
+			Description: fmt.Sprintf("with text %q", text),
+			Matches:     string(data) == text,
+		}
+	}
+}
+
+// newEchoServer echoes every message on /echo, closing with 4000 when it receives
+// "close", and greets the X-Name from the handshake on /greet.
+func newEchoServer() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := bews.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			msg, err := conn.Next(time.Minute)
+			if err != nil {
+				return
+			}
+			switch {
+			case msg.Type == bews.TextMessage && string(msg.Data) == "close":
+				conn.SendClose(4000, "bye")
+			case msg.Type == bews.TextMessage:
+				conn.SendText(string(msg.Data))
+			default:
+				conn.SendBinary(msg.Data)
+			}
+		}
+	})
+	mux.HandleFunc("/greet", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := bews.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.SendText("hello " + r.Header.Get("X-Name"))
+		conn.Next(time.Minute)
+	})
+	return mux
+}
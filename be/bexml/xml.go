@@ -0,0 +1,291 @@
+package bexml
+
+import (
+	"cmp"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jsteenb2/expect"
+)
+
+const subjectName = "XML"
+
+// Parsed decodes the XML into T with encoding/xml and runs the matcher on it.
+func Parsed[T any](matcher expect.Matcher[T]) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		var thing T
+		err := xml.NewDecoder(rdr).Decode(&thing)
+		if err != nil {
+			return expect.MatchResult{
+				Description: fmt.Sprintf("be parseable into %T", thing),
+				SubjectName: subjectName,
+				Matches:     false,
+				But:         fmt.Sprintf("it could not be parsed: %v", err),
+			}
+		}
+		return matcher(thing)
+	}
+}
+
+// WellFormed checks the reader holds a single, well-formed XML document.
+func WellFormed(rdr io.Reader) expect.MatchResult {
+	_, err := parseDocument(rdr)
+	return expect.MatchResult{
+		Description: "be well-formed",
+		Matches:     err == nil,
+		But:         fmt.Sprintf("it was not: %v", err),
+		SubjectName: subjectName,
+	}
+}
+
+// Equivalent checks the XML is equivalent to the expected document. Comments,
+// processing instructions, whitespace between elements and around text, the order of
+// attributes and the prefixes bound to namespaces are all ignored.
+func Equivalent(expected string) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: "be equivalent to the expected XML",
+			Matches:     false,
+			SubjectName: subjectName,
+		}
+
+		want, err := parseDocument(strings.NewReader(expected))
+		if err != nil {
+			result.But = fmt.Sprintf("the expected XML could not be parsed: %v", err)
+			return result
+		}
+		got, err := parseDocument(rdr)
+		if err != nil {
+			result.But = fmt.Sprintf("it could not be parsed: %v", err)
+			return result
+		}
+
+		gotRoot, wantRoot := got.root(), want.root()
+		if diff := difference(gotRoot, wantRoot, "/"+displayName(wantRoot)); diff != "" {
+			result.But = diff
+			return result
+		}
+		result.Matches = true
+		return result
+	}
+}
+
+type nodeKind int
+
+const (
+	rootNode nodeKind = iota
+	elementNode
+	attributeNode
+	textNode
+)
+
+// node is a node of a parsed XML document. Element and attribute names carry the
+// namespace URI in Space rather than the prefix used in the document.
+type node struct {
+	kind     nodeKind
+	name     xml.Name
+	value    string
+	attrs    []*node
+	children []*node
+	parent   *node
+	// order is the position of the node in document order.
+	order int
+	// prefixes maps the namespace prefixes declared anywhere in the document to their URI,
+	// it is only set on the root.
+	prefixes map[string]string
+}
+
+// root returns the document element.
+func (n *node) root() *node {
+	for _, c := range n.children {
+		if c.kind == elementNode {
+			return c
+		}
+	}
+	return nil
+}
+
+// stringValue is the XPath string-value of the node.
+func (n *node) stringValue() string {
+	if n.kind == attributeNode || n.kind == textNode {
+		return n.value
+	}
+	var sb strings.Builder
+	var walk func(*node)
+	walk = func(n *node) {
+		for _, c := range n.children {
+			if c.kind == textNode {
+				sb.WriteString(c.value)
+			} else {
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+func parseDocument(rdr io.Reader) (*node, error) {
+	doc := &node{kind: rootNode, prefixes: make(map[string]string)}
+	dec := xml.NewDecoder(rdr)
+
+	var (
+		stack = []*node{doc}
+		order = 1
+	)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parent := stack[len(stack)-1]
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if parent == doc && doc.root() != nil {
+				return nil, errors.New("the document has more than one root element")
+			}
+			el := &node{kind: elementNode, name: tok.Name, parent: parent, order: order}
+			order++
+			for _, a := range tok.Attr {
+				if a.Name.Space == "xmlns" {
+					if _, ok := doc.prefixes[a.Name.Local]; !ok {
+						doc.prefixes[a.Name.Local] = a.Value
+					}
+					continue
+				}
+				if a.Name.Space == "" && a.Name.Local == "xmlns" {
+					continue
+				}
+				el.attrs = append(el.attrs, &node{kind: attributeNode, name: a.Name, value: a.Value, parent: el, order: order})
+				order++
+			}
+			parent.children = append(parent.children, el)
+			stack = append(stack, el)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if parent == doc {
+				if strings.TrimSpace(string(tok)) != "" {
+					return nil, errors.New("the document has text outside of the root element")
+				}
+				continue
+			}
+			if last := len(parent.children) - 1; last >= 0 && parent.children[last].kind == textNode {
+				parent.children[last].value += string(tok)
+				continue
+			}
+			parent.children = append(parent.children, &node{kind: textNode, value: string(tok), parent: parent, order: order})
+			order++
+		}
+	}
+
+	if doc.root() == nil {
+		return nil, errors.New("the document has no root element")
+	}
+	return doc, nil
+}
+
+// difference describes the first difference between the elements at path, or returns ""
+// when they are equivalent.
+func difference(got, want *node, path string) string {
+	if got.name != want.name {
+		return fmt.Sprintf("it differed at %s: the element was %s", path, displayName(got))
+	}
+
+	gotAttrs, wantAttrs := sortedAttrs(got), sortedAttrs(want)
+	for _, wa := range wantAttrs {
+		i := slices.IndexFunc(gotAttrs, func(a *node) bool { return a.name == wa.name })
+		if i == -1 {
+			return fmt.Sprintf("it differed at %s: attribute %s was missing", path, displayName(wa))
+		}
+		if gotAttrs[i].value != wa.value {
+			return fmt.Sprintf("it differed at %s: attribute %s was %q, expected %q", path, displayName(wa), gotAttrs[i].value, wa.value)
+		}
+	}
+	for _, ga := range gotAttrs {
+		if !slices.ContainsFunc(wantAttrs, func(a *node) bool { return a.name == ga.name }) {
+			return fmt.Sprintf("it differed at %s: attribute %s was not expected", path, displayName(ga))
+		}
+	}
+
+	gotChildren, wantChildren := significantChildren(got), significantChildren(want)
+	for i := range max(len(gotChildren), len(wantChildren)) {
+		if i >= len(gotChildren) {
+			return fmt.Sprintf("it differed at %s: %s was missing", path, describeNode(wantChildren[i]))
+		}
+		if i >= len(wantChildren) {
+			return fmt.Sprintf("it differed at %s: %s was not expected", path, describeNode(gotChildren[i]))
+		}
+
+		g, w := gotChildren[i], wantChildren[i]
+		switch {
+		case g.kind != w.kind:
+			return fmt.Sprintf("it differed at %s: found %s, expected %s", path, describeNode(g), describeNode(w))
+		case w.kind == textNode:
+			if strings.TrimSpace(g.value) != strings.TrimSpace(w.value) {
+				return fmt.Sprintf("it differed at %s: the text was %q, expected %q", path, strings.TrimSpace(g.value), strings.TrimSpace(w.value))
+			}
+		default:
+			if diff := difference(g, w, path+"/"+displayName(w)+indexSuffix(w)); diff != "" {
+				return diff
+			}
+		}
+	}
+	return ""
+}
+
+func sortedAttrs(n *node) []*node {
+	return slices.SortedFunc(slices.Values(n.attrs), func(a, b *node) int {
+		return cmp.Or(cmp.Compare(a.name.Space, b.name.Space), cmp.Compare(a.name.Local, b.name.Local))
+	})
+}
+
+// significantChildren drops the whitespace-only text between elements.
+func significantChildren(n *node) []*node {
+	var out []*node
+	for _, c := range n.children {
+		if c.kind == textNode && strings.TrimSpace(c.value) == "" {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// indexSuffix returns the [n] position of an element among its same-named siblings
+// when there is more than one of them.
+func indexSuffix(n *node) string {
+	var same []*node
+	for _, s := range n.parent.children {
+		if s.kind == elementNode && s.name == n.name {
+			same = append(same, s)
+		}
+	}
+	if len(same) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("[%d]", slices.Index(same, n)+1)
+}
+
+// displayName renders the name with the namespace URI in braces, i.e. {urn:x}item.
+func displayName(n *node) string {
+	if n.name.Space == "" {
+		return n.name.Local
+	}
+	return "{" + n.name.Space + "}" + n.name.Local
+}
+
+func describeNode(n *node) string {
+	if n.kind == textNode {
+		return fmt.Sprintf("text %q", strings.TrimSpace(n.value))
+	}
+	return "element " + displayName(n)
+}
@@ -0,0 +1,239 @@
+package bexml_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/behttp"
+	"github.com/jsteenb2/expect/be/bexml"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+const feed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<!-- a comment -->
+	<channel>
+		<title>Todos</title>
+		<item id="1">
+			<title>Write tests</title>
+			<dc:creator>berg</dc:creator>
+			<price>2.5</price>
+		</item>
+		<item id="2" done="true">
+			<title>Ship it</title>
+			<dc:creator>pepper</dc:creator>
+			<price>4</price>
+		</item>
+	</channel>
+</rss>`
+
+func ExampleParsed() {
+	t := &expect.SpyTB{}
+
+	type Item struct {
+		ID    int    `xml:"id,attr"`
+		Title string `xml:"title"`
+	}
+	type RSS struct {
+		Items []Item `xml:"channel>item"`
+	}
+
+	haveItems := func(want ...Item) expect.Matcher[RSS] {
+		return func(rss RSS) expect.MatchResult {
+			return expect.MatchResult{
+				Description: fmt.Sprintf("have items %v", want),
+				Matches:     slices.Equal(rss.Items, want),
+				But:         fmt.Sprintf("it had %v", rss.Items),
+				SubjectName: "the feed",
+			}
+		}
+	}
+
+	expect.It[io.Reader](t, strings.NewReader(feed)).To(bexml.Parsed(haveItems(
+		Item{ID: 1, Title: "Write tests"},
+		Item{ID: 2, Title: "Ship it"},
+	)))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleParsed_fail() {
+	t := &expect.SpyTB{}
+
+	type Person struct {
+		Name string `xml:"name"`
+	}
+
+	expect.It[io.Reader](t, strings.NewReader(`<person><name>Pepper</person>`)).To(bexml.Parsed(be.Eq(Person{Name: "Pepper"})))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected XML to be parseable into bexml_test.Person, but it could not be parsed: XML syntax error on line 1: element <name> closed by </person>]
+}
+
+func ExampleXPath() {
+	t := &expect.SpyTB{}
+
+	res := behttp.Get("/feed").Do(newFeedServer())
+	expect.It(t, res).To(
+		behttp.RespBody(bexml.XPath("/rss/channel/title", be.Eq("Todos"))),
+		behttp.RespBody(bexml.XPath("//item[@id='2']/title", be.Eq("Ship it"))),
+		behttp.RespBody(bexml.XPath("count(//item)", be.Eq("2"))),
+		behttp.RespBody(bexml.XPath("//item[last()]/dc:creator", be.Eq("pepper"))),
+	)
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleXPath_fail() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(feed)).To(bexml.XPath("//item[1]/title", be.Eq("Celebrate")))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected XML to have //item[1]/title equal to "Celebrate", but it was "Write tests"]
+}
+
+func ExampleEquivalent() {
+	t := &expect.SpyTB{}
+
+	got := `<a:feed xmlns:a="urn:feed"><a:entry b="2" a="1"> hi </a:entry></a:feed>`
+	expect.It[io.Reader](t, strings.NewReader(got)).To(bexml.Equivalent(`
+		<feed xmlns="urn:feed">
+			<entry a="1" b="2">hi</entry>
+		</feed>`,
+	))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleWellFormed() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(`<a><b></a>`)).To(bexml.WellFormed)
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected XML to be well-formed, but it was not: XML syntax error on line 1: element <b> closed by </a>]
+}
+
+func TestWellFormed(t *testing.T) {
+	expect.It[io.Reader](t, strings.NewReader(feed)).To(bexml.WellFormed)
+
+	for doc, want := range map[string]string{
+		"":                   "it was not: the document has no root element",
+		"<a/><b/>":           "it was not: the document has more than one root element",
+		"<a/>text":           "it was not: the document has text outside of the root element",
+		`<a href="x>`:        "it was not: XML syntax error on line 1: unexpected EOF",
+		"<a>&nbsp;</a>":      "it was not: XML syntax error on line 1: invalid character entity &nbsp;",
+		"<a><b></b></a></a>": "it was not: XML syntax error on line 1: unexpected end element </a>",
+	} {
+		spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(doc), bexml.WellFormed, want)
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+		want     string
+	}{
+		{
+			name:     "element name",
+			got:      `<a><b/><c/></a>`,
+			expected: `<a><b/><d/></a>`,
+			want:     "it differed at /a/d: the element was c",
+		},
+		{
+			name:     "namespace",
+			got:      `<a xmlns="urn:one"/>`,
+			expected: `<a xmlns="urn:two"/>`,
+			want:     "it differed at /{urn:two}a: the element was {urn:one}a",
+		},
+		{
+			name:     "attribute value",
+			got:      `<a><item id="1"/><item id="3"/></a>`,
+			expected: `<a><item id="1"/><item id="2"/></a>`,
+			want:     `it differed at /a/item[2]: attribute id was "3", expected "2"`,
+		},
+		{
+			name:     "missing attribute",
+			got:      `<a/>`,
+			expected: `<a id="1"/>`,
+			want:     "it differed at /a: attribute id was missing",
+		},
+		{
+			name:     "extra attribute",
+			got:      `<a id="1" x:lang="en" xmlns:x="urn:x"/>`,
+			expected: `<a id="1"/>`,
+			want:     "it differed at /a: attribute {urn:x}lang was not expected",
+		},
+		{
+			name:     "text",
+			got:      "<a><b>\n\thello\n</b></a>",
+			expected: "<a><b>goodbye</b></a>",
+			want:     `it differed at /a/b: the text was "hello", expected "goodbye"`,
+		},
+		{
+			name:     "missing child",
+			got:      `<a><b/></a>`,
+			expected: `<a><b/><c/></a>`,
+			want:     "it differed at /a: element c was missing",
+		},
+		{
+			name:     "extra child",
+			got:      `<a>text<b/></a>`,
+			expected: `<a>text</a>`,
+			want:     "it differed at /a: element b was not expected",
+		},
+		{
+			name:     "text for an element",
+			got:      `<a>text</a>`,
+			expected: `<a><b/></a>`,
+			want:     `it differed at /a: found text "text", expected element b`,
+		},
+		{
+			name:     "invalid expected",
+			got:      `<a/>`,
+			expected: `<a>`,
+			want:     "the expected XML could not be parsed: XML syntax error on line 1: unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(tt.got), bexml.Equivalent(tt.expected),
+				"expected XML to be equivalent to the expected XML, but "+tt.want,
+			)
+		})
+	}
+
+	t.Run("ignores comments and processing instructions", func(t *testing.T) {
+		got := `<?xml version="1.0"?><a><!-- x --><b>one<!-- y -->two</b><?pi data?></a>`
+		expect.It[io.Reader](t, strings.NewReader(got)).To(bexml.Equivalent(`<a><b>onetwo</b></a>`))
+	})
+}
+
+func TestRespBody(t *testing.T) {
+	res := behttp.Get("/feed").Do(newFeedServer())
+
+	type Channel struct {
+		XMLName xml.Name `xml:"rss"`
+		Title   string   `xml:"channel>title"`
+	}
+
+	expect.It(t, res).To(
+		behttp.RespBody(bexml.WellFormed),
+		behttp.RespBody(bexml.Parsed(be.Eq(Channel{XMLName: xml.Name{Local: "rss"}, Title: "Todos"}))),
+		behttp.RespBody(bexml.XPath("sum(//price)", be.Eq("6.5"))),
+	)
+}
+
+func newFeedServer() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(feed))
+	})
+}
@@ -0,0 +1,1025 @@
+package bexml
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+// XPath evaluates the XPath 1.0 expression against the document and runs the matcher
+// on the string value of the result. A node-set yields the string value of its first
+// node, and fails to match when it is empty. Numbers and booleans are formatted as
+// XPath's string() function would, so XPath("count(//item)", be.Eq("3")) works.
+//
+// The supported subset covers location paths with the child, descendant,
+// descendant-or-self, self, parent, ancestor, ancestor-or-self, following-sibling,
+// preceding-sibling and attribute axes and their abbreviations, predicates, the
+// operators and the core function library. Unprefixed names match elements in any
+// namespace, which makes default namespaces painless, while prefixed names use the
+// prefixes declared in the document.
+func XPath(expr string, matcher expect.Matcher[string]) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have %s", expr),
+			Matches:     false,
+			SubjectName: subjectName,
+		}
+
+		compiled, err := compileXPath(expr)
+		if err != nil {
+			result.But = err.Error()
+			return result
+		}
+		doc, err := parseDocument(rdr)
+		if err != nil {
+			result.But = fmt.Sprintf("it could not be parsed: %v", err)
+			return result
+		}
+
+		v, err := compiled(evalCtx{node: doc, pos: 1, size: 1, doc: doc})
+		if err != nil {
+			result.But = fmt.Sprintf("the expression %q could not be evaluated: %v", expr, err)
+			return result
+		}
+		if nodes, ok := v.([]*node); ok && len(nodes) == 0 {
+			result.But = fmt.Sprintf("no node matched %s", expr)
+			return result
+		}
+
+		r := matcher(toString(v))
+		r = r.WithDescription(fmt.Sprintf("have %s %s", expr, be.Complement(r)))
+		r.SubjectName = subjectName
+		return r
+	}
+}
+
+// value is the result of an XPath expression: a node-set ([]*node), string, float64 or bool.
+type value any
+
+type evalCtx struct {
+	node      *node
+	pos, size int
+	doc       *node
+}
+
+type expr func(evalCtx) (value, error)
+
+func compileXPath(s string) (expr, error) {
+	toks, err := lexXPath(s)
+	if err != nil {
+		return nil, fmt.Errorf("the expression %q is invalid: %w", s, err)
+	}
+	p := &xpathParser{toks: toks}
+	e, err := p.parseOr()
+	if err == nil && p.peek().kind != tokEOF {
+		err = fmt.Errorf("unexpected %q", p.peek().val)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("the expression %q is invalid: %w", s, err)
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokOp
+	tokName
+	tokAxis
+	tokFunc
+	tokNodeType
+	tokString
+	tokNumber
+)
+
+type xtoken struct {
+	kind tokenKind
+	val  string
+	num  float64
+}
+
+func lexXPath(s string) ([]xtoken, error) {
+	var toks []xtoken
+	// operatorContext reports whether a * or name must be read as an operator, which
+	// is when the previous token is not @, ::, (, [, , or an operator.
+	operatorContext := func() bool {
+		if len(toks) == 0 {
+			return false
+		}
+		prev := toks[len(toks)-1]
+		switch prev.kind {
+		case tokName, tokString, tokNumber:
+			return true
+		case tokOp:
+			return prev.val == ")" || prev.val == "]" || prev.val == "." || prev.val == ".."
+		}
+		return false
+	}
+
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.ContainsRune("()[],|+=@-", rune(c)):
+			toks = append(toks, xtoken{kind: tokOp, val: string(c)})
+			i++
+		case c == '!' && strings.HasPrefix(s[i:], "!="), c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' {
+				op += "="
+			}
+			toks = append(toks, xtoken{kind: tokOp, val: op})
+			i += len(op)
+		case c == '/':
+			op := "/"
+			if strings.HasPrefix(s[i:], "//") {
+				op = "//"
+			}
+			toks = append(toks, xtoken{kind: tokOp, val: op})
+			i += len(op)
+		case c == '.' && strings.HasPrefix(s[i:], ".."):
+			toks = append(toks, xtoken{kind: tokOp, val: ".."})
+			i += 2
+		case c == '.' && (i+1 >= len(s) || s[i+1] < '0' || s[i+1] > '9'):
+			toks = append(toks, xtoken{kind: tokOp, val: "."})
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			start := i
+			for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+				i++
+			}
+			n, err := strconv.ParseFloat(s[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a number", s[start:i])
+			}
+			toks = append(toks, xtoken{kind: tokNumber, val: s[start:i], num: n})
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end == -1 {
+				return nil, fmt.Errorf("the string %s is not terminated", s[i:])
+			}
+			toks = append(toks, xtoken{kind: tokString, val: s[i+1 : i+1+end]})
+			i += end + 2
+		case c == '*':
+			kind := tokName
+			if operatorContext() {
+				kind = tokOp
+			}
+			toks = append(toks, xtoken{kind: kind, val: "*"})
+			i++
+		default:
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			if !isNameStart(r) {
+				return nil, fmt.Errorf("unexpected %q", s[i:])
+			}
+			name := readNCName(s, &i)
+			if i+1 < len(s) && s[i] == ':' && s[i+1] != ':' {
+				i++
+				if i < len(s) && s[i] == '*' {
+					name += ":*"
+					i++
+				} else {
+					local := readNCName(s, &i)
+					if local == "" {
+						return nil, fmt.Errorf("the name %s: has no local part", name)
+					}
+					name += ":" + local
+				}
+			}
+
+			if operatorContext() {
+				if name != "and" && name != "or" && name != "div" && name != "mod" {
+					return nil, fmt.Errorf("unexpected %q", name)
+				}
+				toks = append(toks, xtoken{kind: tokOp, val: name})
+				continue
+			}
+
+			rest := strings.TrimLeft(s[i:], " \t\n\r")
+			switch {
+			case strings.HasPrefix(rest, "::"):
+				toks = append(toks, xtoken{kind: tokAxis, val: name})
+				i = len(s) - len(rest) + 2
+			case strings.HasPrefix(rest, "("):
+				kind := tokFunc
+				if name == "node" || name == "text" || name == "comment" || name == "processing-instruction" {
+					kind = tokNodeType
+				}
+				toks = append(toks, xtoken{kind: kind, val: name})
+			default:
+				toks = append(toks, xtoken{kind: tokName, val: name})
+			}
+		}
+	}
+	return append(toks, xtoken{kind: tokEOF}), nil
+}
+
+func readNCName(s string, i *int) string {
+	start := *i
+	for *i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[*i:])
+		if !isNameStart(r) && !unicode.IsDigit(r) && r != '-' && r != '.' {
+			break
+		}
+		*i += size
+	}
+	return s[start:*i]
+}
+
+func isNameStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+type xpathParser struct {
+	toks []xtoken
+	pos  int
+}
+
+func (p *xpathParser) peek() xtoken {
+	return p.toks[p.pos]
+}
+
+func (p *xpathParser) next() xtoken {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *xpathParser) isOp(vals ...string) bool {
+	t := p.peek()
+	return t.kind == tokOp && slices.Contains(vals, t.val)
+}
+
+func (p *xpathParser) expectOp(val string) error {
+	if !p.isOp(val) {
+		if p.peek().kind == tokEOF {
+			return fmt.Errorf("expected %q but the expression ended", val)
+		}
+		return fmt.Errorf("expected %q but found %q", val, p.peek().val)
+	}
+	p.next()
+	return nil
+}
+
+// binary parses a left associative chain of operators over operands parsed by next.
+func (p *xpathParser) binary(next func() (expr, error), apply func(op string, a, b value) (value, error), ops ...string) (expr, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp(ops...) {
+		op := p.next().val
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(c evalCtx) (value, error) {
+			a, err := l(c)
+			if err != nil {
+				return nil, err
+			}
+			b, err := right(c)
+			if err != nil {
+				return nil, err
+			}
+			return apply(op, a, b)
+		}
+	}
+	return left, nil
+}
+
+func (p *xpathParser) parseOr() (expr, error) {
+	return p.binary(p.parseAnd, func(_ string, a, b value) (value, error) {
+		return toBool(a) || toBool(b), nil
+	}, "or")
+}
+
+func (p *xpathParser) parseAnd() (expr, error) {
+	return p.binary(p.parseEquality, func(_ string, a, b value) (value, error) {
+		return toBool(a) && toBool(b), nil
+	}, "and")
+}
+
+func (p *xpathParser) parseEquality() (expr, error) {
+	return p.binary(p.parseRelational, func(op string, a, b value) (value, error) {
+		return compare(op, a, b), nil
+	}, "=", "!=")
+}
+
+func (p *xpathParser) parseRelational() (expr, error) {
+	return p.binary(p.parseAdditive, func(op string, a, b value) (value, error) {
+		return compare(op, a, b), nil
+	}, "<", "<=", ">", ">=")
+}
+
+func (p *xpathParser) parseAdditive() (expr, error) {
+	return p.binary(p.parseMultiplicative, arithmetic, "+", "-")
+}
+
+func (p *xpathParser) parseMultiplicative() (expr, error) {
+	return p.binary(p.parseUnary, arithmetic, "*", "div", "mod")
+}
+
+func (p *xpathParser) parseUnary() (expr, error) {
+	if p.isOp("-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(c evalCtx) (value, error) {
+			v, err := operand(c)
+			if err != nil {
+				return nil, err
+			}
+			return -toNumber(v), nil
+		}, nil
+	}
+	return p.parseUnion()
+}
+
+func (p *xpathParser) parseUnion() (expr, error) {
+	return p.binary(p.parsePath, func(_ string, a, b value) (value, error) {
+		left, lok := a.([]*node)
+		right, rok := b.([]*node)
+		if !lok || !rok {
+			return nil, fmt.Errorf("| requires node-sets")
+		}
+		return documentOrder(append(slices.Clone(left), right...)), nil
+	}, "|")
+}
+
+func (p *xpathParser) parsePath() (expr, error) {
+	t := p.peek()
+	startsFilter := t.kind == tokString || t.kind == tokNumber || t.kind == tokFunc || t.kind == tokOp && t.val == "("
+	if !startsFilter {
+		return p.parseLocationPath()
+	}
+
+	primary, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	preds, err := p.parsePredicates()
+	if err != nil {
+		return nil, err
+	}
+	filter := primary
+	if len(preds) > 0 {
+		filter = func(c evalCtx) (value, error) {
+			v, err := primary(c)
+			if err != nil {
+				return nil, err
+			}
+			nodes, ok := v.([]*node)
+			if !ok {
+				return nil, fmt.Errorf("a predicate can only filter a node-set")
+			}
+			return applyPredicates(documentOrder(nodes), preds, c.doc)
+		}
+	}
+	if !p.isOp("/", "//") {
+		return filter, nil
+	}
+
+	steps, err := p.parseRelativeSteps(nil)
+	if err != nil {
+		return nil, err
+	}
+	return func(c evalCtx) (value, error) {
+		v, err := filter(c)
+		if err != nil {
+			return nil, err
+		}
+		nodes, ok := v.([]*node)
+		if !ok {
+			return nil, fmt.Errorf("a path can only continue from a node-set")
+		}
+		return evalSteps(nodes, steps, c.doc)
+	}, nil
+}
+
+func (p *xpathParser) parseLocationPath() (expr, error) {
+	absolute := false
+	var steps []step
+	switch {
+	case p.isOp("//"):
+		absolute = true
+	case p.isOp("/"):
+		absolute = true
+		p.next()
+		if !p.startsStep() {
+			// the lone / selects the root
+			return func(c evalCtx) (value, error) { return []*node{c.doc}, nil }, nil
+		}
+	}
+
+	if !absolute || p.startsStep() {
+		first, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, first)
+	}
+	steps, err := p.parseRelativeSteps(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c evalCtx) (value, error) {
+		start := c.node
+		if absolute {
+			start = c.doc
+		}
+		return evalSteps([]*node{start}, steps, c.doc)
+	}, nil
+}
+
+// parseRelativeSteps parses any further / and // separated steps.
+func (p *xpathParser) parseRelativeSteps(steps []step) ([]step, error) {
+	for p.isOp("/", "//") {
+		if p.next().val == "//" {
+			steps = append(steps, step{axis: "descendant-or-self", test: nodeTest{kind: "node"}})
+		}
+		s, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, s)
+	}
+	return steps, nil
+}
+
+func (p *xpathParser) startsStep() bool {
+	t := p.peek()
+	return t.kind == tokName || t.kind == tokAxis || t.kind == tokNodeType || t.kind == tokOp && (t.val == "@" || t.val == "." || t.val == "..")
+}
+
+type step struct {
+	axis  string
+	test  nodeTest
+	preds []expr
+}
+
+type nodeTest struct {
+	// kind is "name", "node", "text", "comment" or "processing-instruction".
+	kind          string
+	prefix, local string
+}
+
+func (p *xpathParser) parseStep() (step, error) {
+	switch {
+	case p.isOp("."):
+		p.next()
+		return step{axis: "self", test: nodeTest{kind: "node"}}, nil
+	case p.isOp(".."):
+		p.next()
+		return step{axis: "parent", test: nodeTest{kind: "node"}}, nil
+	}
+
+	s := step{axis: "child"}
+	switch t := p.peek(); {
+	case t.kind == tokAxis:
+		p.next()
+		if !supportedAxes[t.val] {
+			return s, fmt.Errorf("the axis %s is not supported", t.val)
+		}
+		s.axis = t.val
+	case t.kind == tokOp && t.val == "@":
+		p.next()
+		s.axis = "attribute"
+	}
+
+	switch t := p.next(); t.kind {
+	case tokName:
+		s.test = nodeTest{kind: "name", local: t.val}
+		if prefix, local, ok := strings.Cut(t.val, ":"); ok {
+			s.test.prefix, s.test.local = prefix, local
+		}
+	case tokNodeType:
+		s.test = nodeTest{kind: t.val}
+		if err := p.expectOp("("); err != nil {
+			return s, err
+		}
+		if t.val == "processing-instruction" && p.peek().kind == tokString {
+			p.next()
+		}
+		if err := p.expectOp(")"); err != nil {
+			return s, err
+		}
+	case tokEOF:
+		return s, fmt.Errorf("expected a step but the expression ended")
+	default:
+		return s, fmt.Errorf("expected a step but found %q", t.val)
+	}
+
+	preds, err := p.parsePredicates()
+	if err != nil {
+		return s, err
+	}
+	s.preds = preds
+	return s, nil
+}
+
+func (p *xpathParser) parsePredicates() ([]expr, error) {
+	var preds []expr
+	for p.isOp("[") {
+		p.next()
+		pred, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp("]"); err != nil {
+			return nil, err
+		}
+		preds = append(preds, pred)
+	}
+	return preds, nil
+}
+
+func (p *xpathParser) parsePrimary() (expr, error) {
+	switch t := p.next(); t.kind {
+	case tokString:
+		return func(evalCtx) (value, error) { return t.val, nil }, nil
+	case tokNumber:
+		return func(evalCtx) (value, error) { return t.num, nil }, nil
+	case tokFunc:
+		return p.parseCall(t.val)
+	default:
+		// the parser only calls parsePrimary for a ( otherwise
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expectOp(")")
+	}
+}
+
+func (p *xpathParser) parseCall(name string) (expr, error) {
+	fn, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("the function %s() is not supported", name)
+	}
+	if err := p.expectOp("("); err != nil {
+		return nil, err
+	}
+
+	var args []expr
+	for !p.isOp(")") {
+		if len(args) > 0 {
+			if err := p.expectOp(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next()
+
+	if len(args) < fn.minArgs || fn.maxArgs >= 0 && len(args) > fn.maxArgs {
+		return nil, fmt.Errorf("the function %s() does not take %d arguments", name, len(args))
+	}
+	return func(c evalCtx) (value, error) {
+		vals := make([]value, 0, len(args))
+		for _, arg := range args {
+			v, err := arg(c)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+		}
+		return fn.call(c, vals)
+	}, nil
+}
+
+var supportedAxes = map[string]bool{
+	"child": true, "descendant": true, "descendant-or-self": true, "self": true, "parent": true,
+	"ancestor": true, "ancestor-or-self": true, "following-sibling": true, "preceding-sibling": true,
+	"attribute": true,
+}
+
+func evalSteps(nodes []*node, steps []step, doc *node) ([]*node, error) {
+	for _, s := range steps {
+		var next []*node
+		for _, n := range nodes {
+			candidates, err := s.candidates(n, doc)
+			if err != nil {
+				return nil, err
+			}
+			selected, err := applyPredicates(candidates, s.preds, doc)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, selected...)
+		}
+		nodes = documentOrder(next)
+	}
+	return nodes, nil
+}
+
+// candidates returns the nodes along the axis that pass the node test, in axis order.
+func (s step) candidates(n *node, doc *node) ([]*node, error) {
+	principal := elementNode
+	if s.axis == "attribute" {
+		principal = attributeNode
+	}
+
+	space := ""
+	if s.test.prefix != "" {
+		uri, ok := doc.prefixes[s.test.prefix]
+		if !ok {
+			return nil, fmt.Errorf("the prefix %q is not declared in the document", s.test.prefix)
+		}
+		space = uri
+	}
+
+	var out []*node
+	for _, c := range axisNodes(s.axis, n) {
+		if s.test.matches(c, principal, space) {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (t nodeTest) matches(n *node, principal nodeKind, space string) bool {
+	switch t.kind {
+	case "node":
+		return true
+	case "text":
+		return n.kind == textNode
+	case "name":
+		if n.kind != principal {
+			return false
+		}
+		if t.prefix != "" && n.name.Space != space {
+			return false
+		}
+		return t.local == "*" || n.name.Local == t.local
+	default:
+		// comments and processing instructions are not kept
+		return false
+	}
+}
+
+func axisNodes(axis string, n *node) []*node {
+	switch axis {
+	case "self":
+		return []*node{n}
+	case "child":
+		return n.children
+	case "attribute":
+		return n.attrs
+	case "parent":
+		if n.parent == nil {
+			return nil
+		}
+		return []*node{n.parent}
+	case "ancestor", "ancestor-or-self":
+		var out []*node
+		if axis == "ancestor-or-self" {
+			out = append(out, n)
+		}
+		for a := n.parent; a != nil; a = a.parent {
+			out = append(out, a)
+		}
+		return out
+	case "descendant", "descendant-or-self":
+		var out []*node
+		if axis == "descendant-or-self" {
+			out = append(out, n)
+		}
+		var walk func(*node)
+		walk = func(n *node) {
+			for _, c := range n.children {
+				out = append(out, c)
+				walk(c)
+			}
+		}
+		walk(n)
+		return out
+	case "following-sibling", "preceding-sibling":
+		if n.parent == nil || n.kind == attributeNode {
+			return nil
+		}
+		sibs := n.parent.children
+		i := slices.Index(sibs, n)
+		if axis == "following-sibling" {
+			return sibs[i+1:]
+		}
+		preceding := slices.Clone(sibs[:i])
+		slices.Reverse(preceding)
+		return preceding
+	}
+	return nil
+}
+
+func applyPredicates(nodes []*node, preds []expr, doc *node) ([]*node, error) {
+	for _, pred := range preds {
+		var kept []*node
+		for i, n := range nodes {
+			v, err := pred(evalCtx{node: n, pos: i + 1, size: len(nodes), doc: doc})
+			if err != nil {
+				return nil, err
+			}
+			if num, ok := v.(float64); ok {
+				if num == float64(i+1) {
+					kept = append(kept, n)
+				}
+				continue
+			}
+			if toBool(v) {
+				kept = append(kept, n)
+			}
+		}
+		nodes = kept
+	}
+	return nodes, nil
+}
+
+// documentOrder sorts the nodes into document order and removes duplicates.
+func documentOrder(nodes []*node) []*node {
+	slices.SortFunc(nodes, func(a, b *node) int { return a.order - b.order })
+	return slices.Compact(nodes)
+}
+
+func toString(v value) string {
+	switch v := v.(type) {
+	case []*node:
+		if len(v) == 0 {
+			return ""
+		}
+		return v[0].stringValue()
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return "NaN"
+		case math.IsInf(v, 1):
+			return "Infinity"
+		case math.IsInf(v, -1):
+			return "-Infinity"
+		case v == math.Trunc(v) && math.Abs(v) < 1e15:
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+func toNumber(v value) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(toString(v)), 64)
+	if err != nil {
+		return math.NaN()
+	}
+	return n
+}
+
+func toBool(v value) bool {
+	switch v := v.(type) {
+	case []*node:
+		return len(v) > 0
+	case string:
+		return v != ""
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	case bool:
+		return v
+	}
+	return false
+}
+
+// compare implements the XPath comparison rules, where a node-set compares true when
+// any of its nodes does.
+func compare(op string, a, b value) bool {
+	if nodes, ok := a.([]*node); ok {
+		if _, isBool := b.(bool); isBool {
+			return compareAtoms(op, toBool(a), b)
+		}
+		return slices.ContainsFunc(nodes, func(n *node) bool {
+			return compare(op, n.stringValue(), b)
+		})
+	}
+	if nodes, ok := b.([]*node); ok {
+		if _, isBool := a.(bool); isBool {
+			return compareAtoms(op, a, toBool(b))
+		}
+		return slices.ContainsFunc(nodes, func(n *node) bool {
+			return compare(op, a, n.stringValue())
+		})
+	}
+	return compareAtoms(op, a, b)
+}
+
+func compareAtoms(op string, a, b value) bool {
+	if op == "=" || op == "!=" {
+		var equal bool
+		_, aBool := a.(bool)
+		_, bBool := b.(bool)
+		_, aNum := a.(float64)
+		_, bNum := b.(float64)
+		switch {
+		case aBool || bBool:
+			equal = toBool(a) == toBool(b)
+		case aNum || bNum:
+			equal = toNumber(a) == toNumber(b)
+		default:
+			equal = toString(a) == toString(b)
+		}
+		return equal == (op == "=")
+	}
+
+	x, y := toNumber(a), toNumber(b)
+	switch op {
+	case "<":
+		return x < y
+	case "<=":
+		return x <= y
+	case ">":
+		return x > y
+	default:
+		return x >= y
+	}
+}
+
+func arithmetic(op string, a, b value) (value, error) {
+	x, y := toNumber(a), toNumber(b)
+	switch op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "div":
+		return x / y, nil
+	default:
+		return math.Mod(x, y), nil
+	}
+}
+
+type function struct {
+	// maxArgs is -1 when the function is variadic.
+	minArgs, maxArgs int
+	call             func(c evalCtx, args []value) (value, error)
+}
+
+// contextOr returns the single argument, or the context node when there is none.
+func contextOr(c evalCtx, args []value) value {
+	if len(args) == 0 {
+		return []*node{c.node}
+	}
+	return args[0]
+}
+
+func nodeSetArg(name string, v value) ([]*node, error) {
+	nodes, ok := v.([]*node)
+	if !ok {
+		return nil, fmt.Errorf("%s() requires a node-set", name)
+	}
+	return nodes, nil
+}
+
+func nameOf(name string, c evalCtx, args []value, format func(*node) string) (value, error) {
+	nodes, err := nodeSetArg(name, contextOr(c, args))
+	if err != nil || len(nodes) == 0 {
+		return "", err
+	}
+	return format(nodes[0]), nil
+}
+
+var functions = map[string]function{
+	"last":     {0, 0, func(c evalCtx, _ []value) (value, error) { return float64(c.size), nil }},
+	"position": {0, 0, func(c evalCtx, _ []value) (value, error) { return float64(c.pos), nil }},
+	"count": {1, 1, func(_ evalCtx, args []value) (value, error) {
+		nodes, err := nodeSetArg("count", args[0])
+		return float64(len(nodes)), err
+	}},
+	"name": {0, 1, func(c evalCtx, args []value) (value, error) {
+		return nameOf("name", c, args, func(n *node) string {
+			for _, prefix := range slices.Sorted(maps.Keys(c.doc.prefixes)) {
+				if c.doc.prefixes[prefix] == n.name.Space && n.name.Space != "" {
+					return prefix + ":" + n.name.Local
+				}
+			}
+			return n.name.Local
+		})
+	}},
+	"local-name": {0, 1, func(c evalCtx, args []value) (value, error) {
+		return nameOf("local-name", c, args, func(n *node) string { return n.name.Local })
+	}},
+	"namespace-uri": {0, 1, func(c evalCtx, args []value) (value, error) {
+		return nameOf("namespace-uri", c, args, func(n *node) string { return n.name.Space })
+	}},
+	"string": {0, 1, func(c evalCtx, args []value) (value, error) { return toString(contextOr(c, args)), nil }},
+	"concat": {2, -1, func(_ evalCtx, args []value) (value, error) {
+		var sb strings.Builder
+		for _, a := range args {
+			sb.WriteString(toString(a))
+		}
+		return sb.String(), nil
+	}},
+	"starts-with": {2, 2, func(_ evalCtx, args []value) (value, error) {
+		return strings.HasPrefix(toString(args[0]), toString(args[1])), nil
+	}},
+	"ends-with": {2, 2, func(_ evalCtx, args []value) (value, error) {
+		return strings.HasSuffix(toString(args[0]), toString(args[1])), nil
+	}},
+	"contains": {2, 2, func(_ evalCtx, args []value) (value, error) {
+		return strings.Contains(toString(args[0]), toString(args[1])), nil
+	}},
+	"substring-before": {2, 2, func(_ evalCtx, args []value) (value, error) {
+		before, _, found := strings.Cut(toString(args[0]), toString(args[1]))
+		if !found {
+			return "", nil
+		}
+		return before, nil
+	}},
+	"substring-after": {2, 2, func(_ evalCtx, args []value) (value, error) {
+		_, after, _ := strings.Cut(toString(args[0]), toString(args[1]))
+		return after, nil
+	}},
+	"substring": {2, 3, func(_ evalCtx, args []value) (value, error) {
+		runes := []rune(toString(args[0]))
+		start := math.Round(toNumber(args[1]))
+		end := math.Inf(1)
+		if len(args) == 3 {
+			end = start + math.Round(toNumber(args[2]))
+		}
+		var sb strings.Builder
+		for i, r := range runes {
+			if pos := float64(i + 1); pos >= start && pos < end {
+				sb.WriteRune(r)
+			}
+		}
+		return sb.String(), nil
+	}},
+	"string-length": {0, 1, func(c evalCtx, args []value) (value, error) {
+		return float64(utf8.RuneCountInString(toString(contextOr(c, args)))), nil
+	}},
+	"normalize-space": {0, 1, func(c evalCtx, args []value) (value, error) {
+		return strings.Join(strings.Fields(toString(contextOr(c, args))), " "), nil
+	}},
+	"translate": {3, 3, func(_ evalCtx, args []value) (value, error) {
+		from, to := []rune(toString(args[1])), []rune(toString(args[2]))
+		return strings.Map(func(r rune) rune {
+			i := slices.Index(from, r)
+			switch {
+			case i == -1:
+				return r
+			case i < len(to):
+				return to[i]
+			default:
+				return -1
+			}
+		}, toString(args[0])), nil
+	}},
+	"not":     {1, 1, func(_ evalCtx, args []value) (value, error) { return !toBool(args[0]), nil }},
+	"true":    {0, 0, func(evalCtx, []value) (value, error) { return true, nil }},
+	"false":   {0, 0, func(evalCtx, []value) (value, error) { return false, nil }},
+	"boolean": {1, 1, func(_ evalCtx, args []value) (value, error) { return toBool(args[0]), nil }},
+	"number":  {0, 1, func(c evalCtx, args []value) (value, error) { return toNumber(contextOr(c, args)), nil }},
+	"sum": {1, 1, func(_ evalCtx, args []value) (value, error) {
+		nodes, err := nodeSetArg("sum", args[0])
+		var total float64
+		for _, n := range nodes {
+			total += toNumber(n.stringValue())
+		}
+		return total, err
+	}},
+	"floor":   {1, 1, func(_ evalCtx, args []value) (value, error) { return math.Floor(toNumber(args[0])), nil }},
+	"ceiling": {1, 1, func(_ evalCtx, args []value) (value, error) { return math.Ceil(toNumber(args[0])), nil }},
+	"round": {1, 1, func(_ evalCtx, args []value) (value, error) {
+		return math.Floor(toNumber(args[0]) + 0.5), nil
+	}},
+}
@@ -0,0 +1,82 @@
+package bexml_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/bexml"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func TestXPath(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: "/rss/@version", want: "2.0"},
+		{expr: "//item[2]/@done", want: "true"},
+		{expr: "//item[@done]/title", want: "Ship it"},
+		{expr: "//item[not(@done)]/title", want: "Write tests"},
+		{expr: "//item[price > 3]/@id", want: "2"},
+		{expr: "//title[. = 'Ship it']/../@id", want: "2"},
+		{expr: "//price[1]/parent::item/title", want: "Write tests"},
+		{expr: "//creator[.='pepper']/ancestor::channel/title", want: "Todos"},
+		{expr: "//item[1]/following-sibling::item/@id", want: "2"},
+		{expr: "//item[2]/preceding-sibling::*[1]/@id", want: "1"},
+		{expr: "//item[position() = last()]/title/text()", want: "Ship it"},
+		{expr: "name(/*)", want: "rss"},
+		{expr: "local-name(//dc:creator)", want: "creator"},
+		{expr: "namespace-uri(//dc:creator)", want: "http://purl.org/dc/elements/1.1/"},
+		{expr: "count(//item | //title)", want: "5"},
+		{expr: "count(//@*)", want: "4"},
+		{expr: "sum(//price) * 2", want: "13"},
+		{expr: "floor(2.5) + ceiling(2.5) - round(2.5)", want: "2"},
+		{expr: "10 div 4", want: "2.5"},
+		{expr: "7 mod 3", want: "1"},
+		{expr: "-//item[1]/price", want: "-2.5"},
+		{expr: "1 div 0", want: "Infinity"},
+		{expr: "number('x')", want: "NaN"},
+		{expr: "concat(//item[1]/title, ' & ', //item[2]/title)", want: "Write tests & Ship it"},
+		{expr: "substring(//channel/title, 2, 3)", want: "odo"},
+		{expr: "substring-before('2024-01-02', '-')", want: "2024"},
+		{expr: "substring-after('2024-01-02', '-')", want: "01-02"},
+		{expr: "string-length(//item[1]/title)", want: "11"},
+		{expr: "normalize-space('  a   b ')", want: "a b"},
+		{expr: "translate('bar', 'abc', 'ABC')", want: "BAr"},
+		{expr: "starts-with(//channel/title, 'To') and ends-with(//channel/title, 'os')", want: "true"},
+		{expr: "contains(//item[1]/title, 'test') or false()", want: "true"},
+		{expr: "boolean(//missing)", want: "false"},
+		{expr: "string(//item/price)", want: "2.5"},
+		{expr: "//item[1]/price < //item[2]/price", want: "true"},
+		{expr: "//price != 4", want: "true"},
+		{expr: "(//item/title)[last()]", want: "Ship it"},
+		{expr: "//channel/item[@id = '1' ]/self::item/dc:creator", want: "berg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expect.It[io.Reader](t, strings.NewReader(feed)).To(bexml.XPath(tt.expr, be.Eq(tt.want)))
+		})
+	}
+
+	t.Run("default namespaces", func(t *testing.T) {
+		doc := `<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>hi</title></entry></feed>`
+		expect.It[io.Reader](t, strings.NewReader(doc)).To(bexml.XPath("/feed/entry/title", be.Eq("hi")))
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		for expr, want := range map[string]string{
+			"//missing":       "expected XML to have //missing, but no node matched //missing",
+			"//item[":         `expected XML to have //item[, but the expression "//item[" is invalid: expected a step but the expression ended`,
+			"//item[@id='1'":  `expected XML to have //item[@id='1', but the expression "//item[@id='1'" is invalid: expected "]"`,
+			"following::item": `expected XML to have following::item, but the expression "following::item" is invalid: the axis following is not supported`,
+			"nope(1)":         `expected XML to have nope(1), but the expression "nope(1)" is invalid: the function nope() is not supported`,
+			"//x:item":        `expected XML to have //x:item, but the expression "//x:item" could not be evaluated: the prefix "x" is not declared in the document`,
+			"//item[1]/title": `expected XML to have //item[1]/title equal to "Ship it", but it was "Write tests"`,
+		} {
+			spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(feed), bexml.XPath(expr, be.Eq("Ship it")), want)
+		}
+	})
+}
@@ -0,0 +1,937 @@
+package beyaml
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// parse reads a single YAML document into map[string]any, []any, string, int64, float64,
+// bool and nil values. It supports block and flow collections, every scalar style,
+// anchors, aliases, merge keys and the core schema tags. Mapping keys are kept as the
+// text they were written with.
+func parse(r io.Reader) (any, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	src := strings.TrimPrefix(string(b), "\ufeff")
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+
+	p := &parser{src: src, line: 1, anchors: make(map[string]any)}
+	return p.parseStream()
+}
+
+type parser struct {
+	src       string
+	pos       int
+	line      int
+	lineStart int
+	anchors   map[string]any
+}
+
+type state struct {
+	pos, line, lineStart int
+}
+
+// plainScalar is an unresolved plain scalar, it is resolved once any tag is known.
+type plainScalar string
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) save() state             { return state{p.pos, p.line, p.lineStart} }
+func (p *parser) restore(s state)         { p.pos, p.line, p.lineStart = s.pos, s.line, s.lineStart }
+func (p *parser) eof() bool               { return p.pos >= len(p.src) }
+func (p *parser) col() int                { return p.pos - p.lineStart }
+func (p *parser) rest() string            { return p.src[p.pos:] }
+func (p *parser) hasPrefix(s string) bool { return strings.HasPrefix(p.rest(), s) }
+
+func (p *parser) peek(off int) byte {
+	if p.pos+off >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos+off]
+}
+
+func (p *parser) advance(n int) {
+	for range n {
+		if p.eof() {
+			return
+		}
+		if p.src[p.pos] == '\n' {
+			p.line++
+			p.lineStart = p.pos + 1
+		}
+		p.pos++
+	}
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == 0
+}
+
+func isFlowIndicator(c byte) bool {
+	return c == ',' || c == '[' || c == ']' || c == '{' || c == '}'
+}
+
+func (p *parser) skipSpaces() {
+	for c := p.peek(0); c == ' ' || c == '\t'; c = p.peek(0) {
+		p.advance(1)
+	}
+}
+
+// atLineEnd reports whether only a comment or nothing is left on the line.
+func (p *parser) atLineEnd() bool {
+	c := p.peek(0)
+	return p.eof() || c == '\n' || c == '#' && (p.pos == 0 || isBlank(p.src[p.pos-1]))
+}
+
+// skipToContent moves past whitespace, comments and line breaks.
+func (p *parser) skipToContent() {
+	for !p.eof() {
+		p.skipSpaces()
+		if p.atLineEnd() {
+			for !p.eof() && p.peek(0) != '\n' {
+				p.advance(1)
+			}
+			p.advance(1)
+			continue
+		}
+		return
+	}
+}
+
+// atDocumentMarker reports whether the position is at a --- or ... line.
+func (p *parser) atDocumentMarker() bool {
+	return p.col() == 0 && (p.hasPrefix("---") || p.hasPrefix("...")) && isBlank(p.peek(3))
+}
+
+// checkIndent rejects tabs used to indent a block node.
+func (p *parser) checkIndent() error {
+	if strings.TrimLeft(p.src[p.lineStart:p.pos], " ") == "" {
+		return nil
+	}
+	if strings.Trim(p.src[p.lineStart:p.pos], " \t") == "" {
+		return p.errorf("tabs are not allowed for indentation")
+	}
+	return nil
+}
+
+func (p *parser) parseStream() (any, error) {
+	p.skipToContent()
+	for !p.eof() && p.col() == 0 && p.peek(0) == '%' {
+		for !p.eof() && p.peek(0) != '\n' {
+			p.advance(1)
+		}
+		p.skipToContent()
+	}
+
+	explicit := false
+	if p.atDocumentMarker() && p.hasPrefix("---") {
+		explicit = true
+		p.advance(3)
+	}
+	if !explicit && p.eof() {
+		return nil, errors.New("the document is empty")
+	}
+	v, err := p.parseBlockNode(-1, false)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipToContent()
+	if !p.eof() && p.atDocumentMarker() && p.hasPrefix("...") {
+		p.advance(3)
+		p.skipToContent()
+	}
+	switch {
+	case p.eof():
+		return v, nil
+	case p.atDocumentMarker():
+		return nil, p.errorf("the stream has more than one document")
+	}
+	return nil, p.errorf("unexpected %q", p.currentLine())
+}
+
+func (p *parser) currentLine() string {
+	line, _, _ := strings.Cut(p.rest(), "\n")
+	return strings.TrimSpace(line)
+}
+
+// parseBlockNode parses the node at the next content. Content on a later line must be
+// indented more than parentIndent, or for a sequence as much as it when seqAtParent is
+// set, otherwise the node is empty.
+func (p *parser) parseBlockNode(parentIndent int, seqAtParent bool) (any, error) {
+	startLine := p.line
+	p.skipToContent()
+	if p.eof() || p.atDocumentMarker() {
+		return nil, nil
+	}
+	if p.line != startLine {
+		if p.col() < parentIndent || p.col() == parentIndent && !(seqAtParent && p.atSequenceEntry()) {
+			return nil, nil
+		}
+	}
+	if err := p.checkIndent(); err != nil {
+		return nil, err
+	}
+
+	anchor, tag, err := p.parseProperties()
+	if err != nil {
+		return nil, err
+	}
+	if (anchor != "" || tag != "") && p.atLineEnd() {
+		v, err := p.parseBlockNode(parentIndent, seqAtParent)
+		if err != nil {
+			return nil, err
+		}
+		return p.finish(v, anchor, tag)
+	}
+
+	var (
+		v     any
+		block bool
+	)
+	switch c := p.peek(0); {
+	case c == '*':
+		v, err = p.parseAlias()
+	case p.atSequenceEntry():
+		v, err = p.parseBlockSequence(p.col())
+		block = true
+	case c == '|' || c == '>':
+		v, err = p.parseBlockScalar(parentIndent)
+		block = true
+	case c == '?':
+		return nil, p.errorf("explicit mapping keys are not supported")
+	case p.atMappingKey():
+		v, err = p.parseBlockMapping(p.col())
+		block = true
+	case c == '[' || c == '{':
+		v, err = p.parseFlowNode()
+	case c == '"' || c == '\'':
+		v, err = p.parseQuoted()
+	default:
+		v, err = p.parsePlainBlock(parentIndent)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !block {
+		p.skipSpaces()
+		if !p.atLineEnd() {
+			return nil, p.errorf("unexpected %q after the value", p.currentLine())
+		}
+	}
+	return p.finish(v, anchor, tag)
+}
+
+func (p *parser) parseProperties() (anchor, tag string, err error) {
+	for {
+		switch p.peek(0) {
+		case '&':
+			if anchor != "" {
+				return "", "", p.errorf("a node can only have one anchor")
+			}
+			p.advance(1)
+			anchor = p.readName()
+			if anchor == "" {
+				return "", "", p.errorf("expected an anchor name after &")
+			}
+		case '!':
+			if tag != "" {
+				return "", "", p.errorf("a node can only have one tag")
+			}
+			start := p.pos
+			for !isBlank(p.peek(0)) && !p.eof() {
+				p.advance(1)
+			}
+			tag = p.src[start:p.pos]
+		default:
+			return anchor, tag, nil
+		}
+		p.skipSpaces()
+	}
+}
+
+func (p *parser) readName() string {
+	start := p.pos
+	for c := p.peek(0); !p.eof() && !isBlank(c) && !isFlowIndicator(c); c = p.peek(0) {
+		p.advance(1)
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) parseAlias() (any, error) {
+	p.advance(1)
+	name := p.readName()
+	v, ok := p.anchors[name]
+	if !ok {
+		return nil, p.errorf("the alias *%s refers to an unknown anchor", name)
+	}
+	return v, nil
+}
+
+// finish resolves a plain scalar according to its tag and records the anchor. The core
+// schema tags also resolve quoted scalars, so !!int "3" is 3.
+func (p *parser) finish(v any, anchor, tag string) (any, error) {
+	switch s := v.(type) {
+	case plainScalar:
+		if tag == "!!str" || tag == "!" {
+			v = string(s)
+		} else {
+			v = resolve(string(s))
+		}
+	case string:
+		if tag == "!!int" || tag == "!!float" || tag == "!!bool" || tag == "!!null" {
+			v = resolve(s)
+		}
+	}
+
+	var ok bool
+	switch tag {
+	case "!!int":
+		_, ok = v.(int64)
+	case "!!float":
+		if n, isInt := v.(int64); isInt {
+			v = float64(n)
+		}
+		_, ok = v.(float64)
+	case "!!bool":
+		_, ok = v.(bool)
+	case "!!null":
+		ok = v == nil
+	case "!!str":
+		_, ok = v.(string)
+	case "!!map":
+		_, ok = v.(map[string]any)
+	case "!!seq":
+		_, ok = v.([]any)
+	default:
+		ok = true
+	}
+	if !ok {
+		return nil, p.errorf("the value %v cannot be tagged %s", v, tag)
+	}
+
+	if anchor != "" {
+		p.anchors[anchor] = v
+	}
+	return v, nil
+}
+
+var (
+	intPattern   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	floatPattern = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// resolve applies the YAML 1.2 core schema to a plain scalar.
+func resolve(s string) any {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+
+	switch {
+	case strings.HasPrefix(s, "0x"):
+		if n, err := strconv.ParseInt(s[2:], 16, 64); err == nil {
+			return n
+		}
+	case strings.HasPrefix(s, "0o"):
+		if n, err := strconv.ParseInt(s[2:], 8, 64); err == nil {
+			return n
+		}
+	case intPattern.MatchString(s):
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case floatPattern.MatchString(s):
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+func (p *parser) atSequenceEntry() bool {
+	return p.peek(0) == '-' && (isBlank(p.peek(1)) || p.pos+1 >= len(p.src))
+}
+
+// atMappingKey reports whether the line continues with a key followed by a colon.
+func (p *parser) atMappingKey() bool {
+	line, _, _ := strings.Cut(p.rest(), "\n")
+	if line == "" || isFlowIndicator(line[0]) || line[0] == '#' {
+		return false
+	}
+
+	i := 0
+	if q := line[0]; q == '"' || q == '\'' {
+		end, ok := quotedEnd(line, q)
+		if !ok {
+			return false
+		}
+		i = strings.IndexFunc(line[end:], func(r rune) bool { return r != ' ' && r != '\t' })
+		return i != -1 && line[end+i] == ':' && (end+i+1 == len(line) || isBlank(line[end+i+1]))
+	}
+
+	for ; i < len(line); i++ {
+		switch {
+		case line[i] == '#' && i > 0 && isBlank(line[i-1]):
+			return false
+		case line[i] == ':' && (i+1 == len(line) || isBlank(line[i+1])):
+			return true
+		}
+	}
+	return false
+}
+
+// quotedEnd finds the end of a single-line quoted string starting at s[0].
+func quotedEnd(s string, q byte) (int, bool) {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case q == '\'' && s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == q:
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+func (p *parser) parseBlockMapping(indent int) (any, error) {
+	var (
+		m      = make(map[string]any)
+		merges []any
+	)
+	for {
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		p.advance(1) // the colon
+
+		v, err := p.parseBlockNode(indent, true)
+		if err != nil {
+			return nil, err
+		}
+		if key == "<<" {
+			merges = append(merges, v)
+		} else {
+			if _, dup := m[key]; dup {
+				return nil, p.errorf("the key %q is already defined", key)
+			}
+			m[key] = v
+		}
+
+		p.skipToContent()
+		if p.eof() || p.atDocumentMarker() || p.col() < indent {
+			break
+		}
+		if p.col() > indent {
+			return nil, p.errorf("unexpected indentation of %q", p.currentLine())
+		}
+		if err := p.checkIndent(); err != nil {
+			return nil, err
+		}
+		if !p.atMappingKey() {
+			return nil, p.errorf("expected a mapping key, found %q", p.currentLine())
+		}
+	}
+
+	for _, merge := range merges {
+		sources := []any{merge}
+		if l, ok := merge.([]any); ok {
+			sources = l
+		}
+		for _, src := range sources {
+			sm, ok := src.(map[string]any)
+			if !ok {
+				return nil, p.errorf("the merge key << must refer to a mapping")
+			}
+			for k, v := range sm {
+				if _, ok := m[k]; !ok {
+					m[k] = v
+				}
+			}
+		}
+	}
+	return m, nil
+}
+
+// parseKey reads a block mapping key up to its colon.
+func (p *parser) parseKey() (string, error) {
+	if c := p.peek(0); c == '"' || c == '\'' {
+		key, err := p.parseQuoted()
+		if err != nil {
+			return "", err
+		}
+		p.skipSpaces()
+		return key, nil
+	}
+
+	start := p.pos
+	for !(p.peek(0) == ':' && isBlank(p.peek(1))) {
+		p.advance(1)
+	}
+	return strings.TrimRight(p.src[start:p.pos], " \t"), nil
+}
+
+func (p *parser) parseBlockSequence(indent int) (any, error) {
+	var l []any
+	for {
+		p.advance(1) // the dash
+		v, err := p.parseBlockNode(indent, false)
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, v)
+
+		p.skipToContent()
+		if p.eof() || p.atDocumentMarker() || p.col() < indent {
+			break
+		}
+		if p.col() > indent {
+			return nil, p.errorf("unexpected indentation of %q", p.currentLine())
+		}
+		if err := p.checkIndent(); err != nil {
+			return nil, err
+		}
+		if !p.atSequenceEntry() {
+			break
+		}
+	}
+	return l, nil
+}
+
+// parsePlainBlock reads a plain scalar, folding any continuation lines indented more
+// than parentIndent. A continuation line can't be indented with tabs or hold a mapping key.
+func (p *parser) parsePlainBlock(parentIndent int) (any, error) {
+	var sb strings.Builder
+	sb.WriteString(p.readPlainLine())
+	for {
+		s := p.save()
+		breaks := 0
+		for {
+			p.skipSpaces()
+			if p.peek(0) != '\n' {
+				break
+			}
+			p.advance(1)
+			breaks++
+		}
+		if breaks == 0 || p.eof() || p.atDocumentMarker() || p.col() <= parentIndent || p.atLineEnd() {
+			p.restore(s)
+			break
+		}
+		if indent := p.src[p.lineStart:p.pos]; len(indent)-len(strings.TrimLeft(indent, " ")) <= parentIndent {
+			return nil, p.errorf("tabs are not allowed for indentation")
+		}
+		if p.atMappingKey() {
+			return nil, p.errorf("unexpected mapping key %q in a multi-line scalar", p.currentLine())
+		}
+		if breaks == 1 {
+			sb.WriteByte(' ')
+		} else {
+			sb.WriteString(strings.Repeat("\n", breaks-1))
+		}
+		sb.WriteString(p.readPlainLine())
+	}
+	return plainScalar(sb.String()), nil
+}
+
+func (p *parser) readPlainLine() string {
+	start := p.pos
+	for !p.eof() && p.peek(0) != '\n' && !(p.peek(0) == '#' && p.pos > 0 && isBlank(p.src[p.pos-1])) {
+		p.advance(1)
+	}
+	return strings.TrimRight(p.src[start:p.pos], " \t")
+}
+
+func (p *parser) parseQuoted() (string, error) {
+	q := p.peek(0)
+	p.advance(1)
+
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("the quoted string is not closed")
+		}
+		c := p.peek(0)
+		switch {
+		case c == q && q == '\'' && p.peek(1) == '\'':
+			sb.WriteByte('\'')
+			p.advance(2)
+		case c == q:
+			p.advance(1)
+			return sb.String(), nil
+		case c == '\\' && q == '"':
+			if p.peek(1) == '\n' {
+				p.advance(2)
+				p.skipSpaces()
+				continue
+			}
+			if err := p.readEscape(&sb); err != nil {
+				return "", err
+			}
+		case c == '\n':
+			// fold the line break, trailing and leading white space are dropped
+			trimmed := strings.TrimRight(sb.String(), " \t")
+			sb.Reset()
+			sb.WriteString(trimmed)
+
+			breaks := 0
+			for p.peek(0) == '\n' {
+				p.advance(1)
+				breaks++
+				p.skipSpaces()
+			}
+			if breaks == 1 {
+				sb.WriteByte(' ')
+			} else {
+				sb.WriteString(strings.Repeat("\n", breaks-1))
+			}
+		default:
+			sb.WriteByte(c)
+			p.advance(1)
+		}
+	}
+}
+
+var escapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v", 'f': "\f",
+	'r': "\r", 'e': "\x1b", ' ': " ", '"': `"`, '/': "/", '\\': `\`, 'N': "\u0085",
+	'_': " ", 'L': " ", 'P': " ",
+}
+
+func (p *parser) readEscape(sb *strings.Builder) error {
+	c := p.peek(1)
+	if s, ok := escapes[c]; ok {
+		sb.WriteString(s)
+		p.advance(2)
+		return nil
+	}
+
+	size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+	if size == 0 || p.pos+2+size > len(p.src) {
+		return p.errorf("invalid escape \\%c", c)
+	}
+	hex := p.src[p.pos+2 : p.pos+2+size]
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || !utf8.ValidRune(rune(n)) {
+		return p.errorf("invalid escape \\%c%s", c, hex)
+	}
+	sb.WriteRune(rune(n))
+	p.advance(2 + size)
+	return nil
+}
+
+func (p *parser) parseBlockScalar(parentIndent int) (string, error) {
+	literal := p.peek(0) == '|'
+	p.advance(1)
+
+	var (
+		chomp    byte
+		explicit int
+	)
+	for range 2 {
+		switch c := p.peek(0); {
+		case c == '-' || c == '+':
+			chomp = c
+			p.advance(1)
+		case c >= '1' && c <= '9':
+			explicit = int(c - '0')
+			p.advance(1)
+		}
+	}
+	p.skipSpaces()
+	if !p.atLineEnd() {
+		return "", p.errorf("unexpected %q after the block scalar indicator", p.currentLine())
+	}
+	for !p.eof() && p.peek(0) != '\n' {
+		p.advance(1)
+	}
+	p.advance(1)
+
+	indent := -1
+	if explicit > 0 {
+		indent = max(parentIndent, 0) + explicit
+	}
+
+	var lines []string
+	for !p.eof() {
+		line, _, _ := strings.Cut(p.rest(), "\n")
+		spaces := len(line) - len(strings.TrimLeft(line, " "))
+		if strings.TrimSpace(line) == "" {
+			if indent != -1 && len(line) > indent {
+				lines = append(lines, line[indent:])
+			} else {
+				lines = append(lines, "")
+			}
+			p.advance(len(line) + 1)
+			continue
+		}
+		if indent == -1 {
+			if spaces <= parentIndent {
+				break
+			}
+			indent = spaces
+		}
+		if spaces < indent || indent == 0 && p.atDocumentMarker() {
+			break
+		}
+		lines = append(lines, line[indent:])
+		p.advance(len(line) + 1)
+	}
+	trailing := 0
+	for trailing < len(lines) && strings.TrimSpace(lines[len(lines)-1-trailing]) == "" {
+		trailing++
+	}
+	body := lines[:len(lines)-trailing]
+
+	var text string
+	if literal {
+		text = strings.Join(body, "\n")
+	} else {
+		text = fold(body)
+	}
+
+	switch {
+	case len(body) == 0 && chomp != '+':
+		return "", nil
+	case chomp == '-':
+		return text, nil
+	case chomp == '+':
+		return text + "\n" + strings.Repeat("\n", trailing), nil
+	}
+	return text + "\n", nil
+}
+
+// fold joins the lines of a folded block scalar. Line breaks between text lines become
+// spaces, except around more indented lines, and each empty line becomes a line break.
+func fold(lines []string) string {
+	var sb strings.Builder
+	i := 0
+	for ; i < len(lines) && lines[i] == ""; i++ {
+		sb.WriteByte('\n')
+	}
+	moreIndented := func(s string) bool {
+		return strings.HasPrefix(s, " ") || strings.HasPrefix(s, "\t")
+	}
+	for i < len(lines) {
+		sb.WriteString(lines[i])
+		j := i + 1
+		for j < len(lines) && lines[j] == "" {
+			j++
+		}
+		if j == len(lines) {
+			break
+		}
+
+		empty := j - i - 1
+		switch {
+		case moreIndented(lines[i]) || moreIndented(lines[j]):
+			sb.WriteString(strings.Repeat("\n", empty+1))
+		case empty == 0:
+			sb.WriteByte(' ')
+		default:
+			sb.WriteString(strings.Repeat("\n", empty))
+		}
+		i = j
+	}
+	return sb.String()
+}
+
+// skipFlowSpace moves past white space, line breaks and comments inside a flow collection.
+func (p *parser) skipFlowSpace() {
+	for !p.eof() {
+		switch c := p.peek(0); {
+		case c == ' ' || c == '\t' || c == '\n':
+			p.advance(1)
+		case c == '#' && (p.pos == 0 || isBlank(p.src[p.pos-1])):
+			for !p.eof() && p.peek(0) != '\n' {
+				p.advance(1)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) parseFlowNode() (any, error) {
+	p.skipFlowSpace()
+	anchor, tag, err := p.parseProperties()
+	if err != nil {
+		return nil, err
+	}
+	p.skipFlowSpace()
+
+	var v any
+	switch p.peek(0) {
+	case '[':
+		v, err = p.parseFlowSequence()
+	case '{':
+		v, err = p.parseFlowMapping()
+	case '"', '\'':
+		v, err = p.parseQuoted()
+	case '*':
+		v, err = p.parseAlias()
+	case 0:
+		return nil, p.errorf("the flow collection is not closed")
+	default:
+		v = plainScalar(p.readPlainFlow())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.finish(v, anchor, tag)
+}
+
+// readPlainFlow reads a plain scalar inside a flow collection, folding line breaks.
+func (p *parser) readPlainFlow() string {
+	var words []string
+	start := p.pos
+	for !p.eof() {
+		c := p.peek(0)
+		if isFlowIndicator(c) || c == ':' && (isBlank(p.peek(1)) || isFlowIndicator(p.peek(1))) ||
+			c == '#' && isBlank(p.src[p.pos-1]) {
+			break
+		}
+		if c == '\n' {
+			words = append(words, strings.TrimSpace(p.src[start:p.pos]))
+			p.skipFlowSpace()
+			start = p.pos
+			continue
+		}
+		p.advance(1)
+	}
+	words = append(words, strings.TrimSpace(p.src[start:p.pos]))
+	return strings.TrimSpace(strings.Join(words, " "))
+}
+
+func (p *parser) parseFlowSequence() (any, error) {
+	p.advance(1)
+	l := []any{}
+	for {
+		p.skipFlowSpace()
+		if p.peek(0) == ']' {
+			p.advance(1)
+			return l, nil
+		}
+
+		item, err := p.parseFlowNode()
+		if err != nil {
+			return nil, err
+		}
+		p.skipFlowSpace()
+		if p.peek(0) == ':' {
+			// a single pair mapping such as [a: 1]
+			p.advance(1)
+			v, err := p.parseFlowValue()
+			if err != nil {
+				return nil, err
+			}
+			item = map[string]any{keyString(item): v}
+		}
+		l = append(l, item)
+
+		if err := p.flowSeparator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseFlowMapping() (any, error) {
+	p.advance(1)
+	m := make(map[string]any)
+	for {
+		p.skipFlowSpace()
+		if p.peek(0) == '}' {
+			p.advance(1)
+			return m, nil
+		}
+
+		var key string
+		if c := p.peek(0); c == '"' || c == '\'' {
+			k, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			key = k
+		} else {
+			key = p.readPlainFlow()
+		}
+		if _, dup := m[key]; dup {
+			return nil, p.errorf("the key %q is already defined", key)
+		}
+
+		p.skipFlowSpace()
+		var v any
+		if p.peek(0) == ':' {
+			p.advance(1)
+			var err error
+			if v, err = p.parseFlowValue(); err != nil {
+				return nil, err
+			}
+		}
+		m[key] = v
+
+		if err := p.flowSeparator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseFlowValue parses the value after a colon, which may be left out.
+func (p *parser) parseFlowValue() (any, error) {
+	p.skipFlowSpace()
+	if c := p.peek(0); c == ',' || c == ']' || c == '}' {
+		return nil, nil
+	}
+	return p.parseFlowNode()
+}
+
+func (p *parser) flowSeparator(end byte) error {
+	p.skipFlowSpace()
+	switch p.peek(0) {
+	case ',':
+		p.advance(1)
+		return nil
+	case end:
+		return nil
+	case 0:
+		return p.errorf("the flow collection is not closed")
+	}
+	return p.errorf("expected , or %c, found %q", end, p.currentLine())
+}
+
+func keyString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
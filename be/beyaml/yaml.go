@@ -0,0 +1,33 @@
+package beyaml
+
+import (
+	"io"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be/internal/tree"
+)
+
+var format = tree.Format{Name: "YAML", Map: "a mapping", List: "a sequence", Tag: "yaml", Parse: parse}
+
+// Parsed decodes the YAML into T and runs the matcher on it. Struct fields are matched by
+// their yaml tag or, without one, case-insensitively by name, and time.Duration fields
+// accept strings like "5s".
+func Parsed[T any](matcher expect.Matcher[T]) expect.Matcher[io.Reader] {
+	return tree.Parsed(format, matcher)
+}
+
+// Path decodes the value at the path into T and runs the matcher on it. Paths use the same
+// syntax as bejson.Path: the root $, .key and ['key'] member access, and [n] indexes, i.e.
+// $.servers[0].host or $.labels['app.kubernetes.io/name'].
+//
+//	beyaml.Path("$.server.port", be.Eq(8080))
+func Path[T any](path string, matcher expect.Matcher[T]) expect.Matcher[io.Reader] {
+	return tree.AtPath(format, path, matcher)
+}
+
+// Equivalent checks the YAML holds the same data as the expected document. Comments,
+// key order, quoting and scalar styles are ignored, so `port: 8080` and `"port": 8080.0`
+// are equivalent.
+func Equivalent(expected string) expect.Matcher[io.Reader] {
+	return tree.Equivalent(format, expected)
+}
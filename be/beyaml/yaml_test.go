@@ -0,0 +1,296 @@
+package beyaml_test
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/be/befs"
+	"github.com/jsteenb2/expect/be/beyaml"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+const config = `# service config
+name: todos
+server:
+  host: 0.0.0.0
+  port: 8080
+  timeout: 5s
+replicas: 3
+debug: false
+servers:
+  - host: a.example.com
+    port: 443
+  - host: b.example.com
+    port: 8443
+labels:
+  app.kubernetes.io/name: todos
+`
+
+type Config struct {
+	Name   string `yaml:"name"`
+	Server struct {
+		Host    string        `yaml:"host"`
+		Port    int           `yaml:"port"`
+		Timeout time.Duration `yaml:"timeout"`
+	} `yaml:"server"`
+	Replicas int
+	Debug    bool
+}
+
+func ExampleParsed() {
+	t := &expect.SpyTB{}
+
+	haveReplicas := func(want int) expect.Matcher[Config] {
+		return func(c Config) expect.MatchResult {
+			return expect.MatchResult{
+				Description: fmt.Sprintf("have %d replicas", want),
+				Matches:     c.Replicas == want,
+				But:         fmt.Sprintf("it had %d", c.Replicas),
+				SubjectName: "the config",
+			}
+		}
+	}
+
+	stubFS := fstest.MapFS{"config.yaml": {Data: []byte(config)}}
+	expect.It[fs.FS](t, stubFS).To(befs.FileNamed("config.yaml", beyaml.Parsed(haveReplicas(3))))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleParsed_fail() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader("name: todos\nserver:\n  port: [8080]\n")).To(beyaml.Parsed(be.Eq(Config{Name: "todos"})))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected YAML to be parseable into beyaml_test.Config, but it could not be parsed: cannot decode a sequence into int at $.server.port]
+}
+
+func ExamplePath() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(config)).To(beyaml.Path("$.servers[1].host", be.Eq("b.example.com")))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExamplePath_fail() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(config)).To(beyaml.Path("server.port", be.Eq(9090)))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected YAML to have server.port equal to 9090, but it was 8080]
+}
+
+func ExampleEquivalent() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(config)).To(beyaml.Equivalent(`
+servers: [{host: a.example.com, port: 443}, {host: b.example.com, port: 8443}]
+server: {host: 0.0.0.0, port: 8080, timeout: 5s}
+name: todos
+replicas: 3
+debug: false
+labels: {"app.kubernetes.io/name": todos}
+`))
+	fmt.Printf("%s\n", t)
+	// Output: Test passed
+}
+
+func ExampleEquivalent_fail() {
+	t := &expect.SpyTB{}
+
+	expect.It[io.Reader](t, strings.NewReader(config)).To(beyaml.Equivalent(strings.Replace(config, "8443", "9443", 1)))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected YAML to be equivalent to the expected YAML, but it differed at $.servers[1].port: it was 8443, expected 9443]
+}
+
+func TestParsed(t *testing.T) {
+	t.Run("decodes into go types", func(t *testing.T) {
+		type Server struct {
+			Host string
+			Port uint16
+		}
+		type Doc struct {
+			Config  `yaml:",inline"`
+			Servers []Server          `yaml:"servers"`
+			Labels  map[string]string `yaml:"labels"`
+			Extra   *string           `yaml:"extra"`
+			Ignored string            `yaml:"-"`
+		}
+
+		var got Doc
+		expect.It[io.Reader](t, strings.NewReader(config)).To(beyaml.Parsed(func(d Doc) expect.MatchResult {
+			got = d
+			return expect.MatchResult{Matches: true}
+		}))
+
+		expect.It(t, got.Name).To(be.Eq("todos"))
+		expect.It(t, got.Server.Timeout).To(be.Eq(5 * time.Second))
+		expect.It(t, got.Replicas).To(be.Eq(3))
+		expect.It(t, got.Servers).To(be.ShallowEq([]Server{{"a.example.com", 443}, {"b.example.com", 8443}}))
+		expect.It(t, got.Labels["app.kubernetes.io/name"]).To(be.Eq("todos"))
+		expect.It(t, got.Extra == nil).To(be.Eq(true))
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		for doc, want := range map[string]string{
+			"":                                      "it could not be parsed: the document is empty",
+			"replicas: 3.5":                         "it could not be parsed: cannot decode a float into int at $.replicas",
+			"server:\n  port: 70000000000000000000": "it could not be parsed: cannot decode a float into int at $.server.port",
+			"server:\n  timeout: soon":              `it could not be parsed: cannot decode a string into time.Duration at $.server.timeout: time: invalid duration "soon"`,
+			"- a\n- b":                              "it could not be parsed: cannot decode a sequence into beyaml_test.Config",
+			"name: [a":                              "it could not be parsed: line 1: the flow collection is not closed",
+		} {
+			spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(doc), beyaml.Parsed(be.Eq(Config{})), want)
+		}
+	})
+}
+
+func TestPath(t *testing.T) {
+	expect.It[io.Reader](t, strings.NewReader(config)).To(beyaml.Path("$.labels['app.kubernetes.io/name']", be.Eq("todos")))
+	expect.It[io.Reader](t, strings.NewReader(config)).To(beyaml.Path(`labels["app.kubernetes.io/name"]`, be.Eq("todos")))
+	expect.It[io.Reader](t, strings.NewReader(config)).To(beyaml.Path("server.timeout", be.Eq(5*time.Second)))
+	expect.It[io.Reader](t, strings.NewReader(config)).To(beyaml.Path("servers", be.Size[any](be.Eq(2))))
+
+	for path, want := range map[string]string{
+		"server.tls":      "expected YAML to have server.tls, but it had nothing at $.server.tls: $.server had no key tls",
+		"servers[2].host": "expected YAML to have servers[2].host, but it had nothing at $.servers[2].host: $.servers had 2 items",
+		"name.first":      `expected YAML to have name.first, but it had nothing at $.name.first: $.name was "todos", not a mapping`,
+		"replicas[0]":     "expected YAML to have replicas[0], but it had nothing at $.replicas[0]: $.replicas was 3, not a sequence",
+		"servers[0]":      "expected YAML to have servers[0], but cannot decode a mapping into string at $.servers[0]",
+		"servers[x]":      `expected YAML to have servers[x], but the path "servers[x]" is invalid: "x" is not an index`,
+		"server..port":    `expected YAML to have server..port, but the path "server..port" is invalid: expected a key after the . at 6`,
+		"server.host":     `expected YAML to have server.host equal to "localhost", but it was "0.0.0.0"`,
+	} {
+		spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(config), beyaml.Path(path, be.Eq("localhost")), want)
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+		want     string
+	}{
+		{name: "scalar", got: "a: 1", expected: "a: 2", want: "it differed at $.a: it was 1, expected 2"},
+		{name: "type", got: "a: '1'", expected: "a: 1", want: `it differed at $.a: it was "1", expected 1`},
+		{name: "missing key", got: "a: 1", expected: "{a: 1, b: 2}", want: "key b was missing"},
+		{name: "extra key", got: "a: {b: 1, c: 2}", expected: "a: {b: 1}", want: "it differed at $.a: key c was not expected"},
+		{name: "length", got: "[1, 2, 3]", expected: "[1, 2]", want: "it had 3 items, expected 2"},
+		{name: "kind", got: "a: [1]", expected: "a: {b: 1}", want: "it differed at $.a: it was a sequence, expected a mapping"},
+		{name: "invalid expected", got: "a: 1", expected: "a: [", want: "the expected YAML could not be parsed: line 1: the flow collection is not closed"},
+		{name: "invalid", got: "a:\n  b: 1\n c: 2", expected: "a: 1", want: `it could not be parsed: line 3: unexpected indentation of "c: 2"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(tt.got), beyaml.Equivalent(tt.expected),
+				"expected YAML to be equivalent to the expected YAML, but "+tt.want,
+			)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		json string
+	}{
+		{
+			name: "nested block collections",
+			yaml: "a:\n  b:\n    - 1\n    - - 2\n      - 3\n    - c: 4\n      d: 5\nlist:\n- x\n- y\n",
+			json: `{"a": {"b": [1, [2, 3], {"c": 4, "d": 5}]}, "list": ["x", "y"]}`,
+		},
+		{
+			name: "core schema",
+			yaml: "n: ~\ne:\nt: True\nf: false\ni: -12\nh: 0x1F\no: 0o17\nfl: 1.5e3\ninf: -.inf\ns: 1.2.3\nq: '12'\n",
+			json: `{"n": null, "e": null, "t": true, "f": false, "i": -12, "h": 31, "o": 15, "fl": 1500, "inf": -.inf, "s": "1.2.3", "q": "12"}`,
+		},
+		{
+			name: "quoted scalars",
+			yaml: `a: "tab\there \"q\" \u00e9 \x41"` + "\nb: 'it''s # not a comment'\nc: \"folded\n  line\n\n  para\"\n",
+			json: `{"a": "tab\there \"q\" é A", "b": "it's # not a comment", "c": "folded line\npara"}`,
+		},
+		{
+			name: "plain scalars",
+			yaml: "url: http://example.com:8080/x # comment\nmulti: one\n  two\n\n  three\nport: 8080:80\nhash: a#b\n",
+			json: `{"url": "http://example.com:8080/x", "multi": "one two\nthree", "port": "8080:80", "hash": "a#b"}`,
+		},
+		{
+			name: "literal block scalars",
+			yaml: "keep: |+\n  a\n   b\n\nclip: |\n  a\n\n\nstrip: |-\n  a\nindent: |2\n   x\n  y\n",
+			json: `{"keep": "a\n b\n\n", "clip": "a\n", "strip": "a", "indent": " x\ny\n"}`,
+		},
+		{
+			name: "folded block scalars",
+			yaml: "f: >\n  one\n  two\n\n  three\n    more\n  four\n",
+			json: `{"f": "one two\nthree\n  more\nfour\n"}`,
+		},
+		{
+			name: "flow collections",
+			yaml: "a: [1, 'two', {three: 3}, [four], ]\nb: {x: 1,\n  y: [2,\n    3]}\nc: [k: v]\nd: {e}\n",
+			json: `{"a": [1, "two", {"three": 3}, ["four"]], "b": {"x": 1, "y": [2, 3]}, "c": [{"k": "v"}], "d": {"e": null}}`,
+		},
+		{
+			name: "anchors, aliases and merge keys",
+			yaml: "base: &base\n  a: 1\n  b: 2\nother: &other {c: 3}\nderived:\n  <<: [*base, *other]\n  b: 20\ncopy: *base\nlist: [&x 1, *x]\n",
+			json: `{"base": {"a": 1, "b": 2}, "other": {"c": 3}, "derived": {"a": 1, "b": 20, "c": 3}, "copy": {"a": 1, "b": 2}, "list": [1, 1]}`,
+		},
+		{
+			name: "tags",
+			yaml: "s: !!str 123\ni: !!int \"7\"\nf: !!float 1\nlocal: !thing x\n",
+			json: `{"s": "123", "i": 7, "f": 1.0, "local": "x"}`,
+		},
+		{
+			name: "documents and comments",
+			yaml: "%YAML 1.2\n# leading\n---\n# inside\n- a # trailing\n\n-   b\n...\n# after\n",
+			json: `["a", "b"]`,
+		},
+		{
+			name: "quoted and numeric keys",
+			yaml: "\"a: b\": 1\n'c': 2\n3: three\n",
+			json: `{"a: b": 1, "c": 2, "3": "three"}`,
+		},
+		{
+			name: "scalar document",
+			yaml: "--- >\n  hello\n  world\n",
+			json: `"hello world\n"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect.It[io.Reader](t, strings.NewReader(tt.yaml)).To(beyaml.Equivalent(tt.json))
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		for doc, want := range map[string]string{
+			"a: 1\na: 2":        `line 2: the key "a" is already defined`,
+			"a:\n\t- 1":         "line 2: tabs are not allowed for indentation",
+			"a: *nope":          "line 1: the alias *nope refers to an unknown anchor",
+			"a: 1\n---\nb: 2":   "line 2: the stream has more than one document",
+			"a: \"open":         "line 1: the quoted string is not closed",
+			"a: \"bad \\q\"":    `line 1: invalid escape \q`,
+			"a: !!int abc":      "line 1: the value abc cannot be tagged !!int",
+			"a: 'x' y":          `line 1: unexpected "y" after the value`,
+			"- a\nb: 1":         `line 2: unexpected "b: 1"`,
+			"? complex\n: key":  "line 1: explicit mapping keys are not supported",
+			"a:\n  - 1\n  b: 2": `line 3: unexpected indentation of "b: 2"`,
+			"<<: 1":             "line 1: the merge key << must refer to a mapping",
+			"\ta: 1":            "line 1: tabs are not allowed for indentation",
+			"- a\n\t- b":        "line 2: tabs are not allowed for indentation",
+			"a: b\n  c: d":      `line 2: unexpected mapping key "c: d" in a multi-line scalar`,
+			"a:\n  b\n  c: d":   `line 3: unexpected mapping key "c: d" in a multi-line scalar`,
+		} {
+			spytb.VerifyFailingMatcher[io.Reader](t, strings.NewReader(doc), beyaml.Equivalent("a: 1"), "it could not be parsed: "+want)
+		}
+	})
+}
@@ -10,21 +10,23 @@ import (
 // Eq checks if a value is equal to another value.
 func Eq[T comparable](expected T) expect.Matcher[T] {
 	return func(got T) expect.MatchResult {
-		description := fmt.Sprintf("be equal to %+v", expected)
+		want := fmt.Sprintf("%+v", expected)
 		but := fmt.Sprintf("it was %v", got)
 		subject := ""
 		
 		if str, isStr := any(got).(string); isStr {
-			description = fmt.Sprintf("be equal to %q", any(expected).(string))
+			want = fmt.Sprintf("%q", any(expected).(string))
 			but = fmt.Sprintf("it was %q", str)
 			subject = fmt.Sprintf("%q", str)
 		}
 		
 		return expect.MatchResult{
-			Description: description,
-			Matches:     got == expected,
-			But:         but,
-			SubjectName: subject,
+			Description:        "be equal to " + want,
+			Matches:            got == expected,
+			But:                but,
+			SubjectName:        subject,
+			NegatedDescription: "not be equal to " + want,
+			NegatedBut:         "it was equal to " + want,
 		}
 	}
 }
@@ -33,9 +35,11 @@ func Eq[T comparable](expected T) expect.Matcher[T] {
 func Less[T cmp.Ordered](in T) expect.Matcher[T] {
 	return func(got T) expect.MatchResult {
 		return expect.MatchResult{
-			Description: fmt.Sprintf("be less than %v", in),
-			Matches:     got < in,
-			But:         fmt.Sprintf("it was %v", got),
+			Description:        fmt.Sprintf("be less than %v", in),
+			Matches:            got < in,
+			But:                fmt.Sprintf("it was %v", got),
+			NegatedDescription: fmt.Sprintf("be at least %v", in),
+			NegatedBut:         fmt.Sprintf("it was %v", got),
 		}
 	}
 }
@@ -44,9 +48,11 @@ func Less[T cmp.Ordered](in T) expect.Matcher[T] {
 func Greater[T cmp.Ordered](in T) expect.Matcher[T] {
 	return func(got T) expect.MatchResult {
 		return expect.MatchResult{
-			Description: fmt.Sprintf("be greater than %v", in),
-			Matches:     got > in,
-			But:         fmt.Sprintf("it was %v", got),
+			Description:        fmt.Sprintf("be greater than %v", in),
+			Matches:            got > in,
+			But:                fmt.Sprintf("it was %v", got),
+			NegatedDescription: fmt.Sprintf("be at most %v", in),
+			NegatedBut:         fmt.Sprintf("it was %v", got),
 		}
 	}
 }
@@ -0,0 +1,257 @@
+package tree
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	textUnmarshalType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Decode stores the value in the thing target points to. Struct fields are matched by
+// the format's struct tag or, without one, case-insensitively by name. Keys without a
+// field are ignored. The path locates v in its document for error messages.
+func Decode(v any, target any, p Path, f Format) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("cannot decode into %T, it must be a non-nil pointer", target)
+	}
+	return decoder{format: f}.decode(v, rv.Elem(), p)
+}
+
+type decoder struct {
+	format Format
+}
+
+func (d decoder) decode(v any, rv reflect.Value, p Path) error {
+	mismatch := func() error {
+		at := ""
+		if len(p) > 0 {
+			at = " at " + p.String()
+		}
+		return fmt.Errorf("cannot decode %s into %s%s", kind(v, d.format), rv.Type(), at)
+	}
+
+	if rv.Kind() == reflect.Pointer {
+		if v == nil {
+			rv.SetZero()
+			return nil
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return d.decode(v, rv.Elem(), p)
+	}
+	if v == nil {
+		rv.SetZero()
+		return nil
+	}
+
+	switch rv.Type() {
+	case timeType:
+		t, ok := v.(time.Time)
+		if !ok {
+			break
+		}
+		rv.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		switch v := v.(type) {
+		case string:
+			dur, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("%w: %v", mismatch(), err)
+			}
+			rv.SetInt(int64(dur))
+			return nil
+		case int64:
+			rv.SetInt(v)
+			return nil
+		}
+		return mismatch()
+	}
+
+	if s, ok := v.(string); ok && reflect.PointerTo(rv.Type()).Implements(textUnmarshalType) {
+		if err := rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("%w: %v", mismatch(), err)
+		}
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() > 0 {
+			return mismatch()
+		}
+		rv.Set(reflect.ValueOf(v))
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return mismatch()
+		}
+		rv.SetBool(b)
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return mismatch()
+		}
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := integer(v)
+		if !ok || rv.OverflowInt(n) {
+			return mismatch()
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := integer(v)
+		if !ok || n < 0 || rv.OverflowUint(uint64(n)) {
+			return mismatch()
+		}
+		rv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		n, ok := number(v)
+		if !ok {
+			return mismatch()
+		}
+		rv.SetFloat(n)
+	case reflect.Slice:
+		l, ok := v.([]any)
+		if !ok {
+			return mismatch()
+		}
+		s := reflect.MakeSlice(rv.Type(), len(l), len(l))
+		for i, item := range l {
+			if err := d.decode(item, s.Index(i), p.with(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+	case reflect.Array:
+		l, ok := v.([]any)
+		if !ok || len(l) > rv.Len() {
+			return mismatch()
+		}
+		rv.SetZero()
+		for i, item := range l {
+			if err := d.decode(item, rv.Index(i), p.with(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(m)))
+		}
+		for k, item := range m {
+			key, err := mapKey(k, rv.Type().Key())
+			if err != nil {
+				return fmt.Errorf("cannot decode key %s into %s at %s: %v", keyName(k), rv.Type().Key(), p.name(), err)
+			}
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := d.decode(item, elem, p.with(k)); err != nil {
+				return err
+			}
+			rv.SetMapIndex(key, elem)
+		}
+	case reflect.Struct:
+		m, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		for k, item := range m {
+			field, ok := d.field(rv, k)
+			if !ok {
+				continue
+			}
+			if err := d.decode(item, field, p.with(k)); err != nil {
+				return err
+			}
+		}
+	default:
+		return mismatch()
+	}
+	return nil
+}
+
+// field finds the struct field for the key, looking into embedded structs.
+func (d decoder) field(rv reflect.Value, key string) (reflect.Value, bool) {
+	var fold reflect.Value
+	for i := range rv.NumField() {
+		sf := rv.Type().Field(i)
+		tag := sf.Tag.Get(d.format.Tag)
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			if f, ok := d.field(rv.Field(i), key); ok {
+				return f, true
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		switch {
+		case name != "":
+			if name == key {
+				return rv.Field(i), true
+			}
+		case sf.Name == key:
+			return rv.Field(i), true
+		case !fold.IsValid() && strings.EqualFold(sf.Name, key):
+			fold = rv.Field(i)
+		}
+	}
+	return fold, fold.IsValid()
+}
+
+func mapKey(k string, t reflect.Type) (reflect.Value, error) {
+	key := reflect.New(t).Elem()
+	if reflect.PointerTo(t).Implements(textUnmarshalType) {
+		err := key.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(k))
+		return key, err
+	}
+	switch t.Kind() {
+	case reflect.String:
+		key.SetString(k)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(k, 10, t.Bits())
+		if err != nil {
+			return key, err
+		}
+		key.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(k, 10, t.Bits())
+		if err != nil {
+			return key, err
+		}
+		key.SetUint(n)
+	default:
+		return key, fmt.Errorf("unsupported key type")
+	}
+	return key, nil
+}
+
+func integer(v any) (int64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
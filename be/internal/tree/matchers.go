@@ -0,0 +1,96 @@
+package tree
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+// Parsed decodes the document into T and runs the matcher on it.
+func Parsed[T any](f Format, matcher expect.Matcher[T]) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		var thing T
+		doc, err := f.Parse(rdr)
+		if err == nil {
+			err = Decode(doc, &thing, nil, f)
+		}
+		if err != nil {
+			return expect.MatchResult{
+				Description: fmt.Sprintf("be parseable into %T", thing),
+				SubjectName: f.Name,
+				Matches:     false,
+				But:         fmt.Sprintf("it could not be parsed: %v", err),
+			}
+		}
+		return matcher(thing)
+	}
+}
+
+// AtPath decodes the value at the path into T and runs the matcher on it, see ParsePath.
+func AtPath[T any](f Format, path string, matcher expect.Matcher[T]) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: fmt.Sprintf("have %s", path),
+			Matches:     false,
+			SubjectName: f.Name,
+		}
+
+		p, err := ParsePath(path)
+		if err != nil {
+			result.But = err.Error()
+			return result
+		}
+		doc, err := f.Parse(rdr)
+		if err != nil {
+			result.But = fmt.Sprintf("it could not be parsed: %v", err)
+			return result
+		}
+		v, err := Lookup(doc, p, f)
+		if err != nil {
+			result.But = fmt.Sprintf("it had nothing at %s: %v", p, err)
+			return result
+		}
+		var thing T
+		if err := Decode(v, &thing, p, f); err != nil {
+			result.But = err.Error()
+			return result
+		}
+
+		r := matcher(thing)
+		r = r.WithDescription(fmt.Sprintf("have %s %s", path, be.Complement(r)))
+		r.SubjectName = f.Name
+		return r
+	}
+}
+
+// Equivalent checks the document holds the same data as the expected one, see Diff.
+func Equivalent(f Format, expected string) expect.Matcher[io.Reader] {
+	return func(rdr io.Reader) expect.MatchResult {
+		result := expect.MatchResult{
+			Description: "be equivalent to the expected " + f.Name,
+			Matches:     false,
+			SubjectName: f.Name,
+		}
+
+		want, err := f.Parse(strings.NewReader(expected))
+		if err != nil {
+			result.But = fmt.Sprintf("the expected %s could not be parsed: %v", f.Name, err)
+			return result
+		}
+		got, err := f.Parse(rdr)
+		if err != nil {
+			result.But = fmt.Sprintf("it could not be parsed: %v", err)
+			return result
+		}
+
+		if diff := Diff(got, want, f); diff != "" {
+			result.But = diff
+			return result
+		}
+		result.Matches = true
+		return result
+	}
+}
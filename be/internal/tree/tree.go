@@ -0,0 +1,307 @@
+package tree
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Format names the parts of a document in messages, with their article, and the struct
+// tag used to decode it. Documents are made of map[string]any, []any, string, int64, float64, bool, time.Time
+// and nil values, which is what the YAML and TOML parsers produce. Name and Parse are used
+// by the matchers, see Parsed.
+type Format struct {
+	Name  string
+	Map   string
+	List  string
+	Tag   string
+	Parse func(io.Reader) (any, error)
+}
+
+// Path is a location within a document, made of string keys and int indexes.
+type Path []any
+
+// ParsePath parses a path in the subset of JSONPath shared by bejson, beyaml and betoml: the
+// root $, .key and ['key'] member access, and [n] indexes, i.e. $.servers[0].host or
+// $.labels['app.kubernetes.io/name']. Keys may be double quoted too, and the leading $. may
+// be left out, i.e. servers[0].host. The empty path and $ are the whole document.
+func ParsePath(s string) (Path, error) {
+	invalid := func(format string, args ...any) (Path, error) {
+		return nil, fmt.Errorf("the path %q is invalid: %s", s, fmt.Sprintf(format, args...))
+	}
+
+	start := 0
+	if strings.HasPrefix(s, "$") {
+		start = 1
+	}
+	var p Path
+	for i := start; i < len(s); {
+		switch s[i] {
+		case '[':
+			rest := s[i+1:]
+			end := strings.IndexByte(rest, ']')
+			switch {
+			case strings.HasPrefix(rest, `"`):
+				quoted, err := strconv.QuotedPrefix(rest)
+				if err != nil {
+					return invalid("the key at %d is not a valid quoted string", i)
+				}
+				key, _ := strconv.Unquote(quoted)
+				if !strings.HasPrefix(rest[len(quoted):], "]") {
+					return invalid("expected ] after %s", quoted)
+				}
+				p = append(p, key)
+				i += 1 + len(quoted) + 1
+				continue
+			case strings.HasPrefix(rest, "'"):
+				end := strings.Index(rest[1:], "']")
+				if end == -1 {
+					return invalid("the key at %d is not closed with ']", i)
+				}
+				p = append(p, rest[1:1+end])
+				i += 1 + 1 + end + 2
+				continue
+			}
+			if end == -1 {
+				return invalid("the [ at %d is not closed", i)
+			}
+			n, err := strconv.Atoi(rest[:end])
+			if err != nil || n < 0 {
+				return invalid("%q is not an index", rest[:end])
+			}
+			p = append(p, n)
+			i += 1 + end + 1
+		case '.':
+			if i == len(s)-1 || s[i+1] == '.' || s[i+1] == '[' {
+				return invalid("expected a key after the . at %d", i)
+			}
+			i++
+		default:
+			if i > 0 && s[i-1] != '.' {
+				return invalid("expected . or [ at %d", i)
+			}
+			end := strings.IndexAny(s[i:], ".[")
+			if end == -1 {
+				end = len(s) - i
+			}
+			p = append(p, s[i:i+end])
+			i += end
+		}
+	}
+	return p, nil
+}
+
+// String renders the path as ParsePath reads it, i.e. $.servers[0].host.
+func (p Path) String() string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, seg := range p {
+		switch seg := seg.(type) {
+		case int:
+			fmt.Fprintf(&sb, "[%d]", seg)
+		case string:
+			switch {
+			case isBareKey(seg):
+				sb.WriteString("." + seg)
+			case strings.Contains(seg, "'"):
+				fmt.Fprintf(&sb, "[%q]", seg)
+			default:
+				sb.WriteString("['" + seg + "']")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// name is the path in a sentence, the root being the document itself.
+func (p Path) name() string {
+	if len(p) == 0 {
+		return "the document"
+	}
+	return p.String()
+}
+
+func (p Path) with(seg any) Path {
+	return append(p[:len(p):len(p)], seg)
+}
+
+// keyName is a key in a sentence, quoted unless it is a plain word.
+func keyName(k string) string {
+	if isBareKey(k) {
+		return k
+	}
+	return strconv.Quote(k)
+}
+
+func isBareKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup returns the value at the path.
+func Lookup(v any, p Path, f Format) (any, error) {
+	for i, seg := range p {
+		parent := p[:i]
+		switch seg := seg.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s was %s, not %s", parent.name(), Describe(v, f), f.Map)
+			}
+			next, ok := m[seg]
+			if !ok {
+				return nil, fmt.Errorf("%s had no key %s", parent.name(), keyName(seg))
+			}
+			v = next
+		case int:
+			l, ok := v.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s was %s, not %s", parent.name(), Describe(v, f), f.List)
+			}
+			if seg >= len(l) {
+				return nil, fmt.Errorf("%s had %d items", parent.name(), len(l))
+			}
+			v = l[seg]
+		}
+	}
+	return v, nil
+}
+
+// Describe renders a value for a message. Scalars are shown as they are, while maps and
+// lists are named by their kind.
+func Describe(v any, f Format) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case map[string]any:
+		return f.Map
+	case []any:
+		return f.List
+	}
+	return fmt.Sprint(v)
+}
+
+// kind names the type of a value.
+func kind(v any, f Format) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case int64:
+		return "an integer"
+	case float64:
+		return "a float"
+	case bool:
+		return "a boolean"
+	case time.Time:
+		return "a date-time"
+	case map[string]any:
+		return f.Map
+	case []any:
+		return f.List
+	}
+	return fmt.Sprintf("a %T", v)
+}
+
+// Diff describes the first difference between the documents, or returns "" when they
+// are equal. Keys are compared in sorted order and numbers are compared by value, so 1
+// and 1.0 are equal.
+func Diff(got, want any, f Format) string {
+	return diff(got, want, nil, f)
+}
+
+func diff(got, want any, p Path, f Format) string {
+	differed := func(format string, args ...any) string {
+		msg := fmt.Sprintf(format, args...)
+		if len(p) == 0 {
+			return msg
+		}
+		return fmt.Sprintf("it differed at %s: %s", p, msg)
+	}
+
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return differed("it was %s, expected %s", Describe(got, f), Describe(want, f))
+		}
+		for _, k := range slices.Sorted(maps.Keys(w)) {
+			gv, ok := g[k]
+			if !ok {
+				return differed("key %s was missing", keyName(k))
+			}
+			if d := diff(gv, w[k], p.with(k), f); d != "" {
+				return d
+			}
+		}
+		for _, k := range slices.Sorted(maps.Keys(g)) {
+			if _, ok := w[k]; !ok {
+				return differed("key %s was not expected", keyName(k))
+			}
+		}
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			return differed("it was %s, expected %s", Describe(got, f), Describe(want, f))
+		}
+		for i := range min(len(g), len(w)) {
+			if d := diff(g[i], w[i], p.with(i), f); d != "" {
+				return d
+			}
+		}
+		if len(g) != len(w) {
+			return differed("it had %d items, expected %d", len(g), len(w))
+		}
+	default:
+		if !scalarEqual(got, want) {
+			return differed("it was %s, expected %s", Describe(got, f), Describe(want, f))
+		}
+	}
+	return ""
+}
+
+func scalarEqual(got, want any) bool {
+	if g, ok := number(got); ok {
+		w, ok := number(want)
+		return ok && (g == w || math.IsNaN(g) && math.IsNaN(w))
+	}
+	if g, ok := got.(time.Time); ok {
+		w, ok := want.(time.Time)
+		return ok && g.Equal(w)
+	}
+	return got == want
+}
+
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -16,7 +16,7 @@ func Key[K comparable, V any](key K, valueMatcher expect.Matcher[V]) expect.Matc
 		}
 		
 		result := valueMatcher(value)
-		result.Description = fmt.Sprintf("have key %v with value %v", key, result.Description)
+		result = result.WithDescription(fmt.Sprintf("have key %v with value %v", key, Complement(result)))
 		result.SubjectName = fmt.Sprintf("%+v", m)
 		return result
 	}
@@ -24,7 +24,7 @@ func ExampleKey_fail() {
 	expect.It(t, map[string]int{"score": 4}).To(be.Key("score", be.Greater(5).And(be.Less(10))))
 
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected map[score:4] to have key score with value be greater than 5 and be less than 10, but it was 4]
+	// Output: Test failed: [expected map[score:4] to have key score with value greater than 5 and less than 10, but it was 4]
 }
 
 func ExampleKey() {
@@ -59,7 +59,7 @@ func TestMapMatching(t *testing.T) {
 				t,
 				map[string]string{"hello": "world"},
 				be.Key("hello", be.Eq("goodbye")),
-				`expected map[hello:world] to have key hello with value be equal to "goodbye", but it was "world"`,
+				`expected map[hello:world] to have key hello with value equal to "goodbye", but it was "world"`,
 			)
 		})
 	})
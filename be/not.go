@@ -1,10 +1,14 @@
 package be
 
 import (
+	"strings"
+
 	"github.com/jsteenb2/expect"
 )
 
-// Not is a helper function to negate a matcher.
+// Not is a helper function to negate a matcher. When the matcher matches, the failure says
+// why in the positive, i.e. "expected HELLO to not be in all caps, but it was in all caps".
+// Matchers can word their negation with MatchResult.NegatedDescription and NegatedBut.
 func Not[T any](matcher expect.Matcher[T]) expect.Matcher[T] {
 	return negate(matcher)
 }
@@ -12,9 +16,90 @@ func Not[T any](matcher expect.Matcher[T]) expect.Matcher[T] {
 func negate[T any](matcher expect.Matcher[T]) expect.Matcher[T] {
 	return func(got T) expect.MatchResult {
 		result := matcher(got)
-		return expect.MatchResult{
-			Description: "not " + result.Description,
+
+		description := result.NegatedDescription
+		if description == "" {
+			description = "not " + result.Description
+		}
+		but := result.NegatedBut
+		if but == "" {
+			but = positive(result)
+		}
+
+		tree := &expect.DescriptionNode{
+			Kind:        expect.NotNode,
+			Description: description,
 			Matches:     !result.Matches,
+			But:         but,
+			Children:    []*expect.DescriptionNode{result.DescriptionTree()},
+		}
+		return expect.MatchResult{
+			Description:        description,
+			Matches:            !result.Matches,
+			But:                but,
+			SubjectName:        result.SubjectName,
+			Tree:               tree.WithActual(got),
+			NegatedDescription: result.Description,
+			NegatedBut:         result.But,
+			Detail:             result.Detail,
+		}
+	}
+}
+
+// positive explains a match from its description, i.e. "be in all caps" becomes "it was in
+// all caps".
+func positive(result expect.MatchResult) string {
+	for _, verb := range [][2]string{{"be ", "it was "}, {"have ", "it had "}, {"contain ", "it contained "}} {
+		if rest, ok := strings.CutPrefix(result.Description, verb[0]); ok {
+			return verb[1] + rest
+		}
+	}
+	if result.But != "" {
+		return "it matched: " + result.But
+	}
+	return "it matched"
+}
+
+// Complement turns the description of a result to follow a noun, i.e. "have length " +
+// Complement(be.Eq(5)(n)) reads "have length equal to 5". It works through the description
+// tree, only rewording the leading verb of each matcher, so the values described are left
+// alone: "be " is dropped, "have " becomes "with " and "contain " becomes "containing ".
+func Complement(result expect.MatchResult) string {
+	return complementNode(result.DescriptionTree())
+}
+
+func complementNode(n *expect.DescriptionNode) string {
+	switch n.Kind {
+	case expect.AllNode, expect.AnyNode:
+		joiner := " and "
+		if n.Kind == expect.AnyNode {
+			joiner = " or "
+		}
+		parts := make([]string, 0, len(n.Children))
+		for _, child := range n.Children {
+			parts = append(parts, complementNested(child))
 		}
+		return strings.Join(parts, joiner)
+	case expect.NotNode:
+		if len(n.Children) == 1 && (n.Children[0].Kind == expect.AllNode || n.Children[0].Kind == expect.AnyNode) {
+			return "not (" + complementNode(n.Children[0]) + ")"
+		}
+	}
+	for _, verb := range [][2]string{
+		{"be ", ""}, {"not be ", "not "},
+		{"have ", "with "}, {"not have ", "without "},
+		{"contain ", "containing "}, {"not contain ", "not containing "},
+	} {
+		if rest, ok := strings.CutPrefix(n.Description, verb[0]); ok {
+			return verb[1] + rest
+		}
+	}
+	return n.Description
+}
+
+func complementNested(n *expect.DescriptionNode) string {
+	if (n.Kind == expect.AllNode || n.Kind == expect.AnyNode) && len(n.Children) > 1 {
+		return "(" + complementNode(n) + ")"
 	}
+	return complementNode(n)
 }
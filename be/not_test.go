@@ -0,0 +1,103 @@
+package be_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func ExampleNot() {
+	t := &expect.SpyTB{}
+
+	expect.It(t, "HELLO").To(be.Not(be.AllCaps))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected HELLO to not be in all caps, but it was in all caps]
+}
+
+func ExampleNot_negatedDescription() {
+	t := &expect.SpyTB{}
+
+	expect.It(t, 11).To(be.Not(be.Greater(10)))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected 11 to be at most 10, but it was 11]
+}
+
+func TestNot(t *testing.T) {
+	t.Run("passing", func(t *testing.T) {
+		expect.It(t, "hello").To(
+			be.Not(be.AllCaps),
+			be.Not(be.Eq("goodbye")),
+			be.Not(be.Not(be.Substring("ell"))),
+		)
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, "hello", be.Not(be.Eq("hello")),
+			`expected "hello" to not be equal to "hello", but it was equal to "hello"`,
+		)
+		spytb.VerifyFailingMatcher(t, "hello", be.Not(be.Substring("ell")),
+			`expected hello to not contain "ell", but it contained "ell"`,
+		)
+		spytb.VerifyFailingMatcher(t, 3, be.Not(be.Less(5)),
+			`expected 3 to be at least 5, but it was 3`,
+		)
+		spytb.VerifyFailingMatcher(t, "hello", be.Len(be.Not(be.Eq(5))),
+			`expected hello to have length not equal to 5, but it was equal to 5`,
+		)
+		spytb.VerifyFailingMatcher(t, []string{"a", "b"}, be.Not(be.Size[string](be.Eq(2))),
+			`expected [a b] to not have a size equal to 2, but it had a size equal to 2`,
+		)
+	})
+
+	t.Run("wrapped and composed matchers", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, map[string]string{"k": "x and be y"}, be.Not(be.Key("k", be.Eq("x and be y"))),
+			`expected map[k:x and be y] to not have key k with value equal to "x and be y", but it had key k with value equal to "x and be y"`,
+		)
+		spytb.VerifyFailingMatcher(t, "hi", be.Not(be.Len(be.Less(5))),
+			`expected hi to not have length less than 5, but it had length less than 5`,
+		)
+		spytb.VerifyFailingMatcher(t, 2, be.Not(be.Greater(1).And(be.Less(3))),
+			`expected 2 to not (be greater than 1 and be less than 3), but it matched: be greater than 1 and be less than 3`,
+		)
+		spytb.VerifyFailingMatcher(t, "hello", be.Len(be.Greater(1).And(be.Not(be.Eq(5)))),
+			`expected hello to have length greater than 1 and not equal to 5, but it was equal to 5`,
+		)
+	})
+
+	t.Run("double negation reads as the original", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, "hello", be.Not(be.Not(be.AllCaps)),
+			`expected hello to be in all caps, but it was not in all caps`,
+		)
+	})
+
+	t.Run("without a recognisable description the but gives the detail", func(t *testing.T) {
+		exceedLimit := func(n int) expect.MatchResult {
+			return expect.MatchResult{Description: "exceed the limit", Matches: n > 2, But: fmt.Sprintf("there were %d", n)}
+		}
+
+		spytb.VerifyFailingMatcher(t, 3, be.Not(exceedLimit),
+			`expected 3 to not exceed the limit, but it matched: there were 3`,
+		)
+	})
+}
+
+func TestComplement(t *testing.T) {
+	for description, tc := range map[string]struct {
+		result expect.MatchResult
+		want   string
+	}{
+		"drops be":                   {be.Eq(5)(4), "equal to 5"},
+		"keeps not":                  {be.Not(be.Eq(5))(4), "not equal to 5"},
+		"have becomes with":          {be.Size[int](be.Eq(2))(nil), "with a size equal to 2"},
+		"not have becomes without":   {be.Not(be.Size[int](be.Eq(2)))(nil), "without a size equal to 2"},
+		"contain becomes containing": {be.Substring("ell")("hello"), `containing "ell"`},
+		"composed":                   {be.Greater(1).And(be.Not(be.Eq(3)))(3), "greater than 1 and not equal to 3"},
+	} {
+		t.Run(description, func(t *testing.T) {
+			expect.It(t, be.Complement(tc.result)).To(be.Eq(tc.want))
+		})
+	}
+}
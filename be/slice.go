@@ -17,7 +17,7 @@ var passingResult = expect.MatchResult{
 func Size[T any](matcher expect.Matcher[int]) expect.Matcher[[]T] {
 	return func(items []T) expect.MatchResult {
 		result := matcher(len(items))
-		result.Description = "have a size " + result.Description
+		result = result.WithDescription("have a size " + Complement(result))
 		return result
 	}
 }
@@ -39,8 +39,8 @@ func ContainingItem[T any](m expect.Matcher[T]) expect.Matcher[[]T] {
 			}
 		}
 		
+		exampleFailure = exampleFailure.WithDescription("contain an item " + Complement(exampleFailure))
 		exampleFailure.But = "it did not"
-		exampleFailure.Description = "contain an item " + exampleFailure.Description
 		exampleFailure.SubjectName = fmt.Sprintf("%+v", items)
 		
 		return exampleFailure
@@ -78,7 +78,7 @@ func ShallowEq[T comparable](other []T) expect.Matcher[[]T] {
 
 func everyItemFailure(result expect.MatchResult) expect.MatchResult {
 	return expect.MatchResult{
-		Description: "have every item " + result.Description,
+		Description: "have every item " + Complement(result),
 		Matches:     false,
 		But:         result.But,
 	}
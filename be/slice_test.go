@@ -66,7 +66,7 @@ func ExampleSize_fail() {
 	expect.It(t, anArray).To(be.Size[string](be.Eq(3)))
 
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected [hello world] to have a size be equal to 3, but it was 2]
+	// Output: Test failed: [expected [hello world] to have a size equal to 3, but it was 2]
 }
 
 func ExampleEveryItem() {
@@ -86,7 +86,7 @@ func ExampleEveryItem_fail() {
 	expect.It(t, anArray).To(be.EveryItem(be.Substring("h")))
 
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected [hello world] to have every item contain "h"]
+	// Output: Test failed: [expected [hello world] to have every item containing "h", but it was "world"]
 }
 
 func TestArrayMatchers(t *testing.T) {
@@ -101,7 +101,7 @@ func TestArrayMatchers(t *testing.T) {
 					t,
 					[]string{"hello", "world"},
 					be.ContainingItem(be.Eq("goodbye")),
-					`expected [hello world] to contain an item be equal to "goodbye", but it did not`,
+					`expected [hello world] to contain an item equal to "goodbye", but it did not`,
 				)
 			})
 			t.Run("all caps", func(t *testing.T) {
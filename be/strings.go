@@ -14,7 +14,7 @@ import (
 func Len(matcher expect.Matcher[int]) expect.Matcher[string] {
 	return func(in string) expect.MatchResult {
 		result := matcher(len(in))
-		result.Description = fmt.Sprintf("have length %v", result.Description)
+		result = result.WithDescription("have length " + Complement(result))
 		return result
 	}
 }
@@ -22,7 +22,7 @@ func Len(matcher expect.Matcher[int]) expect.Matcher[string] {
 // AllCaps will check if a string is in all caps.
 func AllCaps(in string) expect.MatchResult {
 	return expect.MatchResult{
-		Description: "in all caps",
+		Description: "be in all caps",
 		Matches:     strings.ToUpper(in) == in,
 		But:         "it was not in all caps",
 	}
@@ -34,6 +34,8 @@ func Substring(substring string) expect.Matcher[string] {
 		return expect.MatchResult{
 			Description: fmt.Sprintf("contain %q", substring),
 			Matches:     strings.Contains(in, substring),
+			But:         fmt.Sprintf("it was %q", in),
+			NegatedBut:  fmt.Sprintf("it contained %q", substring),
 		}
 	}
 }
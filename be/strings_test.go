@@ -24,7 +24,7 @@ func ExampleAllCaps_fail() {
 	expect.It(t, "hello").To(be.AllCaps)
 
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected hello to in all caps, but it was not in all caps]
+	// Output: Test failed: [expected hello to be in all caps, but it was not in all caps]
 }
 
 func ExampleLen() {
@@ -42,7 +42,7 @@ func ExampleLen_fail() {
 	expect.It(t, "hello").To(be.Len(be.Eq(4)))
 
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected hello to have length be equal to 4, but it was 5]
+	// Output: Test failed: [expected hello to have length equal to 4, but it was 5]
 }
 
 func ExampleSubstring() {
@@ -60,7 +60,7 @@ func ExampleSubstring_fail() {
 	expect.It(t, "hello").To(be.Substring("goodbye"))
 
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected hello to contain "goodbye"]
+	// Output: Test failed: [expected hello to contain "goodbye", but it was "hello"]
 }
 
 func Example() {
@@ -85,7 +85,7 @@ func TestStringMatchers(t *testing.T) {
 		t.Run("failing", func(t *testing.T) {
 			spyTB := &expect.SpyTB{}
 			expect.It(spyTB, "goodbye").To(be.Len(be.Eq(5)))
-			expect.It(t, spyTB).To(spytb.Error("expected goodbye to have length be equal to 5, but it was 7"))
+			expect.It(t, spyTB).To(spytb.Error("expected goodbye to have length equal to 5, but it was 7"))
 		})
 	})
 }
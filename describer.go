@@ -0,0 +1,95 @@
+package expect
+
+// Describer describes what a matcher expects before it is run, and how a value fails to match
+// it, like Hamcrest's describeTo and describeMismatch. Describe reads as the end of "expected
+// the subject to …", and DescribeMismatch as the end of "…, but …".
+type Describer[T any] interface {
+	Describe() string
+	DescribeMismatch(got T) string
+}
+
+// DescribedMatcher is a matcher written as a type rather than a func. Adapt turns it into a
+// Matcher.
+type DescribedMatcher[T any] interface {
+	Describer[T]
+	Match(got T) bool
+}
+
+// NegationDescriber is optionally implemented by a DescribedMatcher to word its negation for
+// be.Not, as MatchResult.NegatedDescription and NegatedBut do for a Matcher. DescribeNegation
+// replaces "not " and the description, and DescribeMatch explains why got matched.
+type NegationDescriber[T any] interface {
+	DescribeNegation() string
+	DescribeMatch(got T) string
+}
+
+// Adapt turns a DescribedMatcher into a Matcher. DescribeMismatch is only called when Match
+// fails, and DescribeMatch of a NegationDescriber when it passes.
+func Adapt[T any](m DescribedMatcher[T]) Matcher[T] {
+	return func(got T) MatchResult {
+		result := MatchResult{
+			Description: m.Describe(),
+			Matches:     m.Match(got),
+		}
+		if !result.Matches {
+			result.But = m.DescribeMismatch(got)
+		}
+		if n, ok := m.(NegationDescriber[T]); ok {
+			result.NegatedDescription = n.DescribeNegation()
+			if result.Matches {
+				result.NegatedBut = n.DescribeMatch(got)
+			}
+		}
+		return result
+	}
+}
+
+// Described is a Matcher with its description known up front, so it can be listed without
+// running it. Mismatch optionally replaces the matcher's But.
+type Described[T any] struct {
+	Description string
+	Matcher     Matcher[T]
+	Mismatch    func(got T) string
+}
+
+// Describe gives a Matcher a description up front, which replaces the one it gives when run.
+func Describe[T any](description string, matcher Matcher[T]) Described[T] {
+	return Described[T]{Description: description, Matcher: matcher}
+}
+
+// WithMismatch customizes how a mismatch is described.
+func (d Described[T]) WithMismatch(mismatch func(got T) string) Described[T] {
+	d.Mismatch = mismatch
+	return d
+}
+
+// Describe is the description given up front.
+func (d Described[T]) Describe() string {
+	return d.Description
+}
+
+// DescribeMismatch runs the matcher and describes why it failed.
+func (d Described[T]) DescribeMismatch(got T) string {
+	if d.Mismatch != nil {
+		return d.Mismatch(got)
+	}
+	return d.Matcher(got).But
+}
+
+// Match runs the matcher.
+func (d Described[T]) Match(got T) bool {
+	return d.Matcher(got).Matches
+}
+
+// ToMatcher returns a Matcher that runs the wrapped matcher once and reports it with the
+// description given up front, see MatchResult.WithDescription. Use it in place of Adapt, which
+// would run it again to describe a mismatch.
+func (d Described[T]) ToMatcher() Matcher[T] {
+	return func(got T) MatchResult {
+		result := d.Matcher(got).WithDescription(d.Description)
+		if !result.Matches && d.Mismatch != nil {
+			result.But = d.Mismatch(got)
+		}
+		return result
+	}
+}
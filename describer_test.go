@@ -0,0 +1,105 @@
+package expect_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+type evenNumber struct{}
+
+func (evenNumber) Describe() string                { return "be even" }
+func (evenNumber) DescribeMismatch(got int) string { return fmt.Sprintf("%d is odd", got) }
+func (evenNumber) Match(got int) bool              { return got%2 == 0 }
+
+type positiveNumber struct{}
+
+func (positiveNumber) Describe() string                { return "be positive" }
+func (positiveNumber) DescribeMismatch(got int) string { return fmt.Sprintf("%d is not", got) }
+func (positiveNumber) Match(got int) bool              { return got > 0 }
+func (positiveNumber) DescribeNegation() string        { return "be zero or less" }
+func (positiveNumber) DescribeMatch(got int) string    { return fmt.Sprintf("%d is positive", got) }
+
+func ExampleAdapt() {
+	t := &expect.SpyTB{}
+
+	expect.It(t, 3).To(expect.Adapt[int](evenNumber{}))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected 3 to be even, but 3 is odd]
+}
+
+func ExampleDescribe() {
+	t := &expect.SpyTB{}
+
+	adult := expect.Describe("be an adult", be.Greater(17))
+	fmt.Println(adult.Describe())
+
+	expect.It(t, 12).To(adult.ToMatcher())
+	fmt.Printf("%s\n", t)
+	// Output: be an adult
+	// Test failed: [expected 12 to be an adult, but it was 12]
+}
+
+func TestDescribe(t *testing.T) {
+	t.Run("lists what is expected without running the matchers", func(t *testing.T) {
+		calls := 0
+		counting := func(got int) expect.MatchResult {
+			calls++
+			return be.Eq(4)(got)
+		}
+
+		describers := []expect.Describer[int]{expect.Describe("be four", counting), evenNumber{}}
+		var descriptions []string
+		for _, d := range describers {
+			descriptions = append(descriptions, d.Describe())
+		}
+
+		expect.It(t, fmt.Sprint(descriptions)).To(be.Eq("[be four be even]"))
+		expect.It(t, calls).To(be.Eq(0))
+	})
+
+	t.Run("customized mismatches", func(t *testing.T) {
+		four := expect.Describe("be four", be.Eq(4)).WithMismatch(func(got int) string {
+			return fmt.Sprintf("it was %d too many", got-4)
+		})
+
+		expect.It(t, four.DescribeMismatch(6)).To(be.Eq("it was 2 too many"))
+		spytb.VerifyFailingMatcher(t, 6, four.ToMatcher(), "expected 6 to be four, but it was 2 too many")
+		spytb.VerifyFailingMatcher(t, 6, expect.Adapt[int](four), "expected 6 to be four, but it was 2 too many")
+	})
+
+	t.Run("the matcher is run once", func(t *testing.T) {
+		calls := 0
+		counting := func(got int) expect.MatchResult {
+			calls++
+			return be.Eq(4)(got)
+		}
+
+		spytb.VerifyFailingMatcher(t, 5, expect.Describe("be four", counting).ToMatcher(), "expected 5 to be four, but it was 5")
+		expect.It(t, calls).To(be.Eq(1))
+	})
+
+	t.Run("negating a described matcher that words its negation", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, 3, be.Not(expect.Adapt[int](positiveNumber{})), "expected 3 to be zero or less, but 3 is positive")
+		spytb.VerifyFailingMatcher(t, -1, expect.Adapt[int](positiveNumber{}), "expected -1 to be positive, but -1 is not")
+	})
+
+	t.Run("negating uses the description given up front", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, 4, be.Not(expect.Adapt[int](evenNumber{})), "expected 4 to not be even, but it was even")
+		spytb.VerifyFailingMatcher(t, 20, be.Not(expect.Describe("be an adult", be.Greater(17)).ToMatcher()),
+			"expected 20 to not be an adult, but it was an adult",
+		)
+	})
+
+	t.Run("the description given up front replaces the tree", func(t *testing.T) {
+		result := expect.Describe("be small", be.Less(5).Or(be.Eq(7))).ToMatcher()(3)
+
+		expect.It(t, result.Tree == nil).To(be.Eq(true))
+		expect.It(t, result.DescriptionTree().Kind).To(be.Eq(expect.LeafNode))
+		expect.It(t, result.DescriptionTree().Description).To(be.Eq("be small"))
+		expect.It(t, result.NegatedBut).To(be.Eq(""))
+	})
+}
@@ -0,0 +1,158 @@
+package expect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NodeKind is what a DescriptionNode stands for.
+type NodeKind string
+
+const (
+	// LeafNode is a single matcher.
+	LeafNode NodeKind = "matcher"
+	// AllNode passes when all of its children pass, as with Matcher.And and MatchResult.Combine.
+	AllNode NodeKind = "all"
+	// AnyNode passes when any of its children pass, as with Matcher.Or.
+	AnyNode NodeKind = "any"
+	// NotNode passes when its only child fails, as with be.Not.
+	NotNode NodeKind = "not"
+	// ExactlyOneNode passes when one of its children passes, as with ExactlyOneOf.
+	ExactlyOneNode NodeKind = "exactly one"
+	// SkippedNode stands for matchers that were not run, as with the alternatives of
+	// Matcher.Or after the first that passes.
+	SkippedNode NodeKind = "skipped"
+)
+
+// DescriptionNode describes how a match went as a tree, so a failure of composed matchers
+// shows which branch failed. Each node carries the description and But of the matcher or
+// combination it stands for, and the actual value when the combinator knows it. It encodes
+// to JSON with encoding/json.
+type DescriptionNode struct {
+	Kind        NodeKind           `json:"kind"`
+	Description string             `json:"description,omitempty"`
+	Matches     bool               `json:"matches"`
+	Actual      string             `json:"actual,omitempty"`
+	But         string             `json:"but,omitempty"`
+	Children    []*DescriptionNode `json:"children,omitempty"`
+}
+
+// DescriptionTree returns the tree describing the result, or a leaf when the result was not
+// composed from others. A matcher that rewrites the Description, But or Matches of a
+// composed result, like be.Len, is described by a leaf too, as its Tree no longer fits.
+func (m MatchResult) DescriptionTree() *DescriptionNode {
+	if t := m.Tree; t != nil && t.Description == m.Description && t.But == m.But && t.Matches == m.Matches {
+		return t
+	}
+	return &DescriptionNode{Kind: LeafNode, Description: m.Description, Matches: m.Matches, But: m.But}
+}
+
+// WithActual records got as the actual value of the node, and of the nodes below it that
+// have none, and returns the node.
+func (n *DescriptionNode) WithActual(got any) *DescriptionNode {
+	actual := fmt.Sprintf("%v", got)
+	if s, ok := got.(string); ok {
+		actual = fmt.Sprintf("%q", s)
+	}
+	n.setActual(actual)
+	return n
+}
+
+func (n *DescriptionNode) setActual(actual string) {
+	if n.Actual != "" {
+		return
+	}
+	n.Actual = actual
+	for _, child := range n.Children {
+		child.setActual(actual)
+	}
+}
+
+// composeTree makes a node of the kind for the result from the children, flattening children
+// of the same kind so chains of And or Or make one level.
+func composeTree(kind NodeKind, result MatchResult, children ...*DescriptionNode) *DescriptionNode {
+	node := &DescriptionNode{Kind: kind, Description: result.Description, Matches: result.Matches, But: result.But}
+	for _, child := range children {
+		if child.Kind == kind && kind != NotNode {
+			node.Children = append(node.Children, child.Children...)
+			continue
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node
+}
+
+// String renders the tree as a single line, i.e. "be greater than 5 and (be even or be 7)".
+func (n *DescriptionNode) String() string {
+	switch n.Kind {
+	case AllNode, AnyNode:
+		parts := make([]string, 0, len(n.Children))
+		for _, child := range n.Children {
+			parts = append(parts, child.nested())
+		}
+		joiner := " and "
+		if n.Kind == AnyNode {
+			joiner = " or "
+		}
+		return strings.Join(parts, joiner)
+	case NotNode:
+		if len(n.Children) == 1 {
+			return "not " + n.Children[0].nested()
+		}
+	case ExactlyOneNode:
+		parts := make([]string, 0, len(n.Children))
+		for _, child := range n.Children {
+			parts = append(parts, child.String())
+		}
+		return "exactly one of " + strings.Join(parts, ", ")
+	}
+	return n.Description
+}
+
+func (n *DescriptionNode) nested() string {
+	if n.Kind == LeafNode || n.Kind == NotNode || len(n.Children) < 2 {
+		return n.String()
+	}
+	return "(" + n.String() + ")"
+}
+
+// Indented renders the tree with a line for each node, marking those that passed with ✓ and
+// those that failed with ✗, and those not run with -, i.e.
+//
+//	✗ all of
+//	  ✓ be greater than 5
+//	  ✗ be less than 10, but it was 12
+func (n *DescriptionNode) Indented() string {
+	var sb strings.Builder
+	n.indent(&sb, 0)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (n *DescriptionNode) indent(sb *strings.Builder, depth int) {
+	mark := "✗"
+	if n.Matches {
+		mark = "✓"
+	}
+	sb.WriteString(strings.Repeat("  ", depth))
+	switch n.Kind {
+	case AllNode:
+		fmt.Fprintf(sb, "%s all of\n", mark)
+	case AnyNode:
+		fmt.Fprintf(sb, "%s any of\n", mark)
+	case NotNode:
+		fmt.Fprintf(sb, "%s not\n", mark)
+	case ExactlyOneNode:
+		fmt.Fprintf(sb, "%s exactly one of\n", mark)
+	case SkippedNode:
+		fmt.Fprintf(sb, "- %s, not run\n", n.Description)
+	default:
+		sb.WriteString(mark + " " + n.Description)
+		if n.But != "" && !n.Matches {
+			sb.WriteString(", but " + n.But)
+		}
+		sb.WriteString("\n")
+	}
+	for _, child := range n.Children {
+		child.indent(sb, depth+1)
+	}
+}
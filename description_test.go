@@ -0,0 +1,105 @@
+package expect_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+)
+
+func ExampleDescriptionNode_Indented() {
+	result := be.Greater(5).And(be.Less(10).Or(be.Eq(12)), be.Not(be.Eq(11)))(11)
+
+	fmt.Println(result.DescriptionTree())
+	fmt.Println(result.DescriptionTree().Indented())
+	// Output: be greater than 5 and (be less than 10 or be equal to 12) and not be equal to 11
+	// ✗ all of
+	//   ✓ be greater than 5
+	//   ✗ any of
+	//     ✗ be less than 10, but it was 11
+	//     ✗ be equal to 12, but it was 11
+	//   ✗ not
+	//     ✓ be equal to 11
+}
+
+func ExampleDescriptionNode_json() {
+	result := be.Greater(5).And(be.Less(10))(11)
+
+	out, _ := json.MarshalIndent(result.DescriptionTree(), "", "  ")
+	fmt.Println(string(out))
+	// Output: {
+	//   "kind": "all",
+	//   "description": "be greater than 5 and be less than 10",
+	//   "matches": false,
+	//   "actual": "11",
+	//   "but": "it was 11",
+	//   "children": [
+	//     {
+	//       "kind": "matcher",
+	//       "description": "be greater than 5",
+	//       "matches": true,
+	//       "actual": "11",
+	//       "but": "it was 11"
+	//     },
+	//     {
+	//       "kind": "matcher",
+	//       "description": "be less than 10",
+	//       "matches": false,
+	//       "actual": "11",
+	//       "but": "it was 11"
+	//     }
+	//   ]
+	// }
+}
+
+func TestDescriptionTree(t *testing.T) {
+	t.Run("a single matcher is a leaf", func(t *testing.T) {
+		tree := be.Eq(5)(4).DescriptionTree()
+
+		expect.It(t, tree.Kind).To(be.Eq(expect.LeafNode))
+		expect.It(t, tree.String()).To(be.Eq("be equal to 5"))
+		expect.It(t, tree.Indented()).To(be.Eq("✗ be equal to 5, but it was 4"))
+	})
+
+	t.Run("chained ands are flattened", func(t *testing.T) {
+		tree := be.Greater(1).And(be.Greater(2)).And(be.Greater(3))(4).DescriptionTree()
+
+		expect.It(t, tree.Kind).To(be.Eq(expect.AllNode))
+		expect.It(t, len(tree.Children)).To(be.Eq(3))
+		expect.It(t, tree.Matches).To(be.Eq(true))
+	})
+
+	t.Run("combining the same buts does not repeat them", func(t *testing.T) {
+		result := be.Greater(5).And(be.Greater(6))(4)
+
+		expect.It(t, result.But).To(be.Eq("it was 4"))
+	})
+
+	t.Run("failures of several matchers leave the tree out", func(t *testing.T) {
+		spy := &expect.SpyTB{}
+		expect.It(spy, 4).To(be.Greater(5).And(be.Less(3)))
+
+		expect.It(t, fmt.Sprintf("%s", spy)).To(be.Eq("Test failed: [expected 4 to be greater than 5 and be less than 3, but it was 4]"))
+	})
+
+	t.Run("nodes have the actual value they were matched against", func(t *testing.T) {
+		tree := expect.AllOf(be.Len(be.Greater(3)), be.Substring("x").Or(be.AllCaps))("Sugar").DescriptionTree()
+		expect.It(t, tree.Actual).To(be.Eq(`"Sugar"`))
+		expect.It(t, tree.Children[0].Actual).To(be.Eq(`"Sugar"`))
+		expect.It(t, tree.Children[1].Children[1].Actual).To(be.Eq(`"Sugar"`))
+	})
+
+	t.Run("a matcher that rewrites a composed result is a leaf", func(t *testing.T) {
+		result := be.Len(be.Greater(5).And(be.Less(10)))("hi")
+		tree := result.DescriptionTree()
+
+		expect.It(t, tree.Kind).To(be.Eq(expect.LeafNode))
+		expect.It(t, tree.Description).To(be.Eq(result.Description))
+		expect.It(t, tree.But).To(be.Eq(result.But))
+
+		tree = be.Key("name", be.Eq("Pepper").Or(be.Eq("Salt")))(map[string]string{"name": "Sugar"}).DescriptionTree()
+		expect.It(t, tree.Kind).To(be.Eq(expect.LeafNode))
+	})
+}
@@ -12,6 +12,19 @@ type MatchResult struct {
 	But         string
 	SubjectName string
 	StackTrace  []string
+	// Tree describes the result when it was composed from others, i.e. by Combine. It is not
+	// part of Error, render it with DescriptionTree when the branches matter.
+	Tree *DescriptionNode
+	// NegatedDescription optionally describes the negation of the matcher, for be.Not, when
+	// "not " and the Description does not read well.
+	NegatedDescription string
+	// NegatedBut optionally explains why the negation of the matcher failed, that is, why it
+	// matched. be.Not works one out from the Description when it is not set.
+	NegatedBut string
+	// Detail is shown on the lines after a failure, i.e. a dump of the HTTP response that
+	// failed to match. It is only rendered when the failure is. A result composed from others
+	// keeps the first detail given, so it is shown once.
+	Detail fmt.Stringer
 }
 
 func (m MatchResult) Error() string {
@@ -21,6 +34,10 @@ func (m MatchResult) Error() string {
 	} else {
 		sb.WriteString(fmt.Sprintf("expected %+v to %+v", m.SubjectName, m.Description))
 	}
+	if m.Detail != nil {
+		sb.WriteString("\n")
+		sb.WriteString(m.Detail.String())
+	}
 	if len(m.StackTrace) > 0 {
 		sb.WriteString("\nError Trace:\n")
 		for _, trace := range m.StackTrace {
@@ -32,6 +49,17 @@ func (m MatchResult) Error() string {
 	return sb.String()
 }
 
+// WithDescription returns the result with a new description, for a matcher that reports the
+// result of another under its own, like be.Len. The Tree and the negated description and But
+// were worded for the old description, so they are dropped.
+func (m MatchResult) WithDescription(description string) MatchResult {
+	m.Description = description
+	m.Tree = nil
+	m.NegatedDescription = ""
+	m.NegatedBut = ""
+	return m
+}
+
 // Zero returns true if the MatchResult is the zero value.
 func (m MatchResult) Zero() bool {
 	return m.Description == "" && m.But == "" && !m.Matches
@@ -44,6 +72,9 @@ func (m MatchResult) Combine(other MatchResult) MatchResult {
 	}
 	
 	but := m.But + " and " + other.But
+	if m.But == other.But {
+		but = m.But
+	}
 	
 	if m.Matches && other.Matches {
 		but = ""
@@ -57,10 +88,20 @@ func (m MatchResult) Combine(other MatchResult) MatchResult {
 		but = m.But
 	}
 	
-	return MatchResult{
+	result := MatchResult{
 		Description: m.Description + " and " + other.Description,
 		Matches:     m.Matches && other.Matches,
 		But:         but,
 		SubjectName: m.SubjectName,
+		Detail:      m.Detail,
+	}
+	if result.Detail == nil {
+		result.Detail = other.Detail
+	}
+	result.NegatedDescription = "not (" + result.Description + ")"
+	if result.Matches {
+		result.NegatedBut = "it matched: " + result.Description
 	}
+	result.Tree = composeTree(AllNode, result, m.DescriptionTree(), other.DescriptionTree())
+	return result
 }
@@ -1,41 +1,244 @@
 package expect
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type Matcher[T any] func(T) MatchResult
 
-// Or combines matchers with a boolean OR.
+// Or combines matchers with a boolean OR. See AnyOf.
 func (m Matcher[T]) Or(matchers ...Matcher[T]) Matcher[T] {
+	return AnyOf(append([]Matcher[T]{m}, matchers...)...)
+}
+
+// And combines matchers with a boolean AND. See AllOf.
+func (m Matcher[T]) And(matchers ...Matcher[T]) Matcher[T] {
+	return AllOf(append([]Matcher[T]{m}, matchers...)...)
+}
+
+// AllOf passes when all the matchers pass. Every matcher is run so a failure lists all that
+// failed.
+func AllOf[T any](matchers ...Matcher[T]) Matcher[T] {
 	return func(got T) MatchResult {
-		result := m(got)
-		
-		for _, matcher := range matchers {
-			result.Description += " or " + matcher(got).Description
+		if len(matchers) == 0 {
+			return MatchResult{Description: "match all of no matchers", Matches: true}
 		}
-		
-		if result.Matches {
-			return result
+
+		result := matchers[0](got)
+		for _, matcher := range matchers[1:] {
+			result = result.Combine(matcher(got))
 		}
-		
-		for _, matcher := range matchers {
-			if r := matcher(got); r.Matches {
+		if result.Tree != nil {
+			result.Tree.WithActual(got)
+		}
+		return result
+	}
+}
+
+// AnyOf passes when any of the matchers pass. The matchers are run in order, stopping at the
+// first that passes, so each is run at most once and those not run are described as "N more".
+// When none pass the But lists why each failed, i.e. "neither: it was 4; nor: it was not in
+// all caps".
+func AnyOf[T any](matchers ...Matcher[T]) Matcher[T] {
+	return func(got T) MatchResult {
+		if len(matchers) == 0 {
+			return MatchResult{Description: "match any of no matchers", But: "there were none to match"}
+		}
+		if len(matchers) == 1 {
+			return matchers[0](got)
+		}
+
+		var (
+			result       MatchResult
+			descriptions []string
+			buts         []string
+			children     []*DescriptionNode
+		)
+		for i, matcher := range matchers {
+			r := matcher(got)
+			if result.SubjectName == "" {
+				result.SubjectName = r.SubjectName
+			}
+			if result.Detail == nil {
+				result.Detail = r.Detail
+			}
+			descriptions = append(descriptions, r.Description)
+			children = append(children, r.DescriptionTree())
+
+			if r.Matches {
 				result.Matches = true
-				return result
+				result.NegatedBut = "it matched: " + r.Description
+				if rest := len(matchers) - i - 1; rest > 0 {
+					more := fmt.Sprintf("%d more", rest)
+					descriptions = append(descriptions, more)
+					children = append(children, &DescriptionNode{Kind: SkippedNode, Description: more})
+				}
+				break
 			}
+			buts = append(buts, failureOf(r))
+		}
+
+		result.Description = strings.Join(descriptions, " or ")
+		result.NegatedDescription = "not (" + result.Description + ")"
+		if !result.Matches {
+			result.But = neitherNor(buts)
 		}
-		
+		result.Tree = composeTree(AnyNode, result, children...).WithActual(got)
 		return result
 	}
 }
 
-// And combines matchers with a boolean AND.
-func (m Matcher[T]) And(matchers ...Matcher[T]) Matcher[T] {
+// NoneOf passes when none of the matchers pass. Every matcher is run so a failure lists all
+// that passed.
+func NoneOf[T any](matchers ...Matcher[T]) Matcher[T] {
 	return func(got T) MatchResult {
-		result := m(got)
-		
+		if len(matchers) == 0 {
+			return MatchResult{Description: "match none of no matchers", Matches: true}
+		}
+
+		result := MatchResult{Matches: true}
+		var (
+			descriptions []string
+			matched      []string
+			buts         []string
+			children     []*DescriptionNode
+		)
 		for _, matcher := range matchers {
 			r := matcher(got)
-			result = result.Combine(r)
+			if result.SubjectName == "" {
+				result.SubjectName = r.SubjectName
+			}
+			if result.Detail == nil {
+				result.Detail = r.Detail
+			}
+			descriptions = append(descriptions, r.Description)
+			children = append(children, r.DescriptionTree())
+
+			if r.Matches {
+				result.Matches = false
+				matched = append(matched, r.Description)
+				continue
+			}
+			buts = append(buts, failureOf(r))
 		}
-		
+
+		result.Description = "neither " + strings.Join(descriptions, " nor ")
+		result.NegatedDescription = strings.Join(descriptions, " or ")
+		if result.Matches {
+			result.NegatedBut = neitherNor(buts)
+		} else {
+			result.But = "it matched: " + strings.Join(matched, " and ")
+		}
+		anyOf := composeTree(AnyNode, MatchResult{Matches: !result.Matches}, children...)
+		result.Tree = composeTree(NotNode, result, anyOf).WithActual(got)
+		return result
+	}
+}
+
+// ExactlyOneOf passes when one, and only one, of the matchers passes. Every matcher is run so
+// a failure lists all that passed, or why each failed when none did.
+func ExactlyOneOf[T any](matchers ...Matcher[T]) Matcher[T] {
+	return func(got T) MatchResult {
+		if len(matchers) == 0 {
+			return MatchResult{Description: "match exactly one of no matchers", But: "there were none to match"}
+		}
+
+		var (
+			result       MatchResult
+			descriptions []string
+			matched      []string
+			buts         []string
+			children     []*DescriptionNode
+		)
+		for _, matcher := range matchers {
+			r := matcher(got)
+			if result.SubjectName == "" {
+				result.SubjectName = r.SubjectName
+			}
+			if result.Detail == nil {
+				result.Detail = r.Detail
+			}
+			descriptions = append(descriptions, r.Description)
+			children = append(children, r.DescriptionTree())
+
+			if r.Matches {
+				matched = append(matched, r.Description)
+				continue
+			}
+			buts = append(buts, failureOf(r))
+		}
+
+		result.Description = "match exactly one of: " + strings.Join(descriptions, ", ")
+		result.Matches = len(matched) == 1
+		switch {
+		case len(matched) == 1:
+			result.NegatedBut = "it matched: " + matched[0]
+		case len(matched) > 1:
+			result.But = fmt.Sprintf("it matched %d: %s", len(matched), strings.Join(matched, " and "))
+		default:
+			result.But = neitherNor(buts)
+		}
+		result.Tree = composeTree(ExactlyOneNode, result, children...).WithActual(got)
 		return result
 	}
 }
+
+// failureOf is why the result failed, for listing alongside the failures of other matchers.
+func failureOf(r MatchResult) string {
+	if r.But != "" {
+		return r.But
+	}
+	return "it did not " + r.Description
+}
+
+// neitherNor lists the reasons each alternative failed, i.e. "neither: it was 4; nor: it was not
+// in all caps". A reason shared by several alternatives is given once, saying which of them
+// gave it, i.e. "neither: it was 4 (alternatives 1 and 3); nor: it was not in all caps".
+func neitherNor(buts []string) string {
+	var (
+		distinct     []string
+		alternatives = map[string][]int{}
+	)
+	for i, but := range buts {
+		if _, ok := alternatives[but]; !ok {
+			distinct = append(distinct, but)
+		}
+		alternatives[but] = append(alternatives[but], i+1)
+	}
+	if len(distinct) == 1 {
+		switch len(buts) {
+		case 1:
+			return buts[0]
+		case 2:
+			return buts[0] + " for both alternatives"
+		default:
+			return fmt.Sprintf("%s for all %d alternatives", buts[0], len(buts))
+		}
+	}
+
+	var sb strings.Builder
+	for i, but := range distinct {
+		if i == 0 {
+			sb.WriteString("neither: ")
+		} else {
+			sb.WriteString("; nor: ")
+		}
+		sb.WriteString(but)
+		if positions := alternatives[but]; len(positions) > 1 {
+			sb.WriteString(" (alternatives " + listPositions(positions) + ")")
+		}
+	}
+	return sb.String()
+}
+
+// listPositions lists the positions of alternatives, i.e. "1, 2 and 4".
+func listPositions(positions []int) string {
+	parts := make([]string, 0, len(positions))
+	for _, p := range positions {
+		parts = append(parts, strconv.Itoa(p))
+	}
+	last := len(parts) - 1
+	return strings.Join(parts[:last], ", ") + " and " + parts[last]
+}
@@ -0,0 +1,118 @@
+package expect_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jsteenb2/expect"
+	"github.com/jsteenb2/expect/be"
+	"github.com/jsteenb2/expect/spytb"
+)
+
+func ExampleAnyOf() {
+	t := &expect.SpyTB{}
+
+	expect.It(t, "hello").To(expect.AnyOf(be.Eq("goodbye"), be.AllCaps))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected "hello" to be equal to "goodbye" or be in all caps, but neither: it was "hello"; nor: it was not in all caps]
+}
+
+func ExampleNoneOf() {
+	t := &expect.SpyTB{}
+
+	expect.It(t, 4).To(expect.NoneOf(be.Eq(3), be.Eq(4)))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected 4 to neither be equal to 3 nor be equal to 4, but it matched: be equal to 4]
+}
+
+func ExampleExactlyOneOf() {
+	t := &expect.SpyTB{}
+
+	expect.It(t, 12).To(expect.ExactlyOneOf(be.Greater(10), be.Less(20)))
+	fmt.Printf("%s\n", t)
+	// Output: Test failed: [expected 12 to match exactly one of: be greater than 10, be less than 20, but it matched 2: be greater than 10 and be less than 20]
+}
+
+func TestAnyOf(t *testing.T) {
+	t.Run("runs each matcher once and stops at the first that passes", func(t *testing.T) {
+		calls := map[string]int{}
+		counting := func(name string, matches bool) expect.Matcher[int] {
+			return func(int) expect.MatchResult {
+				calls[name]++
+				return expect.MatchResult{Description: "be " + name, Matches: matches, But: "it was not " + name}
+			}
+		}
+
+		expect.It(t, 1).To(counting("a", false).Or(counting("b", true), counting("c", true)))
+		expect.It(t, fmt.Sprint(calls)).To(be.Eq("map[a:1 b:1]"))
+	})
+
+	t.Run("lists why each alternative failed", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, "hello", be.Eq("goodbye").Or(be.AllCaps, be.Eq("hi")),
+			`expected "hello" to be equal to "goodbye" or be in all caps or be equal to "hi", but neither: it was "hello" (alternatives 1 and 3); nor: it was not in all caps`,
+		)
+	})
+
+	t.Run("gives a reason shared by every alternative once", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, 4, be.Eq(3).Or(be.Eq(5), be.Greater(10)),
+			"expected 4 to be equal to 3 or be equal to 5 or be greater than 10, but it was 4 for all 3 alternatives",
+		)
+	})
+
+	t.Run("a single alternative reads as itself", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, 4, expect.AnyOf(be.Eq(3)),
+			"expected 4 to be equal to 3, but it was 4",
+		)
+	})
+
+	t.Run("negated", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, 3, be.Not(be.Eq(3).Or(be.Eq(4), be.Eq(5))),
+			"expected 3 to not (be equal to 3 or 2 more), but it matched: be equal to 3",
+		)
+	})
+
+	t.Run("describes the alternatives not run in the tree", func(t *testing.T) {
+		tree := be.Eq(3).Or(be.Eq(4), be.Eq(5))(3).DescriptionTree()
+		expect.It(t, tree.String()).To(be.Eq("be equal to 3 or 2 more"))
+		expect.It(t, tree.Indented()).To(be.Eq("✓ any of\n  ✓ be equal to 3\n  - 2 more, not run"))
+	})
+
+	t.Run("no matchers never pass", func(t *testing.T) {
+		spytb.VerifyFailingMatcher(t, 3, expect.AnyOf[int](),
+			"but there were none to match",
+		)
+	})
+}
+
+func TestAllOf(t *testing.T) {
+	expect.It(t, 5).To(expect.AllOf(be.Greater(1), be.Less(10)), expect.AllOf[int]())
+
+	spytb.VerifyFailingMatcher(t, 11, expect.AllOf(be.Greater(1), be.Less(10)),
+		"expected 11 to be greater than 1 and be less than 10, but it was 11",
+	)
+}
+
+func TestNoneOf(t *testing.T) {
+	expect.It(t, 5).To(expect.NoneOf(be.Eq(3), be.Eq(4)), expect.NoneOf[int]())
+
+	spytb.VerifyFailingMatcher(t, 3, expect.NoneOf(be.Eq(3), be.Less(4)),
+		"expected 3 to neither be equal to 3 nor be less than 4, but it matched: be equal to 3 and be less than 4",
+	)
+	spytb.VerifyFailingMatcher(t, 5, be.Not(expect.NoneOf(be.Eq(3), be.Eq(4))),
+		"expected 5 to be equal to 3 or be equal to 4, but it was 5 for both alternatives",
+	)
+}
+
+func TestExactlyOneOf(t *testing.T) {
+	expect.It(t, 12).To(expect.ExactlyOneOf(be.Greater(10), be.Eq(0)))
+
+	spytb.VerifyFailingMatcher(t, 5, expect.ExactlyOneOf(be.Greater(10), be.Eq(0)),
+		"expected 5 to match exactly one of: be greater than 10, be equal to 0, but it was 5 for both alternatives",
+	)
+	spytb.VerifyFailingMatcher(t, 5, expect.ExactlyOneOf[int](),
+		"expected 5 to match exactly one of no matchers, but there were none to match",
+	)
+	spytb.VerifyFailingMatcher(t, 12, be.Not(expect.ExactlyOneOf(be.Greater(10), be.Eq(0))),
+		"but it matched: be greater than 10",
+	)
+}
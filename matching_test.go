@@ -23,7 +23,7 @@ func ExampleMatcher_Or() {
 
 	expect.It(t, tshirt).To(HaveColour("blue").Or(HaveColour("red")))
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected the t-shirt to have colour "blue" or have colour "red", but it was "yellow"]
+	// Output: Test failed: [expected the t-shirt to have colour "blue" or have colour "red", but it was "yellow" for both alternatives]
 }
 
 func ExampleNot() {
@@ -33,7 +33,7 @@ func ExampleNot() {
 
 	expect.It(t, tshirt).To(be.Not(HaveColour("yellow")))
 	fmt.Printf("%s\n", t)
-	// Output: Test failed: [expected the t-shirt to not have colour "yellow"]
+	// Output: Test failed: [expected the t-shirt to not have colour "yellow", but it had colour "yellow"]
 }
 
 func ExampleMatcher_And() {
@@ -109,7 +109,7 @@ func TestMatching(t *testing.T) {
 			result2 := be.AllCaps(someString)
 
 			expected := expect.MatchResult{
-				Description: `have length be equal to 5 and in all caps`,
+				Description: `have length equal to 5 and be in all caps`,
 				Matches:     false,
 				But:         "it was 7 and it was not in all caps",
 			}
@@ -124,7 +124,7 @@ func TestMatching(t *testing.T) {
 			result2 := be.AllCaps(someString)
 
 			expected := expect.MatchResult{
-				Description: `have length be equal to 5 and in all caps`,
+				Description: `have length equal to 5 and be in all caps`,
 				Matches:     true,
 			}
 
@@ -138,7 +138,7 @@ func TestMatching(t *testing.T) {
 			result2 := be.AllCaps(someString)
 
 			expected := expect.MatchResult{
-				Description: `have length be equal to 5 and in all caps`,
+				Description: `have length equal to 5 and be in all caps`,
 				Matches:     false,
 				But:         "it was not in all caps",
 			}
@@ -153,7 +153,7 @@ func TestMatching(t *testing.T) {
 			result2 := be.AllCaps(someString)
 
 			expected := expect.MatchResult{
-				Description: `have length be equal to 5 and in all caps`,
+				Description: `have length equal to 5 and be in all caps`,
 				Matches:     false,
 				But:         "it was 7",
 			}
@@ -161,9 +161,30 @@ func TestMatching(t *testing.T) {
 			actual := result1.Combine(result2)
 			expect.It(t, actual).To(spytb.HaveMatchResult(expected))
 		})
+
+		t.Run("the detail is shown once", func(t *testing.T) {
+			detailed := func(description string) expect.Matcher[int] {
+				return func(int) expect.MatchResult {
+					return expect.MatchResult{Description: description, But: "it did not", Detail: detail("the details")}
+				}
+			}
+
+			spytb.VerifyFailingMatcher(t, 1, detailed("be odd").And(detailed("be even")),
+				"expected 1 to be odd and be even, but it did not\nthe details",
+			)
+			spytb.VerifyFailingMatcher(t, 1, detailed("be odd").Or(detailed("be even")),
+				"expected 1 to be odd or be even, but it did not for both alternatives\nthe details",
+			)
+		})
 	})
 }
 
+type detail string
+
+func (d detail) String() string {
+	return string(d)
+}
+
 type TShirt struct {
 	Colour string
 }